@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sync"
 
 	"github.com/10gen/stitch-cli/auth"
 	"github.com/10gen/stitch-cli/user"
@@ -19,8 +23,16 @@ const DefaultAtlasBaseURL = "https://cloud.mongodb.com"
 const (
 	adminBaseURL     = "/api/admin/v3.0"
 	authSessionRoute = adminBaseURL + "/auth/session"
+
+	clientBaseURL        = "/api/client/v2.0"
+	incomingWebhookRoute = clientBaseURL + "/app/%s/service/%s/incoming_webhook/%s"
 )
 
+// IncomingWebhookPath returns the public path of an incoming webhook of the app with the given client App ID
+func IncomingWebhookPath(clientAppID, serviceName, webhookName string) string {
+	return fmt.Sprintf(incomingWebhookRoute, url.PathEscape(clientAppID), url.PathEscape(serviceName), url.PathEscape(webhookName))
+}
+
 // Client represents something that is capable of making HTTP requests
 type Client interface {
 	ExecuteRequest(method, path string, options RequestOptions) (*http.Response, error)
@@ -65,10 +77,13 @@ func NewAuthClient(client Client, user *user.User) *AuthClient {
 	}
 }
 
-// AuthClient is a Client that is aware of a User's auth credentials
+// AuthClient is a Client that is aware of a User's auth credentials. It is safe for concurrent use: the
+// access token is read and refreshed under a lock, and refreshed once for all the requests it expired for
 type AuthClient struct {
 	Client
 	user *user.User
+
+	mu sync.Mutex
 }
 
 // RefreshAuth makes a call to the session endpoint using the user's refresh token in order to obtain a new access token
@@ -97,32 +112,74 @@ func (ac *AuthClient) RefreshAuth() (auth.Response, error) {
 	return authResponse, nil
 }
 
-// ExecuteRequest makes a call to the provided path, supplying the user's access token
+// ExecuteRequest makes a call to the provided path, supplying the user's access token. If the access token
+// has expired, it is refreshed and the request is made again with the same headers and body
 func (ac *AuthClient) ExecuteRequest(method, path string, options RequestOptions) (*http.Response, error) {
-	if options.Header == nil {
-		options.Header = http.Header{}
+	var body []byte
+	if options.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(options.Body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %s", err)
+		}
 	}
 
-	options.Header.Add("Authorization", "Bearer "+ac.user.AccessToken)
-
-	res, err := ac.Client.ExecuteRequest(method, path, options)
+	accessToken := ac.accessToken()
+	res, err := ac.Client.ExecuteRequest(method, path, authorizedRequestOptions(options.Header, body, accessToken))
 	if err != nil {
 		return nil, err
 	}
 
 	if res.StatusCode == http.StatusUnauthorized {
 		res.Body.Close()
-		authResponse, err := ac.RefreshAuth()
+		accessToken, err := ac.refreshAccessToken(accessToken)
 		if err != nil {
 			return nil, err
 		}
 
-		return ac.Client.ExecuteRequest(method, path, RequestOptions{
-			Header: http.Header{
-				"Authorization": []string{"Bearer " + authResponse.AccessToken},
-			},
-		})
+		return ac.Client.ExecuteRequest(method, path, authorizedRequestOptions(options.Header, body, accessToken))
 	}
 
 	return res, err
 }
+
+// authorizedRequestOptions returns the options for a single attempt at a request: a copy of the caller's
+// headers with the access token added, and a fresh reader over the buffered body
+func authorizedRequestOptions(header http.Header, body []byte, accessToken string) RequestOptions {
+	options := RequestOptions{Header: http.Header{}}
+	for key, values := range header {
+		options.Header[key] = append([]string(nil), values...)
+	}
+	options.Header.Set("Authorization", "Bearer "+accessToken)
+
+	if body != nil {
+		options.Body = bytes.NewReader(body)
+	}
+
+	return options
+}
+
+func (ac *AuthClient) accessToken() string {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	return ac.user.AccessToken
+}
+
+// refreshAccessToken obtains a new access token to replace expiredToken and keeps it for later requests.
+// If another request already replaced expiredToken, its replacement is returned instead
+func (ac *AuthClient) refreshAccessToken(expiredToken string) (string, error) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.user.AccessToken != expiredToken {
+		return ac.user.AccessToken, nil
+	}
+
+	authResponse, err := ac.RefreshAuth()
+	if err != nil {
+		return "", err
+	}
+
+	ac.user.AccessToken = authResponse.AccessToken
+	return ac.user.AccessToken, nil
+}
@@ -1,7 +1,10 @@
 package api_test
 
 import (
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/10gen/stitch-cli/api"
@@ -61,4 +64,97 @@ func TestAuthClientExecuteRequest(t *testing.T) {
 		u.So(t, client.RequestData[2].Path, gc.ShouldEqual, "/somewhere")
 		u.So(t, client.RequestData[2].Options.Header.Get("Authorization"), gc.ShouldEqual, "Bearer new.access.token")
 	})
+
+	t.Run("on unauthorized should make the request again with the same headers and body", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusUnauthorized,
+				Body:       u.NewAuthResponseBody(auth.Response{}),
+			},
+			{
+				StatusCode: http.StatusCreated,
+				Body: u.NewAuthResponseBody(auth.Response{
+					AccessToken: "new.access.token",
+				}),
+			},
+			{
+				StatusCode: http.StatusNoContent,
+				Body:       u.NewAuthResponseBody(auth.Response{}),
+			},
+		})
+
+		authClient := api.NewAuthClient(client, &user.User{AccessToken: "old.access.token", RefreshToken: "my.refresh.token"})
+
+		header := http.Header{"Content-Type": []string{"application/json"}}
+		_, err := authClient.ExecuteRequest(http.MethodPut, "/somewhere", api.RequestOptions{
+			Body:   strings.NewReader(`{"name":"flag"}`),
+			Header: header,
+		})
+		u.So(t, err, gc.ShouldBeNil)
+
+		u.So(t, len(client.RequestData), gc.ShouldEqual, 3)
+		for _, i := range []int{0, 2} {
+			body, err := ioutil.ReadAll(client.RequestData[i].Options.Body)
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, string(body), gc.ShouldEqual, `{"name":"flag"}`)
+			u.So(t, client.RequestData[i].Options.Header.Get("Content-Type"), gc.ShouldEqual, "application/json")
+		}
+		u.So(t, client.RequestData[0].Options.Header.Get("Authorization"), gc.ShouldEqual, "Bearer old.access.token")
+		u.So(t, client.RequestData[2].Options.Header.Get("Authorization"), gc.ShouldEqual, "Bearer new.access.token")
+		u.So(t, header, gc.ShouldResemble, http.Header{"Content-Type": []string{"application/json"}})
+	})
+
+	t.Run("should refresh the token once for concurrent requests", func(t *testing.T) {
+		client := &tokenCheckingClient{accessToken: "new.access.token"}
+		authClient := api.NewAuthClient(client, &user.User{AccessToken: "old.access.token", RefreshToken: "my.refresh.token"})
+
+		statuses := make(chan int, 10)
+		var wg sync.WaitGroup
+		for i := 0; i < cap(statuses); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := authClient.ExecuteRequest(http.MethodGet, "/somewhere", api.RequestOptions{})
+				if err != nil {
+					statuses <- 0
+					return
+				}
+				statuses <- res.StatusCode
+			}()
+		}
+		wg.Wait()
+		close(statuses)
+
+		for status := range statuses {
+			u.So(t, status, gc.ShouldEqual, http.StatusOK)
+		}
+		u.So(t, client.refreshes, gc.ShouldEqual, 1)
+	})
+}
+
+// tokenCheckingClient is an api.Client that may be used concurrently. It only accepts requests bearing
+// accessToken, which it hands out when the auth session is refreshed
+type tokenCheckingClient struct {
+	accessToken string
+
+	mu        sync.Mutex
+	refreshes int
+}
+
+func (tcc *tokenCheckingClient) ExecuteRequest(method, path string, options api.RequestOptions) (*http.Response, error) {
+	if path == "/api/admin/v3.0/auth/session" {
+		tcc.mu.Lock()
+		tcc.refreshes++
+		tcc.mu.Unlock()
+
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       u.NewAuthResponseBody(auth.Response{AccessToken: tcc.accessToken}),
+		}, nil
+	}
+
+	if options.Header.Get("Authorization") != "Bearer "+tcc.accessToken {
+		return &http.Response{StatusCode: http.StatusUnauthorized, Body: u.NewAuthResponseBody(auth.Response{})}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: u.NewAuthResponseBody(auth.Response{})}, nil
 }
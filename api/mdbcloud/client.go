@@ -26,12 +26,25 @@ type Group struct {
 	Name string `json:"name"`
 }
 
+type clustersResponse struct {
+	Results []Cluster `json:"results"`
+}
+
+// Cluster represents a mongodb atlas cluster
+type Cluster struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	StateName      string `json:"stateName"`
+	MongoDBVersion string `json:"mongoDBVersion"`
+}
+
 // Client provides access to the MongoDB Cloud Manager APIs
 type Client interface {
 	WithAuth(username, apiKey string) Client
 	Groups() ([]Group, error)
 	GroupByName(string) (*Group, error)
 	DeleteDatabaseUser(groupID, username string) error
+	Clusters(groupID string) ([]Cluster, error)
 }
 
 type simpleClient struct {
@@ -178,3 +191,32 @@ func (client *simpleClient) DeleteDatabaseUser(groupID, username string) error {
 	}
 	return nil
 }
+
+// Clusters returns the Atlas clusters of the Group with the provided ID
+func (client *simpleClient) Clusters(groupID string) ([]Cluster, error) {
+	resp, err := client.do(
+		http.MethodGet,
+		fmt.Sprintf("%s/api/atlas/v1.0/groups/%s/clusters", client.atlasAPIBaseURL, groupID),
+		nil,
+		true,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("no project found with ID %s", groupID)
+		}
+		return nil, fmt.Errorf("failed to fetch the clusters of project %s: %s", groupID, resp.Status)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	var clustersResponse clustersResponse
+	if err := dec.Decode(&clustersResponse); err != nil {
+		return nil, err
+	}
+
+	return clustersResponse.Results, nil
+}
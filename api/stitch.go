@@ -8,20 +8,48 @@ import (
 	"io"
 	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/10gen/stitch-cli/auth"
 	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
 )
 
 const (
-	authProviderLoginRoute = adminBaseURL + "/auth/providers/%s/login"
-	appExportRoute         = adminBaseURL + "/groups/%s/apps/%s/export?template=%t"
-	appImportRoute         = adminBaseURL + "/groups/%s/apps/%s/import"
-	appsByGroupIDRoute     = adminBaseURL + "/groups/%s/apps"
-	userProfileRoute       = adminBaseURL + "/auth/profile"
+	authProviderLoginRoute  = adminBaseURL + "/auth/providers/%s/login"
+	appExportRoute          = adminBaseURL + "/groups/%s/apps/%s/export?template=%t"
+	appImportRoute          = adminBaseURL + "/groups/%s/apps/%s/import"
+	appsByGroupIDRoute      = adminBaseURL + "/groups/%s/apps"
+	appFunctionsRoute       = adminBaseURL + "/groups/%s/apps/%s/functions"
+	appFunctionRoute        = adminBaseURL + "/groups/%s/apps/%s/functions/%s"
+	appExecuteFunctionRoute = adminBaseURL + "/groups/%s/apps/%s/debug/execute_function"
+	appValuesRoute          = adminBaseURL + "/groups/%s/apps/%s/values"
+	appValueRoute           = adminBaseURL + "/groups/%s/apps/%s/values/%s"
+	appSecretsRoute         = adminBaseURL + "/groups/%s/apps/%s/secrets"
+	appSecretRoute          = adminBaseURL + "/groups/%s/apps/%s/secrets/%s"
+	appTriggersRoute        = adminBaseURL + "/groups/%s/apps/%s/triggers"
+	appTriggerRoute         = adminBaseURL + "/groups/%s/apps/%s/triggers/%s"
+	appTriggerResumeRoute   = adminBaseURL + "/groups/%s/apps/%s/triggers/%s/resume"
+	appLogsRoute            = adminBaseURL + "/groups/%s/apps/%s/logs"
+	appUsersRoute           = adminBaseURL + "/groups/%s/apps/%s/users"
+	appUserRoute            = adminBaseURL + "/groups/%s/apps/%s/users/%s"
+	appUserEnableRoute      = adminBaseURL + "/groups/%s/apps/%s/users/%s/enable"
+	appUserDisableRoute     = adminBaseURL + "/groups/%s/apps/%s/users/%s/disable"
+	appUserLogoutRoute      = adminBaseURL + "/groups/%s/apps/%s/users/%s/logout"
+	appAuthProvidersRoute   = adminBaseURL + "/groups/%s/apps/%s/auth_providers"
+	appAPIKeysRoute         = adminBaseURL + "/groups/%s/apps/%s/api_keys"
+	appAPIKeyRoute          = adminBaseURL + "/groups/%s/apps/%s/api_keys/%s"
+	appAPIKeyEnableRoute    = adminBaseURL + "/groups/%s/apps/%s/api_keys/%s/enable"
+	appAPIKeyDisableRoute   = adminBaseURL + "/groups/%s/apps/%s/api_keys/%s/disable"
+	userProfileRoute        = adminBaseURL + "/auth/profile"
 )
 
+// appUsersPageSize is the number of users requested at a time when listing the users of an app
+const appUsersPageSize = 50
+
 var (
 	errExportMissingFilename = errors.New("the app export response did not specify a filename")
 	errGroupNotFound         = errors.New("group could not be found")
@@ -89,7 +117,40 @@ type StitchClient interface {
 	FetchAppByGroupIDAndClientAppID(groupID, clientAppID string) (*models.App, error)
 	FetchAppByClientAppID(clientAppID string) (*models.App, error)
 	FetchAppsByGroupID(groupID string) ([]*models.App, error)
+	FetchUserProfile() (*models.UserProfile, error)
 	CreateEmptyApp(groupID, appName string) (*models.App, error)
+	Functions(groupID, appID string) ([]*models.Function, error)
+	Function(groupID, appID, functionID string) (*models.Function, error)
+	CreateFunction(groupID, appID string, function *models.Function) (*models.Function, error)
+	UpdateFunction(groupID, appID string, function *models.Function) error
+	DeleteFunction(groupID, appID, functionID string) error
+	ExecuteFunction(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error)
+	Values(groupID, appID string) ([]*models.Value, error)
+	Value(groupID, appID, valueID string) (*models.Value, error)
+	CreateValue(groupID, appID string, value *models.Value) (*models.Value, error)
+	UpdateValue(groupID, appID string, value *models.Value) error
+	DeleteValue(groupID, appID, valueID string) error
+	Secrets(groupID, appID string) ([]*models.Secret, error)
+	CreateSecret(groupID, appID string, secret *models.Secret) (*models.Secret, error)
+	UpdateSecret(groupID, appID string, secret *models.Secret) error
+	DeleteSecret(groupID, appID, secretID string) error
+	Triggers(groupID, appID string) ([]*models.Trigger, error)
+	Trigger(groupID, appID, triggerID string) (map[string]interface{}, error)
+	UpdateTrigger(groupID, appID, triggerID string, trigger map[string]interface{}) error
+	ResumeTrigger(groupID, appID, triggerID string) error
+	Logs(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error)
+	AppUsers(groupID, appID, providerType string) ([]*models.AppUser, error)
+	AppUser(groupID, appID, userID string) (*models.AppUser, error)
+	DeleteAppUser(groupID, appID, userID string) error
+	EnableAppUser(groupID, appID, userID string) error
+	DisableAppUser(groupID, appID, userID string) error
+	RevokeAppUserSessions(groupID, appID, userID string) error
+	AuthProviders(groupID, appID string) ([]*models.AuthProvider, error)
+	APIKeys(groupID, appID string) ([]*models.APIKey, error)
+	CreateAPIKey(groupID, appID, name string) (*models.APIKey, error)
+	EnableAPIKey(groupID, appID, keyID string) error
+	DisableAPIKey(groupID, appID, keyID string) error
+	DeleteAPIKey(groupID, appID, keyID string) error
 }
 
 // NewStitchClient returns a new StitchClient to be used for making calls to the Stitch Admin API
@@ -241,6 +302,16 @@ func (sc *basicStitchClient) FetchAppByGroupIDAndClientAppID(groupID, clientAppI
 
 // FetchAppByClientAppID fetches a Stitch app given a clientAppID
 func (sc *basicStitchClient) FetchAppByClientAppID(clientAppID string) (*models.App, error) {
+	profileData, err := sc.FetchUserProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	return sc.findProjectAppByClientAppID(profileData.AllGroupIDs(), clientAppID)
+}
+
+// FetchUserProfile fetches the profile of the current user, listing the groups they have roles in
+func (sc *basicStitchClient) FetchUserProfile() (*models.UserProfile, error) {
 	res, err := sc.ExecuteRequest(http.MethodGet, userProfileRoute, RequestOptions{})
 	if err != nil {
 		return nil, err
@@ -258,7 +329,7 @@ func (sc *basicStitchClient) FetchAppByClientAppID(clientAppID string) (*models.
 		return nil, err
 	}
 
-	return sc.findProjectAppByClientAppID(profileData.AllGroupIDs(), clientAppID)
+	return &profileData, nil
 }
 
 func (sc *basicStitchClient) findProjectAppByClientAppID(groupIDs []string, clientAppID string) (*models.App, error) {
@@ -301,6 +372,693 @@ func (sc *basicStitchClient) CreateEmptyApp(groupID, appName string) (*models.Ap
 	return &app, nil
 }
 
+// Functions returns the functions of the given app. Function sources are not included
+func (sc *basicStitchClient) Functions(groupID, appID string) ([]*models.Function, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appFunctionsRoute, groupID, appID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var functions []*models.Function
+	if err := json.NewDecoder(res.Body).Decode(&functions); err != nil {
+		return nil, err
+	}
+
+	return functions, nil
+}
+
+// Function returns the function with the given ID, including its source
+func (sc *basicStitchClient) Function(groupID, appID, functionID string) (*models.Function, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appFunctionRoute, groupID, appID, functionID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var function models.Function
+	if err := json.NewDecoder(res.Body).Decode(&function); err != nil {
+		return nil, err
+	}
+
+	return &function, nil
+}
+
+// CreateFunction creates a new function within the given app
+func (sc *basicStitchClient) CreateFunction(groupID, appID string, function *models.Function) (*models.Function, error) {
+	body, err := json.Marshal(function)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPost, fmt.Sprintf(appFunctionsRoute, groupID, appID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var created models.Function
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateFunction replaces the function with the matching ID within the given app
+func (sc *basicStitchClient) UpdateFunction(groupID, appID string, function *models.Function) error {
+	body, err := json.Marshal(function)
+	if err != nil {
+		return err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPut, fmt.Sprintf(appFunctionRoute, groupID, appID, function.ID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// DeleteFunction deletes the function with the given ID
+func (sc *basicStitchClient) DeleteFunction(groupID, appID, functionID string) error {
+	res, err := sc.ExecuteRequest(http.MethodDelete, fmt.Sprintf(appFunctionRoute, groupID, appID, functionID), RequestOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// ExecuteFunction runs the named function with the given arguments as the user with the given ID,
+// or as the system user if no user ID is provided
+func (sc *basicStitchClient) ExecuteFunction(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error) {
+	if args == nil {
+		args = []json.RawMessage{}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if userID == "" {
+		query.Set("run_as_system", "true")
+	} else {
+		query.Set("user_id", userID)
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPost, fmt.Sprintf(appExecuteFunctionRoute, groupID, appID)+"?"+query.Encode(), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var execution models.FunctionExecution
+	if err := json.NewDecoder(res.Body).Decode(&execution); err != nil {
+		return nil, err
+	}
+
+	return &execution, nil
+}
+
+// Values returns the values of the given app
+func (sc *basicStitchClient) Values(groupID, appID string) ([]*models.Value, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appValuesRoute, groupID, appID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var values []*models.Value
+	if err := json.NewDecoder(res.Body).Decode(&values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Value returns the value with the given ID, including its contents
+func (sc *basicStitchClient) Value(groupID, appID, valueID string) (*models.Value, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appValueRoute, groupID, appID, valueID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var value models.Value
+	if err := json.NewDecoder(res.Body).Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+// CreateValue creates a new value within the given app
+func (sc *basicStitchClient) CreateValue(groupID, appID string, value *models.Value) (*models.Value, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPost, fmt.Sprintf(appValuesRoute, groupID, appID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var created models.Value
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateValue replaces the value with the matching ID within the given app
+func (sc *basicStitchClient) UpdateValue(groupID, appID string, value *models.Value) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPut, fmt.Sprintf(appValueRoute, groupID, appID, value.ID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// DeleteValue deletes the value with the given ID
+func (sc *basicStitchClient) DeleteValue(groupID, appID, valueID string) error {
+	res, err := sc.ExecuteRequest(http.MethodDelete, fmt.Sprintf(appValueRoute, groupID, appID, valueID), RequestOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// Secrets returns the names of the secrets of the given app
+func (sc *basicStitchClient) Secrets(groupID, appID string) ([]*models.Secret, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appSecretsRoute, groupID, appID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var secrets []*models.Secret
+	if err := json.NewDecoder(res.Body).Decode(&secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// CreateSecret creates a new secret within the given app
+func (sc *basicStitchClient) CreateSecret(groupID, appID string, secret *models.Secret) (*models.Secret, error) {
+	body, err := json.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPost, fmt.Sprintf(appSecretsRoute, groupID, appID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var created models.Secret
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateSecret replaces the value of the secret with the matching ID within the given app
+func (sc *basicStitchClient) UpdateSecret(groupID, appID string, secret *models.Secret) error {
+	body, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPut, fmt.Sprintf(appSecretRoute, groupID, appID, secret.ID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// DeleteSecret deletes the secret with the given ID
+func (sc *basicStitchClient) DeleteSecret(groupID, appID, secretID string) error {
+	res, err := sc.ExecuteRequest(http.MethodDelete, fmt.Sprintf(appSecretRoute, groupID, appID, secretID), RequestOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// Triggers returns the triggers of the given app
+func (sc *basicStitchClient) Triggers(groupID, appID string) ([]*models.Trigger, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appTriggersRoute, groupID, appID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var triggers []*models.Trigger
+	if err := json.NewDecoder(res.Body).Decode(&triggers); err != nil {
+		return nil, err
+	}
+
+	return triggers, nil
+}
+
+// Trigger returns the full document of the trigger with the given ID. It is not decoded into a models.Trigger
+// so that it can be sent back through UpdateTrigger without dropping fields the CLI does not know about
+func (sc *basicStitchClient) Trigger(groupID, appID, triggerID string) (map[string]interface{}, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appTriggerRoute, groupID, appID, triggerID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var trigger map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&trigger); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+// UpdateTrigger replaces the trigger with the given ID within the given app
+func (sc *basicStitchClient) UpdateTrigger(groupID, appID, triggerID string, trigger map[string]interface{}) error {
+	body, err := json.Marshal(trigger)
+	if err != nil {
+		return err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPut, fmt.Sprintf(appTriggerRoute, groupID, appID, triggerID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// ResumeTrigger restarts a trigger that was suspended after a failure
+func (sc *basicStitchClient) ResumeTrigger(groupID, appID, triggerID string) error {
+	res, err := sc.ExecuteRequest(http.MethodPut, fmt.Sprintf(appTriggerResumeRoute, groupID, appID, triggerID), RequestOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
+// Logs returns a page of the logs of the given app matching the query, newest first
+func (sc *basicStitchClient) Logs(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error) {
+	params := url.Values{}
+	if query.Type != "" {
+		params.Set("type", query.Type)
+	}
+	if query.ErrorsOnly {
+		params.Set("errors_only", "true")
+	}
+	if !query.StartDate.IsZero() {
+		params.Set("start_date", query.StartDate.UTC().Format(time.RFC3339Nano))
+	}
+	if !query.EndDate.IsZero() {
+		params.Set("end_date", query.EndDate.UTC().Format(time.RFC3339Nano))
+	}
+	if query.Skip != 0 {
+		params.Set("skip", strconv.Itoa(query.Skip))
+	}
+	if query.Limit != 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	path := fmt.Sprintf(appLogsRoute, groupID, appID)
+	if len(params) != 0 {
+		path += "?" + params.Encode()
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodGet, path, RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var page models.LogsPage
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// AppUsers returns every user of the given app, optionally only those with an identity from the given
+// provider type. Pages of users are requested until one comes back short
+func (sc *basicStitchClient) AppUsers(groupID, appID, providerType string) ([]*models.AppUser, error) {
+	var users []*models.AppUser
+	for {
+		params := url.Values{"limit": []string{strconv.Itoa(appUsersPageSize)}}
+		if providerType != "" {
+			params.Set("provider_types", providerType)
+		}
+		if len(users) != 0 {
+			params.Set("after", users[len(users)-1].ID)
+		}
+
+		res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appUsersRoute, groupID, appID)+"?"+params.Encode(), RequestOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []*models.AppUser
+		err = func() error {
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				return UnmarshalStitchError(res)
+			}
+
+			return json.NewDecoder(res.Body).Decode(&page)
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, page...)
+
+		if len(page) < appUsersPageSize {
+			return users, nil
+		}
+	}
+}
+
+// AppUser returns the user of the given app with the given ID
+func (sc *basicStitchClient) AppUser(groupID, appID, userID string) (*models.AppUser, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appUserRoute, groupID, appID, userID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var user models.AppUser
+	if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DeleteAppUser deletes the user of the given app with the given ID
+func (sc *basicStitchClient) DeleteAppUser(groupID, appID, userID string) error {
+	return sc.doNoContentRequest(http.MethodDelete, fmt.Sprintf(appUserRoute, groupID, appID, userID))
+}
+
+// EnableAppUser allows a disabled user of the given app to log in again
+func (sc *basicStitchClient) EnableAppUser(groupID, appID, userID string) error {
+	return sc.doNoContentRequest(http.MethodPut, fmt.Sprintf(appUserEnableRoute, groupID, appID, userID))
+}
+
+// DisableAppUser prevents a user of the given app from logging in
+func (sc *basicStitchClient) DisableAppUser(groupID, appID, userID string) error {
+	return sc.doNoContentRequest(http.MethodPut, fmt.Sprintf(appUserDisableRoute, groupID, appID, userID))
+}
+
+// RevokeAppUserSessions invalidates every session of a user of the given app, forcing them to log in again
+func (sc *basicStitchClient) RevokeAppUserSessions(groupID, appID, userID string) error {
+	return sc.doNoContentRequest(http.MethodPut, fmt.Sprintf(appUserLogoutRoute, groupID, appID, userID))
+}
+
+// AuthProviders returns the auth providers of the given app
+func (sc *basicStitchClient) AuthProviders(groupID, appID string) ([]*models.AuthProvider, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appAuthProvidersRoute, groupID, appID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var providers []*models.AuthProvider
+	if err := json.NewDecoder(res.Body).Decode(&providers); err != nil {
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+// APIKeys returns the API keys of the given app, without the keys themselves
+func (sc *basicStitchClient) APIKeys(groupID, appID string) ([]*models.APIKey, error) {
+	res, err := sc.ExecuteRequest(http.MethodGet, fmt.Sprintf(appAPIKeysRoute, groupID, appID), RequestOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var keys []*models.APIKey
+	if err := json.NewDecoder(res.Body).Decode(&keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// CreateAPIKey creates an API key with the given name within the given app. The returned APIKey is the
+// only one to include the key itself
+func (sc *basicStitchClient) CreateAPIKey(groupID, appID, name string) (*models.APIKey, error) {
+	body, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sc.ExecuteRequest(http.MethodPost, fmt.Sprintf(appAPIKeysRoute, groupID, appID), RequestOptions{
+		Body: bytes.NewReader(body),
+		Header: http.Header{
+			"Content-Type": []string{string(utils.MediaTypeJSON)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, UnmarshalStitchError(res)
+	}
+
+	var created models.APIKey
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// EnableAPIKey allows the API key with the given ID to be used to log in again
+func (sc *basicStitchClient) EnableAPIKey(groupID, appID, keyID string) error {
+	return sc.doNoContentRequest(http.MethodPut, fmt.Sprintf(appAPIKeyEnableRoute, groupID, appID, keyID))
+}
+
+// DisableAPIKey prevents the API key with the given ID from being used to log in
+func (sc *basicStitchClient) DisableAPIKey(groupID, appID, keyID string) error {
+	return sc.doNoContentRequest(http.MethodPut, fmt.Sprintf(appAPIKeyDisableRoute, groupID, appID, keyID))
+}
+
+// DeleteAPIKey deletes the API key with the given ID
+func (sc *basicStitchClient) DeleteAPIKey(groupID, appID, keyID string) error {
+	return sc.doNoContentRequest(http.MethodDelete, fmt.Sprintf(appAPIKeyRoute, groupID, appID, keyID))
+}
+
+// doNoContentRequest executes a request without a body that is expected to respond with 204 No Content
+func (sc *basicStitchClient) doNoContentRequest(method, path string) error {
+	res, err := sc.ExecuteRequest(method, path, RequestOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return UnmarshalStitchError(res)
+	}
+
+	return nil
+}
+
 func findAppByClientAppID(apps []*models.App, clientAppID string) *models.App {
 	for _, app := range apps {
 		if app.ClientAppID == clientAppID {
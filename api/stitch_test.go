@@ -1,11 +1,16 @@
 package api_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/10gen/stitch-cli/api"
+	"github.com/10gen/stitch-cli/models"
 
 	u "github.com/10gen/stitch-cli/utils/test"
 	gc "github.com/smartystreets/goconvey/convey"
@@ -34,3 +39,312 @@ func TestErrStitchResponse(t *testing.T) {
 		u.So(t, err, gc.ShouldBeError, "error: something went horribly, horribly wrong")
 	})
 }
+
+func TestStitchClientFunctions(t *testing.T) {
+	t.Run("should fetch the functions of an app", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader(`[{"_id": "fn-id", "name": "function_a", "private": true}]`)),
+			},
+		})
+
+		functions, err := api.NewStitchClient(client).Functions("group-id", "app-id")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, functions, gc.ShouldHaveLength, 1)
+		u.So(t, functions[0].ID, gc.ShouldEqual, "fn-id")
+		u.So(t, functions[0].Private, gc.ShouldBeTrue)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodGet)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/functions")
+	})
+
+	t.Run("should report an error when deleting a function fails", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusNotFound,
+				Body:       u.NewResponseBody(strings.NewReader(`{"error": "function not found"}`)),
+			},
+		})
+
+		err := api.NewStitchClient(client).DeleteFunction("group-id", "app-id", "fn-id")
+		u.So(t, err, gc.ShouldBeError, "error: function not found")
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodDelete)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/functions/fn-id")
+	})
+}
+
+func TestStitchClientExecuteFunction(t *testing.T) {
+	t.Run("should execute a function as the system user", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader(`{"result": {"$numberInt": "3"}, "logs": ["adding numbers"]}`)),
+			},
+		})
+
+		execution, err := api.NewStitchClient(client).ExecuteFunction("group-id", "app-id", "", "sum", []json.RawMessage{json.RawMessage("1"), json.RawMessage("2")})
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(execution.Result), gc.ShouldEqual, `{"$numberInt": "3"}`)
+		u.So(t, execution.Logs, gc.ShouldResemble, []string{"adding numbers"})
+		u.So(t, execution.Error, gc.ShouldBeNil)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodPost)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/debug/execute_function?run_as_system=true")
+		u.So(t, client.RequestData[0].Options.Body, gc.ShouldNotBeNil)
+	})
+
+	t.Run("should execute a function as the given user", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader(`{"error": {"name": "TypeError", "message": "x is undefined"}}`)),
+			},
+		})
+
+		execution, err := api.NewStitchClient(client).ExecuteFunction("group-id", "app-id", "user-id", "broken", nil)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, execution.Error, gc.ShouldBeError, "TypeError: x is undefined")
+
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/debug/execute_function?user_id=user-id")
+	})
+}
+
+func TestStitchClientValues(t *testing.T) {
+	t.Run("should fetch a value with its contents", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader(`{"_id": "value-id", "name": "token", "value": "s3cr3t", "private": true}`)),
+			},
+		})
+
+		value, err := api.NewStitchClient(client).Value("group-id", "app-id", "value-id")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, value, gc.ShouldResemble, &models.Value{ID: "value-id", Name: "token", Value: "s3cr3t", Private: true})
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodGet)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/values/value-id")
+	})
+
+	t.Run("should create a value", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusCreated,
+				Body:       u.NewResponseBody(strings.NewReader(`{"_id": "value-id", "name": "flag", "value": true, "private": false}`)),
+			},
+		})
+
+		value, err := api.NewStitchClient(client).CreateValue("group-id", "app-id", &models.Value{Name: "flag", Value: true})
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, value, gc.ShouldResemble, &models.Value{ID: "value-id", Name: "flag", Value: true})
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodPost)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/values")
+	})
+
+	t.Run("should update a value", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusNoContent,
+				Body:       u.NewResponseBody(strings.NewReader("")),
+			},
+		})
+
+		err := api.NewStitchClient(client).UpdateValue("group-id", "app-id", &models.Value{ID: "value-id", Name: "flag", Value: false})
+		u.So(t, err, gc.ShouldBeNil)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodPut)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/values/value-id")
+	})
+}
+
+func TestStitchClientTriggers(t *testing.T) {
+	t.Run("should send back fields of a trigger that are not modeled", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader(`{"_id": "trigger-id", "name": "onInsert", "disabled": false, "event_processors": {"FUNCTION": {}}}`)),
+			},
+			{
+				StatusCode: http.StatusNoContent,
+				Body:       u.NewResponseBody(strings.NewReader("")),
+			},
+		})
+
+		stitchClient := api.NewStitchClient(client)
+		trigger, err := stitchClient.Trigger("group-id", "app-id", "trigger-id")
+		u.So(t, err, gc.ShouldBeNil)
+
+		trigger["disabled"] = true
+		u.So(t, stitchClient.UpdateTrigger("group-id", "app-id", "trigger-id", trigger), gc.ShouldBeNil)
+
+		u.So(t, client.RequestData[1].Method, gc.ShouldEqual, http.MethodPut)
+		u.So(t, client.RequestData[1].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/triggers/trigger-id")
+
+		body, err := ioutil.ReadAll(client.RequestData[1].Options.Body)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(body), gc.ShouldEqual, `{"_id":"trigger-id","disabled":true,"event_processors":{"FUNCTION":{}},"name":"onInsert"}`)
+	})
+}
+
+func TestStitchClientLogs(t *testing.T) {
+	t.Run("should fetch a page of logs matching the query", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body: u.NewResponseBody(strings.NewReader(`{
+					"logs": [{"_id": "log-id", "type": "FUNCTION", "function_name": "sum", "started": "2018-01-02T15:04:05Z", "completed": "2018-01-02T15:04:06Z"}],
+					"nextEndDate": "2018-01-02T15:00:00Z",
+					"nextSkip": 1
+				}`)),
+			},
+		})
+
+		startDate := time.Date(2018, 1, 2, 14, 0, 0, 0, time.UTC)
+		endDate := time.Date(2018, 1, 2, 16, 0, 0, 0, time.UTC)
+		page, err := api.NewStitchClient(client).Logs("group-id", "app-id", models.LogsQuery{
+			Type:       "FUNCTION",
+			ErrorsOnly: true,
+			StartDate:  startDate,
+			EndDate:    endDate,
+			Skip:       2,
+			Limit:      100,
+		})
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, page.Logs, gc.ShouldHaveLength, 1)
+		u.So(t, page.Logs[0].FunctionName, gc.ShouldEqual, "sum")
+		u.So(t, page.Logs[0].Completed.Sub(page.Logs[0].Started), gc.ShouldEqual, time.Second)
+		u.So(t, page.NextEndDate.Equal(time.Date(2018, 1, 2, 15, 0, 0, 0, time.UTC)), gc.ShouldBeTrue)
+		u.So(t, page.NextSkip, gc.ShouldEqual, 1)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodGet)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/logs?"+
+			"end_date=2018-01-02T16%3A00%3A00Z&errors_only=true&limit=100&skip=2&start_date=2018-01-02T14%3A00%3A00Z&type=FUNCTION")
+	})
+
+	t.Run("should omit unset filters", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader(`{"logs": []}`)),
+			},
+		})
+
+		page, err := api.NewStitchClient(client).Logs("group-id", "app-id", models.LogsQuery{})
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, page.Logs, gc.ShouldBeEmpty)
+		u.So(t, page.NextEndDate, gc.ShouldBeNil)
+
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/logs")
+	})
+}
+
+func TestStitchClientAppUsers(t *testing.T) {
+	t.Run("should request pages of users until one comes back short", func(t *testing.T) {
+		fullPage := make([]string, 50)
+		for i := range fullPage {
+			fullPage[i] = fmt.Sprintf(`{"_id": "user-%d", "identities": [{"id": "%d", "provider_type": "api-key"}]}`, i, i)
+		}
+
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader("[" + strings.Join(fullPage, ",") + "]")),
+			},
+			{
+				StatusCode: http.StatusOK,
+				Body:       u.NewResponseBody(strings.NewReader(`[{"_id": "user-50", "disabled": true}]`)),
+			},
+		})
+
+		users, err := api.NewStitchClient(client).AppUsers("group-id", "app-id", "api-key")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, users, gc.ShouldHaveLength, 51)
+		u.So(t, users[0].Identities, gc.ShouldResemble, []models.AppUserIdentity{{ID: "0", ProviderType: "api-key"}})
+		u.So(t, users[50].Disabled, gc.ShouldBeTrue)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodGet)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/users?limit=50&provider_types=api-key")
+		u.So(t, client.RequestData[1].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/users?after=user-49&limit=50&provider_types=api-key")
+	})
+
+	t.Run("should disable a user", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusNoContent,
+				Body:       u.NewResponseBody(strings.NewReader("")),
+			},
+		})
+
+		err := api.NewStitchClient(client).DisableAppUser("group-id", "app-id", "user-id")
+		u.So(t, err, gc.ShouldBeNil)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodPut)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/users/user-id/disable")
+	})
+
+	t.Run("should revoke the sessions of a user", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusNoContent,
+				Body:       u.NewResponseBody(strings.NewReader("")),
+			},
+		})
+
+		err := api.NewStitchClient(client).RevokeAppUserSessions("group-id", "app-id", "user-id")
+		u.So(t, err, gc.ShouldBeNil)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodPut)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/users/user-id/logout")
+	})
+}
+
+func TestStitchClientAPIKeys(t *testing.T) {
+	t.Run("should create an API key", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusCreated,
+				Body:       u.NewResponseBody(strings.NewReader(`{"_id": "key-id", "name": "server", "key": "secret-key", "disabled": false}`)),
+			},
+		})
+
+		key, err := api.NewStitchClient(client).CreateAPIKey("group-id", "app-id", "server")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, key, gc.ShouldResemble, &models.APIKey{ID: "key-id", Name: "server", Key: "secret-key"})
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodPost)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/api_keys")
+	})
+
+	t.Run("should disable an API key", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusNoContent,
+				Body:       u.NewResponseBody(strings.NewReader("")),
+			},
+		})
+
+		err := api.NewStitchClient(client).DisableAPIKey("group-id", "app-id", "key-id")
+		u.So(t, err, gc.ShouldBeNil)
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodPut)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/api_keys/key-id/disable")
+	})
+
+	t.Run("should surface errors when deleting an API key", func(t *testing.T) {
+		client := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: http.StatusNotFound,
+				Body:       u.NewResponseBody(strings.NewReader(`{"error": "api key not found"}`)),
+			},
+		})
+
+		err := api.NewStitchClient(client).DeleteAPIKey("group-id", "app-id", "key-id")
+		u.So(t, err, gc.ShouldBeError, "error: api key not found")
+
+		u.So(t, client.RequestData[0].Method, gc.ShouldEqual, http.MethodDelete)
+		u.So(t, client.RequestData[0].Path, gc.ShouldEqual, "/api/admin/v3.0/groups/group-id/apps/app-id/api_keys/key-id")
+	})
+}
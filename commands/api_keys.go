@@ -0,0 +1,471 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/10gen/stitch-cli/models"
+
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	apiKeysFlagOutputFile = "output-file"
+
+	apiKeyProviderType = "api-key"
+)
+
+var errAPIKeyNameRequired = errors.New("an API key name must be supplied")
+
+// NewAPIKeysListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAPIKeysListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("api-keys list", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &APIKeysListCommand{appCommand: ac}, nil
+	}
+}
+
+// APIKeysListCommand is used to list the API keys of a deployed app
+type APIKeysListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (aklc *APIKeysListCommand) Synopsis() string {
+	return `List the API keys of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (aklc *APIKeysListCommand) Help() string {
+	return `List the names, IDs and status of the API keys of a deployed app. Keys themselves are never displayed.
+
+OPTIONS:` +
+		aklc.appCommand.Help()
+}
+
+// Run executes the command
+func (aklc *APIKeysListCommand) Run(args []string) int {
+	aklc.NewFlagSet()
+
+	if err := aklc.BaseCommand.run(args); err != nil {
+		aklc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := aklc.list(); err != nil {
+		aklc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (aklc *APIKeysListCommand) list() error {
+	app, err := aklc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := aklc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	keys, err := stitchClient.APIKeys(app.GroupID, app.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		aklc.UI.Info("No API keys found")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tID\tSTATUS")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key.Name, key.ID, enabledStatus(key.Disabled))
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	aklc.UI.Output(strings.TrimSuffix(buf.String(), "\n"))
+
+	return nil
+}
+
+// NewAPIKeysCreateCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAPIKeysCreateCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("api-keys create", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &APIKeysCreateCommand{appCommand: ac}, nil
+	}
+}
+
+// APIKeysCreateCommand is used to create an API key for a deployed app
+type APIKeysCreateCommand struct {
+	*appCommand
+
+	flagOutputFile string
+}
+
+// Synopsis returns a one-liner description for this command
+func (akcc *APIKeysCreateCommand) Synopsis() string {
+	return `Create an API key for a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (akcc *APIKeysCreateCommand) Help() string {
+	return `Create an API key for a deployed app and print it. The key cannot be retrieved again afterwards.
+
+Usage: stitch-cli api-keys create <name> [options]
+
+OPTIONS:
+  --output-file [string]
+	Write the key to a new file at the given path, readable only by the current user, instead of printing it.
+` +
+		akcc.appCommand.Help()
+}
+
+// Run executes the command
+func (akcc *APIKeysCreateCommand) Run(args []string) int {
+	set := akcc.NewFlagSet()
+
+	set.StringVar(&akcc.flagOutputFile, apiKeysFlagOutputFile, "", "")
+
+	if err := akcc.BaseCommand.run(args); err != nil {
+		akcc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := akcc.create(); err != nil {
+		akcc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (akcc *APIKeysCreateCommand) create() error {
+	if err := akcc.checkPositionalArgs(1, 1, errAPIKeyNameRequired); err != nil {
+		return err
+	}
+	name := akcc.positionalArgs[0]
+
+	var outputFile *os.File
+	var key *models.APIKey
+	if akcc.flagOutputFile != "" {
+		path, err := homedir.Expand(akcc.flagOutputFile)
+		if err != nil {
+			return err
+		}
+
+		// the file is created before the key so that nothing is lost if it cannot be written
+		if outputFile, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600); err != nil {
+			return fmt.Errorf("failed to create %s: %s", path, err)
+		}
+		defer outputFile.Close()
+		defer func() {
+			if key == nil {
+				os.Remove(outputFile.Name())
+			}
+		}()
+	}
+
+	app, err := akcc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := akcc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	if providers, err := stitchClient.AuthProviders(app.GroupID, app.ID); err == nil {
+		akcc.warnAPIKeyProvider(app, providers)
+	}
+
+	if key, err = stitchClient.CreateAPIKey(app.GroupID, app.ID, name); err != nil {
+		return err
+	}
+
+	if outputFile == nil {
+		akcc.UI.Output(key.Key)
+		akcc.UI.Warn(fmt.Sprintf("Created API key '%s' (%s) in '%s'. Store the key now: it cannot be displayed again", name, key.ID, app.ClientAppID))
+		return nil
+	}
+
+	if _, err := outputFile.WriteString(key.Key + "\n"); err != nil {
+		return fmt.Errorf("created API key '%s' (%s) but failed to write it to %s: %s", name, key.ID, outputFile.Name(), err)
+	}
+
+	akcc.UI.Info(fmt.Sprintf("Created API key '%s' (%s) in '%s' and wrote it to %s", name, key.ID, app.ClientAppID, outputFile.Name()))
+	return nil
+}
+
+// warnAPIKeyProvider warns when the api-key auth provider of the app is missing or disabled, since keys
+// cannot be used to log in until it is enabled
+func (akcc *APIKeysCreateCommand) warnAPIKeyProvider(app *models.App, providers []*models.AuthProvider) {
+	for _, provider := range providers {
+		if provider.Type != apiKeyProviderType {
+			continue
+		}
+
+		if provider.Disabled {
+			akcc.UI.Warn(fmt.Sprintf("The %s auth provider of '%s' is disabled: the key cannot be used until it is enabled", apiKeyProviderType, app.ClientAppID))
+		}
+		return
+	}
+
+	akcc.UI.Warn(fmt.Sprintf("'%s' has no %s auth provider: the key cannot be used until one is added", app.ClientAppID, apiKeyProviderType))
+}
+
+// NewAPIKeysEnableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAPIKeysEnableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newAPIKeysToggleCommandFactory("api-keys enable", false, ui)
+}
+
+// NewAPIKeysDisableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAPIKeysDisableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newAPIKeysToggleCommandFactory("api-keys disable", true, ui)
+}
+
+func newAPIKeysToggleCommandFactory(name string, disable bool, ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand(name, ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &APIKeysToggleCommand{appCommand: ac, disable: disable}, nil
+	}
+}
+
+// APIKeysToggleCommand is used to enable or disable an API key of a deployed app
+type APIKeysToggleCommand struct {
+	*appCommand
+
+	disable bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (aktc *APIKeysToggleCommand) Synopsis() string {
+	if aktc.disable {
+		return `Disable an API key of a deployed app.`
+	}
+
+	return `Enable an API key of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (aktc *APIKeysToggleCommand) Help() string {
+	description, usage := "Enable", "enable"
+	if aktc.disable {
+		description, usage = "Disable", "disable"
+	}
+
+	return fmt.Sprintf(`%s an API key of a deployed app, given its name or ID.
+
+Usage: stitch-cli api-keys %s <name> [options]
+
+OPTIONS:`, description, usage) +
+		aktc.appCommand.Help()
+}
+
+// Run executes the command
+func (aktc *APIKeysToggleCommand) Run(args []string) int {
+	aktc.NewFlagSet()
+
+	if err := aktc.BaseCommand.run(args); err != nil {
+		aktc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := aktc.toggle(); err != nil {
+		aktc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (aktc *APIKeysToggleCommand) toggle() error {
+	if err := aktc.checkPositionalArgs(1, 1, errAPIKeyNameRequired); err != nil {
+		return err
+	}
+	name := aktc.positionalArgs[0]
+
+	app, err := aktc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	key, err := fetchRemoteAPIKey(aktc.BaseCommand, app, name)
+	if err != nil {
+		return err
+	}
+
+	status := enabledStatus(aktc.disable)
+	if key.Disabled == aktc.disable {
+		aktc.UI.Info(fmt.Sprintf("API key '%s' is already %s in '%s'", key.Name, status, app.ClientAppID))
+		return nil
+	}
+
+	stitchClient, err := aktc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	if aktc.disable {
+		err = stitchClient.DisableAPIKey(app.GroupID, app.ID, key.ID)
+	} else {
+		err = stitchClient.EnableAPIKey(app.GroupID, app.ID, key.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	aktc.UI.Info(fmt.Sprintf("API key '%s' is now %s in '%s'", key.Name, status, app.ClientAppID))
+	return nil
+}
+
+// NewAPIKeysDeleteCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAPIKeysDeleteCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("api-keys delete", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &APIKeysDeleteCommand{appCommand: ac}, nil
+	}
+}
+
+// APIKeysDeleteCommand is used to delete an API key of a deployed app
+type APIKeysDeleteCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (akdc *APIKeysDeleteCommand) Synopsis() string {
+	return `Delete an API key of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (akdc *APIKeysDeleteCommand) Help() string {
+	return `Delete an API key of a deployed app, given its name or ID. Clients using the key can no longer log in.
+
+Usage: stitch-cli api-keys delete <name> [options]
+
+OPTIONS:` +
+		akdc.appCommand.Help()
+}
+
+// Run executes the command
+func (akdc *APIKeysDeleteCommand) Run(args []string) int {
+	akdc.NewFlagSet()
+
+	if err := akdc.BaseCommand.run(args); err != nil {
+		akdc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := akdc.delete(); err != nil {
+		akdc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (akdc *APIKeysDeleteCommand) delete() error {
+	if err := akdc.checkPositionalArgs(1, 1, errAPIKeyNameRequired); err != nil {
+		return err
+	}
+	name := akdc.positionalArgs[0]
+
+	app, err := akdc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	key, err := fetchRemoteAPIKey(akdc.BaseCommand, app, name)
+	if err != nil {
+		return err
+	}
+
+	confirm, err := akdc.AskYesNo(fmt.Sprintf("Delete API key '%s' (%s) from '%s'?", key.Name, key.ID, app.ClientAppID))
+	if err != nil || !confirm {
+		return err
+	}
+
+	stitchClient, err := akdc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	if err := stitchClient.DeleteAPIKey(app.GroupID, app.ID, key.ID); err != nil {
+		return err
+	}
+
+	akdc.UI.Info(fmt.Sprintf("Deleted API key '%s' from '%s'", key.Name, app.ClientAppID))
+	return nil
+}
+
+// fetchRemoteAPIKey looks up an API key of a deployed app by name or ID
+func fetchRemoteAPIKey(c *BaseCommand, app *models.App, nameOrID string) (*models.APIKey, error) {
+	stitchClient, err := c.StitchClient()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := stitchClient.APIKeys(app.GroupID, app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if key.ID == nameOrID {
+			return key, nil
+		}
+	}
+
+	var found *models.APIKey
+	for _, key := range keys {
+		if key.Name != nameOrID {
+			continue
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("more than one API key is named %q in '%s', use its ID instead", nameOrID, app.ClientAppID)
+		}
+		found = key
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("API key %q not found in '%s'", nameOrID, app.ClientAppID)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func newMockAPIKeysStitchClient(keys []*models.APIKey) *u.MockStitchClient {
+	stitchClient := u.NewMockAppStitchClient()
+	stitchClient.APIKeysFn = func(groupID, appID string) ([]*models.APIKey, error) {
+		return keys, nil
+	}
+	stitchClient.AuthProvidersFn = func(groupID, appID string) ([]*models.AuthProvider, error) {
+		return []*models.AuthProvider{{ID: "provider-id", Name: "api-key", Type: "api-key"}}, nil
+	}
+	stitchClient.CreateAPIKeyFn = func(groupID, appID, name string) (*models.APIKey, error) {
+		return &models.APIKey{ID: "new-key-id", Name: name, Key: "the-secret-key"}, nil
+	}
+
+	return stitchClient
+}
+
+func TestAPIKeysListCommand(t *testing.T) {
+	t.Run("should list API keys without their keys", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAPIKeysListCommandFactory)
+		listCommand := cmd.(*APIKeysListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+		listCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		listCommand.stitchClient = newMockAPIKeysStitchClient([]*models.APIKey{
+			{ID: "key-1", Name: "server"},
+			{ID: "key-2", Name: "batch", Disabled: true},
+		})
+
+		exitCode := listCommand.Run([]string{"--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `NAME    ID     STATUS
+server  key-1  enabled
+batch   key-2  disabled
+`)
+	})
+}
+
+func TestAPIKeysCreateCommand(t *testing.T) {
+	t.Run("should print the key once", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAPIKeysCreateCommandFactory)
+		createCommand := cmd.(*APIKeysCreateCommand)
+		createCommand.storage = u.NewEmptyStorage()
+		createCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		createCommand.stitchClient = newMockAPIKeysStitchClient(nil)
+
+		exitCode := createCommand.Run([]string{"server", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "the-secret-key\n")
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "Created API key 'server' (new-key-id) in 'my-app-abcdef'")
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldNotContainSubstring, "the-secret-key")
+	})
+
+	t.Run("should write the key to a file readable only by the current user", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "stitch-api-keys")
+		u.So(t, err, gc.ShouldBeNil)
+		defer os.RemoveAll(dir)
+		keyPath := filepath.Join(dir, "server.key")
+
+		cmd, mockUI := setUpAppCommand(NewAPIKeysCreateCommandFactory)
+		createCommand := cmd.(*APIKeysCreateCommand)
+		createCommand.storage = u.NewEmptyStorage()
+		createCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		stitchClient := newMockAPIKeysStitchClient(nil)
+		stitchClient.AuthProvidersFn = func(groupID, appID string) ([]*models.AuthProvider, error) {
+			return []*models.AuthProvider{{ID: "provider-id", Name: "api-key", Type: "api-key", Disabled: true}}, nil
+		}
+		createCommand.stitchClient = stitchClient
+
+		exitCode := createCommand.Run([]string{"server", "--app-id=my-app-abcdef", "--output-file=" + keyPath})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldNotContainSubstring, "the-secret-key")
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "The api-key auth provider of 'my-app-abcdef' is disabled")
+
+		data, err := ioutil.ReadFile(keyPath)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(data), gc.ShouldEqual, "the-secret-key\n")
+
+		info, err := os.Stat(keyPath)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, info.Mode().Perm(), gc.ShouldEqual, os.FileMode(0600))
+	})
+
+	t.Run("should not create a key when the output file already exists", func(t *testing.T) {
+		keyFile, err := ioutil.TempFile("", "stitch-api-key")
+		u.So(t, err, gc.ShouldBeNil)
+		keyFile.Close()
+		defer os.Remove(keyFile.Name())
+
+		cmd, mockUI := setUpAppCommand(NewAPIKeysCreateCommandFactory)
+		createCommand := cmd.(*APIKeysCreateCommand)
+		createCommand.storage = u.NewEmptyStorage()
+		createCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var created bool
+		stitchClient := newMockAPIKeysStitchClient(nil)
+		stitchClient.CreateAPIKeyFn = func(groupID, appID, name string) (*models.APIKey, error) {
+			created = true
+			return nil, nil
+		}
+		createCommand.stitchClient = stitchClient
+
+		exitCode := createCommand.Run([]string{"server", "--app-id=my-app-abcdef", "--output-file=" + keyFile.Name()})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, created, gc.ShouldBeFalse)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "failed to create "+keyFile.Name())
+	})
+}
+
+func TestAPIKeysToggleCommand(t *testing.T) {
+	t.Run("should disable an API key by name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAPIKeysDisableCommandFactory)
+		disableCommand := cmd.(*APIKeysToggleCommand)
+		disableCommand.storage = u.NewEmptyStorage()
+		disableCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var disabledID string
+		stitchClient := newMockAPIKeysStitchClient([]*models.APIKey{{ID: "key-1", Name: "server"}})
+		stitchClient.DisableAPIKeyFn = func(groupID, appID, keyID string) error {
+			disabledID = keyID
+			return nil
+		}
+		disableCommand.stitchClient = stitchClient
+
+		exitCode := disableCommand.Run([]string{"server", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, disabledID, gc.ShouldEqual, "key-1")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "API key 'server' is now disabled in 'my-app-abcdef'")
+	})
+
+	t.Run("should require an ID when several keys share a name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAPIKeysEnableCommandFactory)
+		enableCommand := cmd.(*APIKeysToggleCommand)
+		enableCommand.storage = u.NewEmptyStorage()
+		enableCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		enableCommand.stitchClient = newMockAPIKeysStitchClient([]*models.APIKey{
+			{ID: "key-1", Name: "server", Disabled: true},
+			{ID: "key-2", Name: "server", Disabled: true},
+		})
+
+		exitCode := enableCommand.Run([]string{"server", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `more than one API key is named "server"`)
+	})
+}
+
+func TestAPIKeysDeleteCommand(t *testing.T) {
+	t.Run("should delete an API key by ID", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAPIKeysDeleteCommandFactory)
+		deleteCommand := cmd.(*APIKeysDeleteCommand)
+		deleteCommand.storage = u.NewEmptyStorage()
+		deleteCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var deletedID string
+		stitchClient := newMockAPIKeysStitchClient([]*models.APIKey{{ID: "key-1", Name: "server"}})
+		stitchClient.DeleteAPIKeyFn = func(groupID, appID, keyID string) error {
+			deletedID = keyID
+			return nil
+		}
+		deleteCommand.stitchClient = stitchClient
+
+		exitCode := deleteCommand.Run([]string{"key-1", "--app-id=my-app-abcdef", "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, deletedID, gc.ShouldEqual, "key-1")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Deleted API key 'server' from 'my-app-abcdef'")
+	})
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"flag"
+	"os"
+
+	"github.com/10gen/stitch-cli/models"
+
+	"github.com/mitchellh/cli"
+)
+
+const flagRemoteName = "remote"
+
+// appCommand is embedded by commands that operate on the entities of either a local app directory
+// or the deployed app it belongs to
+type appCommand struct {
+	*BaseCommand
+
+	workingDirectory string
+
+	// remoteOnly and localOnly are set by commands that can only operate on the deployed app
+	// or on the local directory respectively
+	remoteOnly bool
+	localOnly  bool
+
+	flagAppPath   string
+	flagAppID     string
+	flagProjectID string
+	flagRemote    bool
+}
+
+func newAppCommand(name string, ui cli.Ui) (*appCommand, error) {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &appCommand{
+		BaseCommand: &BaseCommand{
+			Name: name,
+			UI:   ui,
+		},
+		workingDirectory: workingDirectory,
+	}, nil
+}
+
+// NewFlagSet builds the default set of flags along with the flags used to locate the app. The flags that
+// locate the deployed app are left out for commands that only operate on the local directory
+func (ac *appCommand) NewFlagSet() *flag.FlagSet {
+	set := ac.BaseCommand.NewFlagSet()
+
+	set.StringVar(&ac.flagAppPath, importFlagPath, "", "")
+	if ac.localOnly {
+		return set
+	}
+
+	set.StringVar(&ac.flagAppID, flagAppIDName, "", "")
+	set.StringVar(&ac.flagProjectID, flagProjectIDName, "", "")
+	if ac.remoteOnly {
+		ac.flagRemote = true
+	} else {
+		set.BoolVar(&ac.flagRemote, flagRemoteName, false, "")
+	}
+
+	return set
+}
+
+// appDirectory returns the local app directory
+func (ac *appCommand) appDirectory() (string, error) {
+	return resolveAppDirectory(ac.flagAppPath, ac.workingDirectory)
+}
+
+// remoteApp returns the deployed app, identified either by the --app-id flag or the local app directory
+func (ac *appCommand) remoteApp() (*models.App, error) {
+	if err := ac.requireLogin(); err != nil {
+		return nil, err
+	}
+
+	appPath, err := ac.appDirectory()
+	if err != nil && ac.flagAppID == "" {
+		return nil, err
+	}
+
+	return ac.resolveApp(ac.flagAppID, ac.flagProjectID, appPath)
+}
+
+// Help defines help documentation for parameters that apply to all app entity commands
+func (ac *appCommand) Help() string {
+	switch {
+	case ac.localOnly:
+		return `
+  --path [string]
+	A path to the local directory containing your app. Defaults to the app directory containing the working directory.` +
+			ac.BaseCommand.Help()
+	case ac.remoteOnly:
+		return `
+  --path [string]
+	A path to a local app directory whose stitch.json identifies the deployed app.
+
+  --app-id [string]
+	The App ID of the deployed app. Defaults to the "app_id" in the directory's stitch.json.
+
+  --project-id [string]
+	The Atlas Project ID to look up the deployed app in.` +
+			ac.BaseCommand.Help()
+	}
+
+	return `
+  --path [string]
+	A path to the local directory containing your app. Defaults to the app directory containing the working directory.
+
+  --remote
+	Operate on the deployed app rather than the local directory.
+
+  --app-id [string]
+	The App ID of the deployed app when using --remote. Defaults to the "app_id" in the directory's stitch.json.
+
+  --project-id [string]
+	The Atlas Project ID to look up the deployed app in when using --remote.` +
+		ac.BaseCommand.Help()
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"testing"
+
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestAppCommandNewFlagSet(t *testing.T) {
+	t.Run("should register the flags that locate the deployed app", func(t *testing.T) {
+		ac, err := newAppCommand("values list", cli.NewMockUi())
+		u.So(t, err, gc.ShouldBeNil)
+
+		set := ac.NewFlagSet()
+		for _, name := range []string{importFlagPath, flagRemoteName, flagAppIDName, flagProjectIDName} {
+			u.So(t, set.Lookup(name), gc.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("should only register --path for commands that operate on the local directory", func(t *testing.T) {
+		ac, err := newAppCommand("rules lint", cli.NewMockUi())
+		u.So(t, err, gc.ShouldBeNil)
+		ac.localOnly = true
+
+		set := ac.NewFlagSet()
+		u.So(t, set.Lookup(importFlagPath), gc.ShouldNotBeNil)
+		for _, name := range []string{flagRemoteName, flagAppIDName, flagProjectIDName} {
+			u.So(t, set.Lookup(name), gc.ShouldBeNil)
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+// authProvidersSecretsSection is the section of secrets.json holding the secrets of auth providers
+const authProvidersSecretsSection = "auth_providers"
+
+var (
+	errAuthProviderNameRequired = errors.New("an auth provider name must be supplied")
+	errAuthProviderTypeRequired = fmt.Errorf("an auth provider type must be supplied, one of: %s", strings.Join(utils.AuthProviderTypes(), ", "))
+)
+
+// NewAuthProvidersListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAuthProvidersListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("auth-providers list", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &AuthProvidersListCommand{appCommand: ac}, nil
+	}
+}
+
+// AuthProvidersListCommand is used to list the auth providers of a local app directory
+type AuthProvidersListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (aplc *AuthProvidersListCommand) Synopsis() string {
+	return `List the auth providers of an app.`
+}
+
+// Help returns long-form help information for this command
+func (aplc *AuthProvidersListCommand) Help() string {
+	return `List the auth providers of the local app directory along with their type and status.
+
+OPTIONS:` +
+		aplc.appCommand.Help()
+}
+
+// Run executes the command
+func (aplc *AuthProvidersListCommand) Run(args []string) int {
+	aplc.NewFlagSet()
+
+	if err := aplc.BaseCommand.run(args); err != nil {
+		aplc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := aplc.list(); err != nil {
+		aplc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (aplc *AuthProvidersListCommand) list() error {
+	appPath, err := aplc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	providers, err := utils.ReadLocalAuthProviders(appPath)
+	if err != nil {
+		return err
+	}
+
+	if len(providers) == 0 {
+		aplc.UI.Info("No auth providers found")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tSTATUS")
+	for _, provider := range providers {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", provider.Name(), provider.Type(), enabledStatus(provider.Disabled()))
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	aplc.UI.Output(strings.TrimSuffix(buf.String(), "\n"))
+
+	return nil
+}
+
+// NewAuthProvidersEnableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAuthProvidersEnableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newAuthProvidersToggleCommandFactory("auth-providers enable", false, ui)
+}
+
+// NewAuthProvidersDisableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAuthProvidersDisableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newAuthProvidersToggleCommandFactory("auth-providers disable", true, ui)
+}
+
+func newAuthProvidersToggleCommandFactory(name string, disable bool, ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand(name, ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &AuthProvidersToggleCommand{appCommand: ac, disable: disable}, nil
+	}
+}
+
+// AuthProvidersToggleCommand is used to enable or disable an auth provider of a local app directory
+type AuthProvidersToggleCommand struct {
+	*appCommand
+
+	disable bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (aptc *AuthProvidersToggleCommand) Synopsis() string {
+	if aptc.disable {
+		return `Disable an auth provider.`
+	}
+
+	return `Enable an auth provider.`
+}
+
+// Help returns long-form help information for this command
+func (aptc *AuthProvidersToggleCommand) Help() string {
+	description, usage := "Enable", "enable"
+	if aptc.disable {
+		description, usage = "Disable", "disable"
+	}
+
+	return fmt.Sprintf(`%s an auth provider in the local app directory. The change takes effect on the next import.
+
+Usage: stitch-cli auth-providers %s <name> [options]
+
+OPTIONS:`, description, usage) +
+		aptc.appCommand.Help()
+}
+
+// Run executes the command
+func (aptc *AuthProvidersToggleCommand) Run(args []string) int {
+	aptc.NewFlagSet()
+
+	if err := aptc.BaseCommand.run(args); err != nil {
+		aptc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := aptc.toggle(); err != nil {
+		aptc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (aptc *AuthProvidersToggleCommand) toggle() error {
+	if err := aptc.checkPositionalArgs(1, 1, errAuthProviderNameRequired); err != nil {
+		return err
+	}
+	name := aptc.positionalArgs[0]
+
+	appPath, err := aptc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	provider, err := utils.ReadLocalAuthProvider(appPath, name)
+	if err != nil {
+		return err
+	}
+
+	status := enabledStatus(aptc.disable)
+	if provider.Disabled() == aptc.disable {
+		aptc.UI.Info(fmt.Sprintf("Auth provider '%s' is already %s", name, status))
+		return nil
+	}
+
+	provider.Config["disabled"] = aptc.disable
+	if err := utils.WriteLocalAuthProvider(provider); err != nil {
+		return err
+	}
+
+	aptc.UI.Info(fmt.Sprintf("Auth provider '%s' is now %s in %s", name, status, provider.Path))
+	return nil
+}
+
+// NewAuthProvidersAddCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewAuthProvidersAddCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("auth-providers add", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &AuthProvidersAddCommand{appCommand: ac}, nil
+	}
+}
+
+// AuthProvidersAddCommand is used to scaffold a new auth provider in a local app directory
+type AuthProvidersAddCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (apac *AuthProvidersAddCommand) Synopsis() string {
+	return `Add an auth provider to an app.`
+}
+
+// Help returns long-form help information for this command
+func (apac *AuthProvidersAddCommand) Help() string {
+	return `Add an auth provider of the given type to the local app directory, prompting for its settings.
+
+Secrets such as OAuth client secrets are written to secrets.json rather than to the auth provider's
+config, so they can be kept out of version control.
+
+Usage: stitch-cli auth-providers add <type> [options]
+
+Supported types: ` + strings.Join(utils.AuthProviderTypes(), ", ") + `
+
+OPTIONS:` +
+		apac.appCommand.Help()
+}
+
+// Run executes the command
+func (apac *AuthProvidersAddCommand) Run(args []string) int {
+	apac.NewFlagSet()
+
+	if err := apac.BaseCommand.run(args); err != nil {
+		apac.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := apac.add(); err != nil {
+		apac.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (apac *AuthProvidersAddCommand) add() error {
+	if err := apac.checkPositionalArgs(1, 1, errAuthProviderTypeRequired); err != nil {
+		return err
+	}
+	providerType := apac.positionalArgs[0]
+
+	template, ok := utils.AuthProviderTemplates[providerType]
+	if !ok {
+		return fmt.Errorf("unsupported auth provider type %q, must be one of: %s", providerType, strings.Join(utils.AuthProviderTypes(), ", "))
+	}
+
+	appPath, err := apac.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	providers, err := utils.ReadLocalAuthProviders(appPath)
+	if err != nil {
+		return err
+	}
+
+	for _, provider := range providers {
+		if provider.Type() == providerType {
+			return fmt.Errorf("an auth provider of type %q already exists in %s", providerType, provider.Path)
+		}
+	}
+
+	fields := map[string]string{}
+	for _, field := range template.Fields {
+		value, err := apac.Ask(fmt.Sprintf("%s (%s)", field.Description, field.Name), field.Default)
+		if err != nil {
+			return err
+		}
+		fields[field.Name] = value
+	}
+
+	secrets := map[string]string{}
+	for _, field := range template.SecretFields {
+		value, err := apac.UI.AskSecret(fmt.Sprintf("%s (%s):", field.Description, field.Name))
+		if err != nil {
+			return err
+		}
+		secrets[field.Name] = value
+	}
+
+	provider := template.NewLocalAuthProvider(appPath, fields)
+	if _, err := os.Stat(provider.Path); err == nil {
+		return fmt.Errorf("%s already exists", provider.Path)
+	}
+
+	if err := utils.WriteLocalAuthProvider(provider); err != nil {
+		return err
+	}
+
+	apac.UI.Info(fmt.Sprintf("Added auth provider '%s' in %s", provider.Name(), provider.Path))
+
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	if err := utils.WriteLocalSecrets(appPath, authProvidersSecretsSection, provider.Name(), secrets); err != nil {
+		return err
+	}
+
+	apac.UI.Info(fmt.Sprintf("Stored the secrets of auth provider '%s' in %s", provider.Name(), utils.SecretsFilePath(appPath)))
+	return nil
+}
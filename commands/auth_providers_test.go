@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAuthProvidersListCommand(t *testing.T) {
+	t.Run("should list local auth providers", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAuthProvidersListCommandFactory)
+		listCommand := cmd.(*AuthProvidersListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `NAME       TYPE       STATUS
+anon-user  anon-user  enabled
+api-key    api-key    enabled
+`)
+	})
+}
+
+func TestAuthProvidersToggleCommand(t *testing.T) {
+	t.Run("should require an auth provider name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAuthProvidersDisableCommandFactory)
+		disableCommand := cmd.(*AuthProvidersToggleCommand)
+		disableCommand.storage = u.NewEmptyStorage()
+
+		exitCode := disableCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errAuthProviderNameRequired.Error())
+	})
+
+	t.Run("should disable and enable a local auth provider", func(t *testing.T) {
+		appDir := copyTestdataDirToTempApp(t, "auth_providers")
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewAuthProvidersDisableCommandFactory)
+		disableCommand := cmd.(*AuthProvidersToggleCommand)
+		disableCommand.storage = u.NewEmptyStorage()
+
+		exitCode := disableCommand.Run([]string{"api-key", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		provider, err := utils.ReadLocalAuthProvider(appDir, "api-key")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, provider.Disabled(), gc.ShouldBeTrue)
+		u.So(t, provider.Config["_id"], gc.ShouldEqual, "5a010e184870c524eabef93d")
+
+		cmd, _ = setUpAppCommand(NewAuthProvidersEnableCommandFactory)
+		enableCommand := cmd.(*AuthProvidersToggleCommand)
+		enableCommand.storage = u.NewEmptyStorage()
+
+		exitCode = enableCommand.Run([]string{"api-key", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+
+		provider, err = utils.ReadLocalAuthProvider(appDir, "api-key")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, provider.Disabled(), gc.ShouldBeFalse)
+	})
+}
+
+func TestAuthProvidersAddCommand(t *testing.T) {
+	t.Run("should reject an unsupported type", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAuthProvidersAddCommandFactory)
+		addCommand := cmd.(*AuthProvidersAddCommand)
+		addCommand.storage = u.NewEmptyStorage()
+
+		exitCode := addCommand.Run([]string{"oauth2-myspace", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `unsupported auth provider type "oauth2-myspace"`)
+	})
+
+	t.Run("should refuse to add a second provider of the same type", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewAuthProvidersAddCommandFactory)
+		addCommand := cmd.(*AuthProvidersAddCommand)
+		addCommand.storage = u.NewEmptyStorage()
+
+		exitCode := addCommand.Run([]string{"api-key", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `an auth provider of type "api-key" already exists`)
+	})
+
+	t.Run("should scaffold an OAuth provider and store its secret in secrets.json", func(t *testing.T) {
+		appDir := copyTestdataDirToTempApp(t, "auth_providers")
+		defer os.RemoveAll(appDir)
+
+		u.So(t, ioutil.WriteFile(filepath.Join(appDir, "secrets.json"), []byte(`{"services": {"svc": {"auth_token": "token"}}}`), 0600), gc.ShouldBeNil)
+
+		cmd, mockUI := setUpAppCommand(NewAuthProvidersAddCommandFactory)
+		addCommand := cmd.(*AuthProvidersAddCommand)
+		addCommand.storage = u.NewEmptyStorage()
+		mockUI.InputReader = strings.NewReader("my-client-id\nmy-client-secret\n")
+
+		exitCode := addCommand.Run([]string{"oauth2-google", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		provider, err := utils.ReadLocalAuthProvider(appDir, "oauth2-google")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, provider.Path, gc.ShouldEqual, filepath.Join(appDir, "auth_providers", "oauth2-google.json"))
+		u.So(t, provider.Type(), gc.ShouldEqual, "oauth2-google")
+		u.So(t, provider.Config["config"], gc.ShouldResemble, map[string]interface{}{"clientId": "my-client-id"})
+		u.So(t, provider.Config["redirect_uris"], gc.ShouldResemble, []interface{}{})
+
+		data, err := ioutil.ReadFile(filepath.Join(appDir, "secrets.json"))
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(data), gc.ShouldNotContainSubstring, "my-client-id")
+
+		var secrets map[string]interface{}
+		u.So(t, json.Unmarshal(data, &secrets), gc.ShouldBeNil)
+		u.So(t, secrets, gc.ShouldResemble, map[string]interface{}{
+			"services":       map[string]interface{}{"svc": map[string]interface{}{"auth_token": "token"}},
+			"auth_providers": map[string]interface{}{"oauth2-google": map[string]interface{}{"clientSecret": "my-client-secret"}},
+		})
+
+		app, err := utils.UnmarshalFromDir(appDir)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app["secrets"], gc.ShouldResemble, secrets)
+	})
+
+	t.Run("should scaffold a provider without settings", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewAuthProvidersAddCommandFactory)
+		addCommand := cmd.(*AuthProvidersAddCommand)
+		addCommand.storage = u.NewEmptyStorage()
+
+		exitCode := addCommand.Run([]string{"anon-user", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		provider, err := utils.ReadLocalAuthProvider(appDir, "anon-user")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, provider.Disabled(), gc.ShouldBeFalse)
+
+		_, err = os.Stat(filepath.Join(appDir, "secrets.json"))
+		u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	})
+}
@@ -0,0 +1,332 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/10gen/stitch-cli/api"
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	backupFlagAll         = "all"
+	backupFlagDest        = "dest"
+	backupFlagConcurrency = "concurrency"
+	backupFlagZip         = "zip"
+	backupFlagKeep        = "keep"
+	backupFlagMaxAge      = "max-age"
+
+	defaultBackupConcurrency = 4
+)
+
+var (
+	errBackupScopeRequired = fmt.Errorf("either a project (--%s=[string]) or --%s must be supplied", flagProjectIDName, backupFlagAll)
+	errBackupScopeConflict = fmt.Errorf("--%s and --%s cannot be supplied together", flagProjectIDName, backupFlagAll)
+)
+
+// NewBackupCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewBackupCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		return &BackupCommand{
+			BaseCommand: &BaseCommand{
+				Name: "backup",
+				UI:   ui,
+			},
+			now: time.Now,
+		}, nil
+	}
+}
+
+// BackupCommand is used to export every app of one or all projects
+type BackupCommand struct {
+	*BaseCommand
+
+	now func() time.Time
+
+	flagProjectID   string
+	flagAll         bool
+	flagDest        string
+	flagConcurrency int
+	flagZip         bool
+	flagKeep        int
+	flagMaxAge      time.Duration
+}
+
+// Synopsis returns a one-liner description for this command
+func (bc *BackupCommand) Synopsis() string {
+	return `Export every app of a project, or of all projects, into a timestamped backup.`
+}
+
+// Help returns long-form help information for this command
+func (bc *BackupCommand) Help() string {
+	return `Export every app of a project, or of all projects you have access to, into a timestamped backup.
+
+Each backup is a directory named after the time it was created (e.g. "20180301T120000Z") within the
+destination directory. It holds one export per app, named after the App ID, along with a manifest.json
+listing the exported apps and the SHA-256 checksum of each export.
+
+Usage: stitch-cli backup --project-id [string] [options]
+       stitch-cli backup --all [options]
+
+OPTIONS:
+  --project-id [string]
+	The Atlas Project ID whose apps to back up.
+
+  --all
+	Back up the apps of every project you have access to.
+
+  --dest [string] (default: .)
+	The directory to create the backup in.
+
+  --concurrency [int] (default: 4)
+	How many apps to export at once.
+
+  --zip
+	Keep each export as a zip file rather than unpacking it into a directory.
+
+  --keep [int]
+	Remove the oldest backups within the destination directory so that only this many remain.
+
+  --max-age [duration]
+	Remove the backups within the destination directory older than this (e.g. "168h").
+
+Backups are only pruned when every app was exported successfully.
+` +
+		bc.BaseCommand.Help()
+}
+
+// Run executes the command
+func (bc *BackupCommand) Run(args []string) int {
+	set := bc.NewFlagSet()
+
+	set.StringVar(&bc.flagProjectID, flagProjectIDName, "", "")
+	set.BoolVar(&bc.flagAll, backupFlagAll, false, "")
+	set.StringVar(&bc.flagDest, backupFlagDest, ".", "")
+	set.IntVar(&bc.flagConcurrency, backupFlagConcurrency, defaultBackupConcurrency, "")
+	set.BoolVar(&bc.flagZip, backupFlagZip, false, "")
+	set.IntVar(&bc.flagKeep, backupFlagKeep, 0, "")
+	set.DurationVar(&bc.flagMaxAge, backupFlagMaxAge, 0, "")
+
+	if err := bc.BaseCommand.run(args); err != nil {
+		bc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := bc.backup(); err != nil {
+		bc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (bc *BackupCommand) backup() error {
+	if bc.flagProjectID == "" && !bc.flagAll {
+		return errBackupScopeRequired
+	}
+
+	if bc.flagProjectID != "" && bc.flagAll {
+		return errBackupScopeConflict
+	}
+
+	if bc.flagConcurrency < 1 {
+		return fmt.Errorf("--%s must be at least 1", backupFlagConcurrency)
+	}
+
+	if bc.flagKeep < 0 || bc.flagMaxAge < 0 {
+		return fmt.Errorf("--%s and --%s must not be negative", backupFlagKeep, backupFlagMaxAge)
+	}
+
+	if err := bc.requireLogin(); err != nil {
+		return err
+	}
+
+	dest, err := homedir.Expand(bc.flagDest)
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := bc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	apps, err := bc.fetchApps(stitchClient)
+	if err != nil {
+		return err
+	}
+
+	if len(apps) == 0 {
+		bc.UI.Info("No apps found")
+		return nil
+	}
+
+	createdAt := bc.now().UTC()
+	backupDir := filepath.Join(dest, utils.BackupDirectoryName(createdAt))
+	if _, err := os.Stat(backupDir); err == nil {
+		return fmt.Errorf("backup %q already exists", backupDir)
+	}
+
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %q: %s", backupDir, err)
+	}
+
+	records := bc.exportApps(stitchClient, apps, backupDir)
+
+	manifest := utils.BackupManifest{CreatedAt: createdAt, Apps: records}
+	if err := utils.WriteJSONFile(filepath.Join(backupDir, utils.BackupManifestFileName), manifest); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %s", err)
+	}
+
+	var failed int
+	for _, record := range records {
+		if record.Error != "" {
+			failed++
+			bc.UI.Error(fmt.Sprintf("failed to back up '%s': %s", record.ClientAppID, record.Error))
+			continue
+		}
+		bc.UI.Info(fmt.Sprintf("Backed up '%s' to %s", record.ClientAppID, filepath.Join(backupDir, record.Path)))
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("failed to back up %d of %d app(s), skipping pruning", failed, len(records))
+	}
+
+	bc.UI.Info(fmt.Sprintf("Successfully backed up %d app(s) to %s", len(records), backupDir))
+
+	if bc.flagKeep == 0 && bc.flagMaxAge == 0 {
+		return nil
+	}
+
+	removed, err := utils.PruneBackups(dest, bc.flagKeep, bc.flagMaxAge, createdAt)
+	for _, path := range removed {
+		bc.UI.Info(fmt.Sprintf("Removed backup %s", path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to prune backups: %s", err)
+	}
+
+	return nil
+}
+
+// fetchApps returns the apps of the requested project, or of every project of the user
+func (bc *BackupCommand) fetchApps(stitchClient api.StitchClient) ([]*models.App, error) {
+	groupIDs := []string{bc.flagProjectID}
+	if bc.flagAll {
+		profile, err := stitchClient.FetchUserProfile()
+		if err != nil {
+			return nil, err
+		}
+
+		groupIDs = []string{}
+		seen := map[string]bool{}
+		for _, groupID := range profile.AllGroupIDs() {
+			if !seen[groupID] {
+				seen[groupID] = true
+				groupIDs = append(groupIDs, groupID)
+			}
+		}
+	}
+
+	apps := []*models.App{}
+	for _, groupID := range groupIDs {
+		groupApps, err := stitchClient.FetchAppsByGroupID(groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the apps of project %s: %s", groupID, err)
+		}
+		apps = append(apps, groupApps...)
+	}
+
+	return apps, nil
+}
+
+// exportApps exports apps into backupDir using up to --concurrency exports at once, returning a record
+// of each export in the order of apps
+func (bc *BackupCommand) exportApps(stitchClient api.StitchClient, apps []*models.App, backupDir string) []utils.BackupAppRecord {
+	records := make([]utils.BackupAppRecord, len(apps))
+	indexes := make(chan int)
+
+	workers := bc.flagConcurrency
+	if workers > len(apps) {
+		workers = len(apps)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				records[idx] = bc.exportApp(stitchClient, apps[idx], backupDir)
+			}
+		}()
+	}
+
+	for i := range apps {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return records
+}
+
+func (bc *BackupCommand) exportApp(stitchClient api.StitchClient, app *models.App, backupDir string) utils.BackupAppRecord {
+	record := utils.BackupAppRecord{
+		GroupID:     app.GroupID,
+		AppID:       app.ID,
+		ClientAppID: app.ClientAppID,
+		Name:        app.Name,
+	}
+
+	path, checksum, err := bc.writeExport(stitchClient, app, backupDir)
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+
+	record.Path = path
+	record.Checksum = checksum
+	return record
+}
+
+// writeExport exports app into backupDir, returning the path of the export relative to backupDir and
+// the checksum of the exported zip
+func (bc *BackupCommand) writeExport(stitchClient api.StitchClient, app *models.App, backupDir string) (string, string, error) {
+	if app.ClientAppID == "" {
+		return "", "", errors.New("app has no App ID")
+	}
+
+	_, body, err := stitchClient.Export(app.GroupID, app.ID, false)
+	if err != nil {
+		return "", "", err
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", "", err
+	}
+
+	path := app.ClientAppID
+	if bc.flagZip {
+		path += ".zip"
+		if err := ioutil.WriteFile(filepath.Join(backupDir, path), data, 0600); err != nil {
+			return "", "", err
+		}
+	} else if err := utils.WriteZipToDir(filepath.Join(backupDir, path), bytes.NewReader(data), false); err != nil {
+		return "", "", err
+	}
+
+	return path, utils.Checksum(data), nil
+}
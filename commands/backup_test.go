@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+var backupTime = time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func setUpBackupCommand(t *testing.T) (*BackupCommand, *cli.MockUi, *[]string) {
+	mockUI := cli.NewMockUi()
+	cmd, err := NewBackupCommandFactory(mockUI)()
+	u.So(t, err, gc.ShouldBeNil)
+
+	backupCommand := cmd.(*BackupCommand)
+	backupCommand.storage = u.NewEmptyStorage()
+	backupCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+	backupCommand.now = func() time.Time { return backupTime }
+
+	appsByGroupID := map[string][]*models.App{
+		"group-1": {
+			{GroupID: "group-1", ID: "id-a", ClientAppID: "app-a-abcde", Name: "app-a"},
+			{GroupID: "group-1", ID: "id-b", ClientAppID: "app-b-abcde", Name: "app-b"},
+		},
+		"group-2": {
+			{GroupID: "group-2", ID: "id-c", ClientAppID: "app-c-abcde", Name: "app-c"},
+		},
+	}
+
+	var mu sync.Mutex
+	exportedAppIDs := []string{}
+	backupCommand.stitchClient = &u.MockStitchClient{
+		FetchUserProfileFn: func() (*models.UserProfile, error) {
+			var profile models.UserProfile
+			err := json.Unmarshal([]byte(`{"roles": [{"group_id": "group-1"}, {"group_id": "group-2"}, {"group_id": "group-1"}]}`), &profile)
+			return &profile, err
+		},
+		FetchAppsByGroupIDFn: func(groupID string) ([]*models.App, error) {
+			return appsByGroupID[groupID], nil
+		},
+		ExportFn: func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+			mu.Lock()
+			exportedAppIDs = append(exportedAppIDs, appID)
+			mu.Unlock()
+
+			if appID == "id-b" {
+				return "", nil, errors.New("export failed")
+			}
+			return appID + "_20180301.zip", u.NewResponseBody(bytes.NewReader(u.NewZip(map[string]string{
+				models.AppConfigFileName: `{"name": "` + appID + `"}`,
+			}))), nil
+		},
+	}
+
+	return backupCommand, mockUI, &exportedAppIDs
+}
+
+func readBackupManifest(t *testing.T, backupDir string) utils.BackupManifest {
+	data, err := ioutil.ReadFile(filepath.Join(backupDir, utils.BackupManifestFileName))
+	u.So(t, err, gc.ShouldBeNil)
+
+	var manifest utils.BackupManifest
+	u.So(t, json.Unmarshal(data, &manifest), gc.ShouldBeNil)
+	return manifest
+}
+
+func TestBackupCommand(t *testing.T) {
+	t.Run("should require a project or --all", func(t *testing.T) {
+		for _, tc := range []struct {
+			args     []string
+			expected error
+		}{
+			{[]string{}, errBackupScopeRequired},
+			{[]string{"--project-id=group-1", "--all"}, errBackupScopeConflict},
+		} {
+			backupCommand, mockUI, _ := setUpBackupCommand(t)
+
+			exitCode := backupCommand.Run(tc.args)
+			u.So(t, exitCode, gc.ShouldEqual, 1)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, tc.expected.Error())
+		}
+	})
+
+	t.Run("should export the apps of a project and write a manifest with checksums", func(t *testing.T) {
+		dest, err := ioutil.TempDir("", "stitch-backup")
+		u.So(t, err, gc.ShouldBeNil)
+		defer os.RemoveAll(dest)
+
+		backupCommand, mockUI, exportedAppIDs := setUpBackupCommand(t)
+
+		exitCode := backupCommand.Run([]string{"--project-id=group-2", "--dest=" + dest, "--zip"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, *exportedAppIDs, gc.ShouldResemble, []string{"id-c"})
+
+		backupDir := filepath.Join(dest, "20180301T120000Z")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Successfully backed up 1 app(s) to "+backupDir)
+
+		data, err := ioutil.ReadFile(filepath.Join(backupDir, "app-c-abcde.zip"))
+		u.So(t, err, gc.ShouldBeNil)
+
+		u.So(t, readBackupManifest(t, backupDir), gc.ShouldResemble, utils.BackupManifest{
+			CreatedAt: backupTime,
+			Apps: []utils.BackupAppRecord{
+				{GroupID: "group-2", AppID: "id-c", ClientAppID: "app-c-abcde", Name: "app-c", Path: "app-c-abcde.zip", Checksum: utils.Checksum(data)},
+			},
+		})
+	})
+
+	t.Run("should back up every project in parallel and report failed exports", func(t *testing.T) {
+		dest, err := ioutil.TempDir("", "stitch-backup")
+		u.So(t, err, gc.ShouldBeNil)
+		defer os.RemoveAll(dest)
+
+		backupCommand, mockUI, exportedAppIDs := setUpBackupCommand(t)
+
+		exitCode := backupCommand.Run([]string{"--all", "--dest=" + dest, "--concurrency=2", "--keep=1"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, *exportedAppIDs, gc.ShouldHaveLength, 3)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "failed to back up 'app-b-abcde': export failed")
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "failed to back up 1 of 3 app(s), skipping pruning")
+
+		backupDir := filepath.Join(dest, "20180301T120000Z")
+		manifest := readBackupManifest(t, backupDir)
+		u.So(t, manifest.Apps, gc.ShouldHaveLength, 3)
+		u.So(t, manifest.Apps[0].ClientAppID, gc.ShouldEqual, "app-a-abcde")
+		u.So(t, manifest.Apps[0].Checksum, gc.ShouldNotBeEmpty)
+		u.So(t, manifest.Apps[1].Error, gc.ShouldEqual, "export failed")
+		u.So(t, manifest.Apps[2].ClientAppID, gc.ShouldEqual, "app-c-abcde")
+
+		appInstanceData := readInitializedAppConfig(t, filepath.Join(backupDir, "app-a-abcde"))
+		u.So(t, appInstanceData.AppName(), gc.ShouldEqual, "id-a")
+	})
+
+	t.Run("should prune old backups by count and age", func(t *testing.T) {
+		dest, err := ioutil.TempDir("", "stitch-backup")
+		u.So(t, err, gc.ShouldBeNil)
+		defer os.RemoveAll(dest)
+
+		for _, name := range []string{"20180101T000000Z", "20180225T000000Z", "20180228T000000Z", "20180227T000000Z"} {
+			u.So(t, utils.WriteJSONFile(filepath.Join(dest, name, utils.BackupManifestFileName), utils.BackupManifest{}), gc.ShouldBeNil)
+		}
+		u.So(t, os.MkdirAll(filepath.Join(dest, "20170101T000000Z"), 0700), gc.ShouldBeNil)
+
+		backupCommand, mockUI, _ := setUpBackupCommand(t)
+
+		exitCode := backupCommand.Run([]string{"--project-id=group-2", "--dest=" + dest, "--keep=4", "--max-age=72h"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Removed backup "+filepath.Join(dest, "20180101T000000Z"))
+
+		backups, err := utils.ListBackups(dest)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, backups, gc.ShouldResemble, []string{
+			filepath.Join(dest, "20180227T000000Z"),
+			filepath.Join(dest, "20180228T000000Z"),
+			filepath.Join(dest, "20180301T120000Z"),
+		})
+
+		_, err = os.Stat(filepath.Join(dest, "20170101T000000Z"))
+		u.So(t, err, gc.ShouldBeNil)
+	})
+}
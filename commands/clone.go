@@ -0,0 +1,346 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/10gen/stitch-cli/api"
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	cloneFlagFrom        = "from"
+	cloneFlagFromProject = "from-project"
+	cloneFlagTo          = "to"
+	cloneFlagToProject   = "to-project"
+	cloneFlagName        = "name"
+	cloneFlagAsTemplate  = "as-template"
+	cloneFlagCluster     = "cluster"
+)
+
+var (
+	errCloneSourceRequired = fmt.Errorf("an App ID to clone (--%s=[string]) must be supplied", cloneFlagFrom)
+	errCloneTargetRequired = fmt.Errorf("either a target App ID (--%s=[string]) or a target project (--%s=[string]) must be supplied", cloneFlagTo, cloneFlagToProject)
+	errCloneTargetConflict = fmt.Errorf("--%s and --%s cannot be supplied together", cloneFlagTo, cloneFlagToProject)
+)
+
+// NewCloneCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewCloneCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		return &CloneCommand{
+			BaseCommand: &BaseCommand{
+				Name: "clone",
+				UI:   ui,
+			},
+		}, nil
+	}
+}
+
+// CloneCommand is used to copy the configuration of a deployed app into another app
+type CloneCommand struct {
+	*BaseCommand
+
+	flagFrom           string
+	flagFromProject    string
+	flagTo             string
+	flagToProject      string
+	flagName           string
+	flagAsTemplate     bool
+	flagStrategy       string
+	flagRedactedValues string
+	flagClusters       keyValueFlag
+}
+
+// Synopsis returns a one-liner description for this command
+func (cc *CloneCommand) Synopsis() string {
+	return `Copy a deployed app into another app, such as promoting staging to production.`
+}
+
+// Help returns long-form help information for this command
+func (cc *CloneCommand) Help() string {
+	return `Copy the configuration of a deployed app into another existing app, or into a new app in a project.
+
+The IDs of the source's entities are dropped and its name is replaced with the target's. The clusters of
+its mongodb-atlas services can be replaced with --cluster, or are prompted for. Secrets, private values and
+secret-like service configuration fields are not exported, so their values are prompted for unless
+supplied with --redacted-values. Changes to an existing app are shown for confirmation before importing.
+
+Usage: stitch-cli clone --from [string] --to [string] [options]
+       stitch-cli clone --from [string] --to-project [string] [--name [string]] [options]
+
+OPTIONS:
+  --from [string]
+	The App ID of the app to clone.
+
+  --from-project [string]
+	The Atlas Project ID of the app to clone, as opposed to looking it up among the projects of the user.
+
+  --to [string]
+	The App ID of the existing app to clone into.
+
+  --to-project [string]
+	The Atlas Project ID to create a new app in.
+
+  --name [string]
+	The name of the new app created with --to-project. Defaults to the name of the cloned app.
+
+  --as-template
+	Export the cloned app as a template.
+
+  --cluster [old=new]
+	Replace the Atlas cluster named old with the one named new. May be repeated.
+
+  --strategy [merge|replace] (default: replace)
+	How the cloned app should be imported into an existing app. See "stitch-cli import --help".
+
+  --redacted-values [string]
+	A path to a JSON file holding the values of the redacted fields, in the format of the
+	"secrets.template.json" file written by "export --redact".
+` +
+		cc.BaseCommand.Help()
+}
+
+// Run executes the command
+func (cc *CloneCommand) Run(args []string) int {
+	set := cc.NewFlagSet()
+
+	set.StringVar(&cc.flagFrom, cloneFlagFrom, "", "")
+	set.StringVar(&cc.flagFromProject, cloneFlagFromProject, "", "")
+	set.StringVar(&cc.flagTo, cloneFlagTo, "", "")
+	set.StringVar(&cc.flagToProject, cloneFlagToProject, "", "")
+	set.StringVar(&cc.flagName, cloneFlagName, "", "")
+	set.BoolVar(&cc.flagAsTemplate, cloneFlagAsTemplate, false, "")
+	set.StringVar(&cc.flagStrategy, importFlagStrategy, importStrategyReplace, "")
+	set.StringVar(&cc.flagRedactedValues, importFlagRedacted, "", "")
+
+	cc.flagClusters = keyValueFlag{}
+	set.Var(cc.flagClusters, cloneFlagCluster, "")
+
+	if err := cc.BaseCommand.run(args); err != nil {
+		cc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := cc.clone(); err != nil {
+		cc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (cc *CloneCommand) clone() error {
+	if cc.flagFrom == "" {
+		return errCloneSourceRequired
+	}
+
+	if cc.flagTo == "" && cc.flagToProject == "" {
+		return errCloneTargetRequired
+	}
+
+	if cc.flagTo != "" && cc.flagToProject != "" {
+		return errCloneTargetConflict
+	}
+
+	if cc.flagStrategy != importStrategyMerge && cc.flagStrategy != importStrategyReplace {
+		return fmt.Errorf("unknown import strategy %q; accepted values are [%s|%s]", cc.flagStrategy, importStrategyMerge, importStrategyReplace)
+	}
+
+	if err := cc.requireLogin(); err != nil {
+		return err
+	}
+
+	stitchClient, err := cc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	source, err := cc.fetchApp(stitchClient, cc.flagFromProject, cc.flagFrom)
+	if err != nil {
+		return err
+	}
+
+	var target *models.App
+	if cc.flagTo != "" {
+		if target, err = cc.fetchApp(stitchClient, "", cc.flagTo); err != nil {
+			return err
+		}
+
+		if target.ID == source.ID {
+			return fmt.Errorf("cannot clone '%s' into itself", source.ClientAppID)
+		}
+	}
+
+	app, err := cc.exportApp(stitchClient, source)
+	if err != nil {
+		return err
+	}
+
+	utils.StripEntityIDs(app)
+
+	if err := cc.remapClusters(app); err != nil {
+		return err
+	}
+
+	if err := cc.resolveRedactedValues(app); err != nil {
+		return err
+	}
+
+	isNew := target == nil
+	if isNew {
+		name := cc.flagName
+		if name == "" {
+			name = source.Name
+		}
+
+		if target, err = cc.createEmptyApp(cc.flagToProject, name, stitchClient); err != nil {
+			return err
+		}
+	}
+
+	app[models.AppIDField] = target.ClientAppID
+	app[models.AppNameField] = target.Name
+
+	appData, err := json.Marshal(app)
+	if err != nil {
+		return err
+	}
+
+	strategy := cc.flagStrategy
+	if isNew {
+		strategy = importStrategyReplace
+	}
+
+	// Diff changes unless -y flag has been provided or if this is a new app
+	if !cc.flagYes && !isNew {
+		diffs, err := stitchClient.Diff(target.GroupID, target.ID, appData, strategy)
+		if err != nil {
+			return fmt.Errorf("failed to diff app with currently deployed instance: %s", err)
+		}
+
+		if len(diffs) == 0 {
+			cc.UI.Info("Deployed app is identical to proposed version, nothing to do.")
+			return nil
+		}
+
+		cc.showImportDiffs(diffs, app, func() (map[string]interface{}, error) {
+			return exportDeployedApp(stitchClient, target)
+		})
+
+		confirm, err := cc.AskYesNo("Please confirm the changes shown above:")
+		if err != nil {
+			return err
+		}
+
+		if !confirm {
+			return nil
+		}
+	}
+
+	if err := stitchClient.Import(target.GroupID, target.ID, appData, strategy); err != nil {
+		return fmt.Errorf("failed to import app: %s", err)
+	}
+
+	cc.UI.Info(fmt.Sprintf("Successfully cloned '%s' into '%s'", source.ClientAppID, target.ClientAppID))
+	return nil
+}
+
+func (cc *CloneCommand) fetchApp(stitchClient api.StitchClient, projectID, clientAppID string) (*models.App, error) {
+	if projectID != "" {
+		return stitchClient.FetchAppByGroupIDAndClientAppID(projectID, clientAppID)
+	}
+
+	return stitchClient.FetchAppByClientAppID(clientAppID)
+}
+
+// exportApp exports the redacted configuration of the app and unpacks it into app data
+func (cc *CloneCommand) exportApp(stitchClient api.StitchClient, app *models.App) (map[string]interface{}, error) {
+	_, body, err := stitchClient.Export(app.GroupID, app.ID, cc.flagAsTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export '%s': %s", app.ClientAppID, err)
+	}
+	defer body.Close()
+
+	redacted, err := utils.RedactAppZip(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export '%s': %s", app.ClientAppID, err)
+	}
+
+	return utils.UnmarshalFromZip(redacted)
+}
+
+// remapClusters replaces the clusters of the app's mongodb-atlas services with those given by --cluster,
+// prompting for the replacement of any others
+func (cc *CloneCommand) remapClusters(app map[string]interface{}) error {
+	clustersByService := utils.ClusterNames(app)
+
+	services := make([]string, 0, len(clustersByService))
+	for service := range clustersByService {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	replacements := map[string]string{}
+	for old, replacement := range cc.flagClusters {
+		replacements[old] = replacement
+	}
+
+	for _, service := range services {
+		cluster := clustersByService[service]
+		if _, ok := replacements[cluster]; ok {
+			continue
+		}
+
+		replacement, err := cc.Ask(fmt.Sprintf("Cluster to use in place of '%s' (service '%s')", cluster, service), cluster)
+		if err != nil {
+			return err
+		}
+		replacements[cluster] = replacement
+	}
+
+	utils.RemapClusterNames(app, replacements)
+	return nil
+}
+
+// resolveRedactedValues fills in the fields stripped from the export, read from --redacted-values or
+// prompted for
+func (cc *CloneCommand) resolveRedactedValues(app map[string]interface{}) error {
+	keys := utils.RedactedKeys(app)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values := map[string]json.RawMessage{}
+	if cc.flagRedactedValues != "" {
+		path, err := homedir.Expand(cc.flagRedactedValues)
+		if err != nil {
+			return err
+		}
+
+		if err := utils.ReadAndUnmarshalInto(json.Unmarshal, path, &values); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		if !utils.IsRedactedValueMissing(values[key]) {
+			continue
+		}
+
+		value, err := cc.UI.AskSecret(fmt.Sprintf("Value for %s:", key))
+		if err != nil {
+			return err
+		}
+
+		if values[key], err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+
+	return utils.ResolveRedactedValues(app, values)
+}
@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+type cloneCalls struct {
+	created  []string
+	diffed   bool
+	imported map[string]interface{}
+	strategy string
+}
+
+func setUpCloneCommand(t *testing.T) (*CloneCommand, *cli.MockUi, *cloneCalls) {
+	mockUI := cli.NewMockUi()
+	cmd, err := NewCloneCommandFactory(mockUI)()
+	u.So(t, err, gc.ShouldBeNil)
+
+	cloneCommand := cmd.(*CloneCommand)
+	cloneCommand.storage = u.NewEmptyStorage()
+	cloneCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+	exported := u.NewZip(map[string]string{
+		"stitch.json":                             `{"app_id": "staging-abcde", "name": "staging", "config_version": 20180301}`,
+		"values/":                                 "",
+		"values/apiKey.json":                      `{"id": "value-id", "name": "apiKey", "value": "staging-key", "private": true}`,
+		"services/":                               "",
+		"services/mongodb-atlas/":                 "",
+		"services/mongodb-atlas/rules/":           "",
+		"services/mongodb-atlas/config.json":      `{"id": "service-id", "name": "mongodb-atlas", "type": "mongodb-atlas", "config": {"clusterName": "Staging"}}`,
+		"services/mongodb-atlas/rules/items.json": `{"id": "rule-id", "namespace": "db.items", "roles": []}`,
+	})
+
+	apps := map[string]*models.App{
+		"staging-abcde": {GroupID: "staging-group", ID: "staging-id", ClientAppID: "staging-abcde", Name: "staging"},
+		"prod-abcde":    {GroupID: "prod-group", ID: "prod-id", ClientAppID: "prod-abcde", Name: "prod"},
+	}
+
+	calls := &cloneCalls{}
+	cloneCommand.stitchClient = &u.MockStitchClient{
+		FetchAppByClientAppIDFn: func(clientAppID string) (*models.App, error) {
+			return apps[clientAppID], nil
+		},
+		ExportFn: func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+			return "staging_20180301.zip", u.NewResponseBody(bytes.NewReader(exported)), nil
+		},
+		FetchAppsByGroupIDFn: func(groupID string) ([]*models.App, error) {
+			return []*models.App{}, nil
+		},
+		CreateEmptyAppFn: func(groupID, appName string) (*models.App, error) {
+			calls.created = append(calls.created, groupID+"/"+appName)
+			return &models.App{GroupID: groupID, ID: "new-id", ClientAppID: appName + "-fghij", Name: appName}, nil
+		},
+		DiffFn: func(groupID, appID string, appData []byte, strategy string) ([]string, error) {
+			calls.diffed = true
+			return []string{"sample-diff-contents"}, nil
+		},
+		ImportFn: func(groupID, appID string, appData []byte, strategy string) error {
+			calls.strategy = strategy
+			return json.Unmarshal(appData, &calls.imported)
+		},
+	}
+
+	return cloneCommand, mockUI, calls
+}
+
+func TestCloneCommand(t *testing.T) {
+	t.Run("should require a source and a single target", func(t *testing.T) {
+		for _, tc := range []struct {
+			args     []string
+			expected error
+		}{
+			{[]string{"--to=prod-abcde"}, errCloneSourceRequired},
+			{[]string{"--from=staging-abcde"}, errCloneTargetRequired},
+			{[]string{"--from=staging-abcde", "--to=prod-abcde", "--to-project=prod-group"}, errCloneTargetConflict},
+		} {
+			cloneCommand, mockUI, _ := setUpCloneCommand(t)
+
+			exitCode := cloneCommand.Run(tc.args)
+			u.So(t, exitCode, gc.ShouldEqual, 1)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, tc.expected.Error())
+		}
+	})
+
+	t.Run("should clone into an existing app after confirming the diff", func(t *testing.T) {
+		cloneCommand, mockUI, calls := setUpCloneCommand(t)
+		mockUI.InputReader = strings.NewReader("prod-key\ny\n")
+
+		exitCode := cloneCommand.Run([]string{"--from=staging-abcde", "--to=prod-abcde", "--cluster=Staging=Production"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, calls.diffed, gc.ShouldBeTrue)
+		u.So(t, calls.created, gc.ShouldBeEmpty)
+		u.So(t, calls.strategy, gc.ShouldEqual, importStrategyReplace)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "sample-diff-contents")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Successfully cloned 'staging-abcde' into 'prod-abcde'")
+
+		u.So(t, calls.imported["app_id"], gc.ShouldEqual, "prod-abcde")
+		u.So(t, calls.imported["name"], gc.ShouldEqual, "prod")
+		u.So(t, calls.imported["values"], gc.ShouldResemble, []interface{}{
+			map[string]interface{}{"name": "apiKey", "value": "prod-key", "private": true},
+		})
+		u.So(t, calls.imported["services"], gc.ShouldResemble, []interface{}{
+			map[string]interface{}{
+				"config": map[string]interface{}{
+					"name":   "mongodb-atlas",
+					"type":   "mongodb-atlas",
+					"config": map[string]interface{}{"clusterName": "Production"},
+				},
+				"incoming_webhooks": []interface{}{},
+				"rules": []interface{}{
+					map[string]interface{}{"namespace": "db.items", "roles": []interface{}{}},
+				},
+			},
+		})
+	})
+
+	t.Run("should not import if the diff is not confirmed", func(t *testing.T) {
+		cloneCommand, mockUI, calls := setUpCloneCommand(t)
+		mockUI.InputReader = strings.NewReader("prod-key\nn\n")
+
+		exitCode := cloneCommand.Run([]string{"--from=staging-abcde", "--to=prod-abcde", "--cluster=Staging=Production"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, calls.imported, gc.ShouldBeNil)
+	})
+
+	t.Run("should clone into a new app in another project", func(t *testing.T) {
+		valuesFile, err := ioutil.TempFile("", "redacted-values")
+		u.So(t, err, gc.ShouldBeNil)
+		defer os.Remove(valuesFile.Name())
+
+		_, err = valuesFile.Write([]byte(`{"values/apiKey.json:value": "qa-key"}`))
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, valuesFile.Close(), gc.ShouldBeNil)
+
+		cloneCommand, mockUI, calls := setUpCloneCommand(t)
+
+		exitCode := cloneCommand.Run([]string{
+			"--from=staging-abcde",
+			"--to-project=qa-group",
+			"--name=qa",
+			"--redacted-values=" + valuesFile.Name(),
+			"-y",
+		})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, calls.created, gc.ShouldResemble, []string{"qa-group/qa"})
+		u.So(t, calls.diffed, gc.ShouldBeFalse)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Cluster to use in place of 'Staging' (service 'mongodb-atlas') [Staging]: Staging")
+
+		u.So(t, calls.imported["app_id"], gc.ShouldEqual, "qa-fghij")
+		u.So(t, calls.imported["name"], gc.ShouldEqual, "qa")
+
+		data, err := json.Marshal(calls.imported)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(data), gc.ShouldContainSubstring, `"value":"qa-key"`)
+		u.So(t, string(data), gc.ShouldContainSubstring, `"clusterName":"Staging"`)
+		u.So(t, string(data), gc.ShouldNotContainSubstring, "-id")
+	})
+
+	t.Run("should refuse to clone an app into itself", func(t *testing.T) {
+		cloneCommand, mockUI, _ := setUpCloneCommand(t)
+
+		exitCode := cloneCommand.Run([]string{"--from=staging-abcde", "--to=staging-abcde"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "cannot clone 'staging-abcde' into itself")
+	})
+}
@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+const (
+	clustersFlagCluster     = "cluster"
+	clustersFlagServiceName = "service-name"
+
+	atlasServiceType = "mongodb-atlas"
+)
+
+var errClusterNameRequired = fmt.Errorf("a cluster name (--%s=[string]) must be supplied", clustersFlagCluster)
+
+// NewClustersLinkCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewClustersLinkCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("clusters link", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &ClustersLinkCommand{appCommand: ac}, nil
+	}
+}
+
+// ClustersLinkCommand is used to link an Atlas cluster to a local app directory as a mongodb-atlas service
+type ClustersLinkCommand struct {
+	*appCommand
+
+	flagCluster     string
+	flagServiceName string
+}
+
+// Synopsis returns a one-liner description for this command
+func (clc *ClustersLinkCommand) Synopsis() string {
+	return `Link an Atlas cluster to an app as a MongoDB Atlas service.`
+}
+
+// Help returns long-form help information for this command
+func (clc *ClustersLinkCommand) Help() string {
+	return `Link an Atlas cluster to the local app directory by writing the config of a mongodb-atlas service.
+
+The cluster must exist in the Atlas project of the app, which is looked up from the deployed app
+identified by the "app_id" in the directory's stitch.json unless --project-id is supplied. If the
+service already exists, it is pointed at the given cluster.
+
+Usage: stitch-cli clusters link --cluster [string] [options]
+
+OPTIONS:
+  --cluster [string]
+	The name of the Atlas cluster to link.
+
+  --service-name [string] (default: mongodb-atlas)
+	The name of the service to link the cluster as.
+
+  --project-id [string]
+	The Atlas Project ID the cluster belongs to. Defaults to the project of the deployed app.
+
+  --app-id [string]
+	The App ID of the deployed app whose project the cluster belongs to. Defaults to the "app_id" in the
+	directory's stitch.json.
+` +
+		clc.appCommand.Help()
+}
+
+// Run executes the command
+func (clc *ClustersLinkCommand) Run(args []string) int {
+	set := clc.NewFlagSet()
+
+	set.StringVar(&clc.flagAppID, flagAppIDName, "", "")
+	set.StringVar(&clc.flagProjectID, flagProjectIDName, "", "")
+	set.StringVar(&clc.flagCluster, clustersFlagCluster, "", "")
+	set.StringVar(&clc.flagServiceName, clustersFlagServiceName, atlasServiceType, "")
+
+	if err := clc.BaseCommand.run(args); err != nil {
+		clc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := clc.link(); err != nil {
+		clc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (clc *ClustersLinkCommand) link() error {
+	if clc.flagCluster == "" {
+		return errClusterNameRequired
+	}
+
+	appPath, err := clc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	services, err := utils.ReadLocalServices(appPath)
+	if err != nil {
+		return err
+	}
+
+	var service *utils.LocalService
+	for _, s := range services {
+		if s.Name() == clc.flagServiceName {
+			service = s
+		}
+	}
+
+	if service != nil && service.Type() != atlasServiceType {
+		return fmt.Errorf("service %q is of type %s, not %s", clc.flagServiceName, service.Type(), atlasServiceType)
+	}
+
+	if err := clc.requireLogin(); err != nil {
+		return err
+	}
+
+	projectID := clc.flagProjectID
+	if projectID == "" {
+		app, err := clc.remoteApp()
+		if err != nil {
+			return fmt.Errorf("failed to find the project of the app, supply it with --%s: %s", flagProjectIDName, err)
+		}
+		projectID = app.GroupID
+	}
+
+	if err := clc.validateCluster(projectID); err != nil {
+		return err
+	}
+
+	if service == nil {
+		if service, err = utils.ServiceTemplates[atlasServiceType].NewLocalService(appPath, clc.flagServiceName, nil); err != nil {
+			return err
+		}
+	}
+
+	config, _ := service.Config["config"].(map[string]interface{})
+	if config == nil {
+		config = map[string]interface{}{}
+		service.Config["config"] = config
+	}
+
+	previousCluster, _ := config["clusterName"].(string)
+	config["clusterName"] = clc.flagCluster
+
+	if err := utils.WriteLocalService(service); err != nil {
+		return err
+	}
+
+	if previousCluster != "" && previousCluster != clc.flagCluster {
+		clc.UI.Info(fmt.Sprintf("Linked cluster '%s' as service '%s' in place of cluster '%s'", clc.flagCluster, service.Name(), previousCluster))
+		return nil
+	}
+
+	clc.UI.Info(fmt.Sprintf("Linked cluster '%s' as service '%s' in %s", clc.flagCluster, service.Name(), service.ConfigPath()))
+	return nil
+}
+
+// validateCluster returns an error if the cluster to link does not exist in the project
+func (clc *ClustersLinkCommand) validateCluster(projectID string) error {
+	atlasClient, err := clc.AtlasClient()
+	if err != nil {
+		return err
+	}
+
+	clusters, err := atlasClient.Clusters(projectID)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.Name == clc.flagCluster {
+			return nil
+		}
+		names = append(names, cluster.Name)
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("cluster %q not found: project %s has no clusters", clc.flagCluster, projectID)
+	}
+
+	return fmt.Errorf("cluster %q not found in project %s, must be one of: %s", clc.flagCluster, projectID, strings.Join(names, ", "))
+}
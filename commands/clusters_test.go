@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/10gen/stitch-cli/api/mdbcloud"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+func setUpClustersLinkCommand() (*ClustersLinkCommand, *cli.MockUi, *[]string) {
+	cmd, mockUI := setUpAppCommand(NewClustersLinkCommandFactory)
+	linkCommand := cmd.(*ClustersLinkCommand)
+	linkCommand.storage = u.NewEmptyStorage()
+	linkCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+	requestedGroupIDs := []string{}
+	linkCommand.atlasClient = &u.MockMDBClient{
+		ClustersFn: func(groupID string) ([]mdbcloud.Cluster, error) {
+			requestedGroupIDs = append(requestedGroupIDs, groupID)
+			return []mdbcloud.Cluster{{Name: "Cluster0"}, {Name: "Analytics"}}, nil
+		},
+	}
+	linkCommand.stitchClient = u.NewMockAppStitchClient()
+
+	return linkCommand, mockUI, &requestedGroupIDs
+}
+
+func TestClustersLinkCommand(t *testing.T) {
+	t.Run("should require a cluster name", func(t *testing.T) {
+		linkCommand, mockUI, _ := setUpClustersLinkCommand()
+
+		exitCode := linkCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errClusterNameRequired.Error())
+	})
+
+	t.Run("should link a cluster of the app's project as a new service", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		linkCommand, mockUI, requestedGroupIDs := setUpClustersLinkCommand()
+
+		exitCode := linkCommand.Run([]string{"--path=" + appDir, "--cluster=Analytics"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, *requestedGroupIDs, gc.ShouldResemble, []string{"group-id"})
+
+		service, err := utils.ReadLocalService(appDir, "mongodb-atlas")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, service.Type(), gc.ShouldEqual, "mongodb-atlas")
+		u.So(t, service.Config["config"], gc.ShouldResemble, map[string]interface{}{"clusterName": "Analytics"})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Linked cluster 'Analytics' as service 'mongodb-atlas'")
+	})
+
+	t.Run("should point an existing service at another cluster of the given project", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		service, err := utils.ServiceTemplates["mongodb-atlas"].NewLocalService(appDir, "db", map[string]string{"clusterName": "Cluster0"})
+		u.So(t, err, gc.ShouldBeNil)
+		service.Config["config"].(map[string]interface{})["readPreference"] = "primary"
+		u.So(t, utils.WriteLocalService(service), gc.ShouldBeNil)
+
+		linkCommand, mockUI, requestedGroupIDs := setUpClustersLinkCommand()
+
+		exitCode := linkCommand.Run([]string{"--path=" + appDir, "--cluster=Analytics", "--service-name=db", "--project-id=other-group"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, *requestedGroupIDs, gc.ShouldResemble, []string{"other-group"})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Linked cluster 'Analytics' as service 'db' in place of cluster 'Cluster0'")
+
+		service, err = utils.ReadLocalService(appDir, "db")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, service.Config["config"], gc.ShouldResemble, map[string]interface{}{"clusterName": "Analytics", "readPreference": "primary"})
+	})
+
+	t.Run("should fail for clusters outside of the project", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		linkCommand, mockUI, _ := setUpClustersLinkCommand()
+
+		exitCode := linkCommand.Run([]string{"--path=" + appDir, "--cluster=Cluster1"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `cluster "Cluster1" not found in project group-id, must be one of: Cluster0, Analytics`)
+
+		_, err := utils.ReadLocalService(appDir, "mongodb-atlas")
+		u.So(t, err, gc.ShouldNotBeNil)
+	})
+
+	t.Run("should fail for services of another type", func(t *testing.T) {
+		linkCommand, mockUI, _ := setUpClustersLinkCommand()
+
+		exitCode := linkCommand.Run([]string{"--path=../testdata/full_app", "--cluster=Cluster0", "--service-name=service b"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `service "service b" is of type http, not mongodb-atlas`)
+	})
+}
@@ -3,6 +3,8 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/10gen/stitch-cli/api"
 	"github.com/10gen/stitch-cli/api/mdbcloud"
+	"github.com/10gen/stitch-cli/models"
 	"github.com/10gen/stitch-cli/storage"
 	"github.com/10gen/stitch-cli/user"
 	"github.com/10gen/stitch-cli/utils"
@@ -30,6 +33,28 @@ var (
 	errAppIDRequired = fmt.Errorf("an App ID (--%s=[string]) must be supplied to export an app", flagAppIDName)
 )
 
+// keyValueFlag collects the values of a repeatable name=value flag
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, value := range f {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set parses a name=value pair, satisfying flag.Value
+func (f keyValueFlag) Set(pair string) error {
+	parts := strings.SplitN(pair, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("invalid value %q, must be name=value", pair)
+	}
+
+	f[parts[0]] = parts[1]
+	return nil
+}
+
 // BaseCommand handles the parsing and execution of a command.
 type BaseCommand struct {
 	*flag.FlagSet
@@ -44,6 +69,8 @@ type BaseCommand struct {
 	user         *user.User
 	storage      *storage.Storage
 
+	positionalArgs []string
+
 	flagConfigPath    string
 	flagColorDisabled bool
 	flagBaseURL       string
@@ -165,6 +192,20 @@ func (c *BaseCommand) User() (*user.User, error) {
 	return u, nil
 }
 
+// checkPositionalArgs returns errMissing if fewer than min positional arguments were supplied, and an
+// error listing the unexpected arguments if more than max were
+func (c *BaseCommand) checkPositionalArgs(min, max int, errMissing error) error {
+	if len(c.positionalArgs) < min {
+		return errMissing
+	}
+
+	if len(c.positionalArgs) > max {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(c.positionalArgs[max:], " "))
+	}
+
+	return nil
+}
+
 func (c *BaseCommand) run(args []string) error {
 	if c.FlagSet == nil {
 		c.NewFlagSet()
@@ -174,6 +215,12 @@ func (c *BaseCommand) run(args []string) error {
 	// to avoid duplicate error output
 	c.Parse(args)
 
+	// flags may follow positional arguments (e.g. "functions show my_func --remote")
+	for c.NArg() > 0 {
+		c.positionalArgs = append(c.positionalArgs, c.Arg(0))
+		c.Parse(c.Args()[1:])
+	}
+
 	if !c.flagColorDisabled && isatty.IsTerminal(os.Stdout.Fd()) {
 		c.UI = &cli.ColoredUi{
 			ErrorColor: cli.UiColorRed,
@@ -211,6 +258,140 @@ func (c *BaseCommand) run(args []string) error {
 	return nil
 }
 
+// resolveApp fetches the deployed app identified by clientAppID, falling back to the app_id found in the
+// app config file within appPath. The lookup is restricted to projectID when it is provided
+func (c *BaseCommand) resolveApp(clientAppID, projectID, appPath string) (*models.App, error) {
+	if clientAppID == "" && appPath != "" {
+		appInstanceData := models.AppInstanceData{}
+		if err := appInstanceData.UnmarshalFile(appPath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		clientAppID = appInstanceData.AppID()
+	}
+
+	if clientAppID == "" {
+		return nil, fmt.Errorf("an App ID (--%s=[string]) must be supplied or present in %s", flagAppIDName, models.AppConfigFileName)
+	}
+
+	stitchClient, err := c.StitchClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID != "" {
+		return stitchClient.FetchAppByGroupIDAndClientAppID(projectID, clientAppID)
+	}
+
+	return stitchClient.FetchAppByClientAppID(clientAppID)
+}
+
+// resolveProjectID returns projectID if set, otherwise prompts the user to choose one of their Atlas Projects
+func (c *BaseCommand) resolveProjectID(projectID string) (string, error) {
+	if projectID != "" {
+		return projectID, nil
+	}
+
+	atlasClient, err := c.AtlasClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to find Project: %s", err)
+	}
+
+	groups, err := atlasClient.Groups()
+	if err != nil {
+		return "", fmt.Errorf("failed to find Project: %s", err)
+	}
+
+	groupsByName := map[string]string{}
+	for _, group := range groups {
+		groupsByName[group.Name] = group.ID
+	}
+
+	if len(groupsByName) == 0 {
+		return "", errors.New("no available Projects")
+	}
+
+	c.UI.Info("Available Projects:")
+
+	for name, id := range groupsByName {
+		c.UI.Info(fmt.Sprintf("%s - %s", name, id))
+	}
+
+	var groupID string
+	for {
+		projectResponse, err := c.Ask("Atlas Project Name or ID", groups[0].Name)
+		if err != nil {
+			return "", err
+		}
+
+		if isObjectIDHex(projectResponse) {
+			groupID = projectResponse
+			break
+		}
+
+		groupID = groupsByName[projectResponse]
+		if groupID != "" {
+			break
+		}
+
+		groupFromName, err := atlasClient.GroupByName(projectResponse)
+		if err != nil {
+			return "", err
+		}
+
+		groupID = groupFromName.ID
+		if groupID != "" {
+			break
+		}
+
+		c.UI.Info("Could not understand response, please try again")
+	}
+
+	return groupID, nil
+}
+
+// createEmptyApp creates an app named appName in the Project with the given ID, or the one chosen by the user
+// if no ID is given
+func (c *BaseCommand) createEmptyApp(projectID, appName string, stitchClient api.StitchClient) (*models.App, error) {
+	groupID, err := c.resolveProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	apps, err := stitchClient.FetchAppsByGroupID(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps {
+		if app.Name == appName {
+			return nil, fmt.Errorf("app already exists with name %q", appName)
+		}
+	}
+
+	app, err := stitchClient.CreateEmptyApp(groupID, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.UI.Info(fmt.Sprintf("New app created: %s", app.ClientAppID))
+	return app, nil
+}
+
+// requireLogin returns an error if the current user is not logged in
+func (c *BaseCommand) requireLogin() error {
+	currentUser, err := c.User()
+	if err != nil {
+		return err
+	}
+
+	if !currentUser.LoggedIn() {
+		return user.ErrNotLoggedIn
+	}
+
+	return nil
+}
+
 // AskYesNo is used to prompt the user for yes/no input
 func (c *BaseCommand) AskYesNo(query string) (bool, error) {
 	if c.flagYes {
@@ -277,6 +458,49 @@ func (c *BaseCommand) Ask(query string, defaultVal string) (string, error) {
 	}
 }
 
+// resolveRedactedValuesFromFile replaces the redacted placeholders in the loaded app with the JSON values held
+// in the file at valuesPath, as passed with a --redacted-values flag
+func (c *BaseCommand) resolveRedactedValuesFromFile(app map[string]interface{}, valuesPath string) error {
+	if valuesPath == "" {
+		return fmt.Errorf(
+			"app contains redacted values; provide them with --%s: %s",
+			importFlagRedacted,
+			strings.Join(utils.RedactedKeys(app), ", "),
+		)
+	}
+
+	path, err := homedir.Expand(valuesPath)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]json.RawMessage{}
+	if err := utils.ReadAndUnmarshalInto(json.Unmarshal, path, &values); err != nil {
+		return err
+	}
+
+	return utils.ResolveRedactedValues(app, values)
+}
+
+// resolveAppDirectory returns the directory at path, or the app directory containing workingDirectory if
+// no path is provided
+func resolveAppDirectory(path, workingDirectory string) (string, error) {
+	if path != "" {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			return "", errors.New("directory does not exist")
+		}
+
+		return path, nil
+	}
+
+	return utils.GetDirectoryContainingFile(workingDirectory, models.AppConfigFileName)
+}
+
 // Help defines help documentation for parameters that apply to all commands
 func (c *BaseCommand) Help() string {
 	return `
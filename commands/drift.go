@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+const (
+	driftFlagFormat   = "format"
+	driftFlagExitCode = "exit-code"
+
+	driftFormatText     = "text"
+	driftFormatJSON     = "json"
+	driftFormatMarkdown = "markdown"
+
+	// driftExitCode is returned with --exit-code when the deployed app has drifted
+	driftExitCode = 2
+
+	maxDriftValueLength = 60
+)
+
+var driftStatusSymbols = map[string]string{
+	utils.DriftAdded:   "+",
+	utils.DriftRemoved: "-",
+	utils.DriftChanged: "~",
+}
+
+// NewDriftCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewDriftCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("drift", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &DriftCommand{appCommand: ac}, nil
+	}
+}
+
+// DriftCommand is used to report how the deployed app differs from the local app directory
+type DriftCommand struct {
+	*appCommand
+
+	flagFormat   string
+	flagExitCode bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (dc *DriftCommand) Synopsis() string {
+	return `Report how the deployed app has drifted from the local app directory.`
+}
+
+// Help returns long-form help information for this command
+func (dc *DriftCommand) Help() string {
+	return `Report how the deployed app has drifted from the local app directory.
+
+The deployed app is exported and compared with the local directory entity by entity. Entities only found in
+the deployed app are reported as added, those only found locally as removed, and those found in both with
+differing fields as changed, along with the JSON path of each differing field. Entity IDs and secrets are
+not compared. Private values and secret-like service config fields are only reported as changed, without
+their values, and redaction placeholders in the local directory are not compared.
+
+Usage: stitch-cli drift [options]
+
+OPTIONS:
+  --format [text|json|markdown] (default: text)
+	The format of the report.
+
+  --exit-code
+	Exit with status 2 if the deployed app has drifted, e.g. to alert from a scheduled job.
+` +
+		dc.appCommand.Help()
+}
+
+// Run executes the command
+func (dc *DriftCommand) Run(args []string) int {
+	set := dc.NewFlagSet()
+
+	set.StringVar(&dc.flagFormat, driftFlagFormat, driftFormatText, "")
+	set.BoolVar(&dc.flagExitCode, driftFlagExitCode, false, "")
+
+	if err := dc.BaseCommand.run(args); err != nil {
+		dc.UI.Error(err.Error())
+		return 1
+	}
+
+	report, err := dc.drift()
+	if err != nil {
+		dc.UI.Error(err.Error())
+		return 1
+	}
+
+	if dc.flagExitCode && report.HasDrift() {
+		return driftExitCode
+	}
+
+	return 0
+}
+
+func (dc *DriftCommand) drift() (*utils.DriftReport, error) {
+	var format func(appID string, report *utils.DriftReport) (string, error)
+	switch dc.flagFormat {
+	case driftFormatText:
+		format = formatDriftText
+	case driftFormatJSON:
+		format = formatDriftJSON
+	case driftFormatMarkdown:
+		format = formatDriftMarkdown
+	default:
+		return nil, fmt.Errorf("unknown format %q; accepted values are [%s|%s|%s]", dc.flagFormat, driftFormatText, driftFormatJSON, driftFormatMarkdown)
+	}
+
+	appPath, err := dc.appDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := utils.UnmarshalFromDir(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := dc.remoteApp()
+	if err != nil {
+		return nil, err
+	}
+
+	stitchClient, err := dc.StitchClient()
+	if err != nil {
+		return nil, err
+	}
+
+	deployed, err := exportDeployedApp(stitchClient, app)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export deployed app: %s", err)
+	}
+
+	report := utils.CompareApps(local, deployed)
+
+	output, err := format(app.ClientAppID, report)
+	if err != nil {
+		return nil, err
+	}
+
+	dc.UI.Output(output)
+	return report, nil
+}
+
+func formatDriftText(appID string, report *utils.DriftReport) (string, error) {
+	if !report.HasDrift() {
+		return fmt.Sprintf("Deployed app '%s' has not drifted from the local directory", appID), nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Deployed app '%s' has drifted from the local directory:\n", appID)
+	for _, entity := range report.Entities {
+		fmt.Fprintf(&buf, "%s %s %s (%s)\n", driftStatusSymbols[entity.Status], entity.Kind, entity.Name, entity.Status)
+		for _, field := range entity.Fields {
+			if field.Redacted {
+				fmt.Fprintf(&buf, "    %s: %s\n", field.Path, utils.DriftChanged)
+				continue
+			}
+			fmt.Fprintf(&buf, "    %s: %s -> %s\n", field.Path, formatDriftValue(field.Local), formatDriftValue(field.Deployed))
+		}
+	}
+	fmt.Fprintf(&buf, "Drifted entities: %d", len(report.Entities))
+
+	return buf.String(), nil
+}
+
+func formatDriftJSON(appID string, report *utils.DriftReport) (string, error) {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"app_id":   appID,
+		"drifted":  report.HasDrift(),
+		"entities": report.Entities,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func formatDriftMarkdown(appID string, report *utils.DriftReport) (string, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Drift report for `%s`\n\n", appID)
+
+	if !report.HasDrift() {
+		buf.WriteString("The deployed app has not drifted from the local directory.")
+		return buf.String(), nil
+	}
+
+	buf.WriteString("| Status | Kind | Name |\n")
+	buf.WriteString("| --- | --- | --- |\n")
+	for _, entity := range report.Entities {
+		fmt.Fprintf(&buf, "| %s | %s | `%s` |\n", entity.Status, entity.Kind, entity.Name)
+	}
+
+	for _, entity := range report.Entities {
+		if len(entity.Fields) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n## %s `%s`\n\n", entity.Kind, entity.Name)
+		buf.WriteString("| Path | Local | Deployed |\n")
+		buf.WriteString("| --- | --- | --- |\n")
+		for _, field := range entity.Fields {
+			if field.Redacted {
+				fmt.Fprintf(&buf, "| `%s` | _%s_ | _%s_ |\n", field.Path, utils.DriftChanged, utils.DriftChanged)
+				continue
+			}
+			fmt.Fprintf(&buf, "| `%s` | %s | %s |\n", field.Path, markdownCell(field.Local), markdownCell(field.Deployed))
+		}
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// formatDriftValue renders a field value as compact JSON, truncating long values
+func formatDriftValue(value interface{}) string {
+	if value == nil {
+		return "(absent)"
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	if len(data) > maxDriftValueLength {
+		return string(data[:maxDriftValueLength]) + "..."
+	}
+	return string(data)
+}
+
+func markdownCell(value interface{}) string {
+	if value == nil {
+		return "_absent_"
+	}
+
+	return "`" + strings.Replace(formatDriftValue(value), "|", "\\|", -1) + "`"
+}
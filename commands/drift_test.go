@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/user"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+// zipAppDirectory builds the zip data of an export of the app directory at dir, with the contents of
+// the files in overrides replaced, or the files left out if their contents are empty. Names ending in a
+// slash add directories
+func zipAppDirectory(dir string, overrides map[string]string) []byte {
+	files := u.ReadDirectoryFiles(dir)
+	for name, contents := range overrides {
+		if contents == "" && !strings.HasSuffix(name, "/") {
+			delete(files, name)
+			continue
+		}
+		files[name] = contents
+	}
+
+	return u.NewZip(files)
+}
+
+func setUpDriftCommand(t *testing.T, overrides map[string]string) (*DriftCommand, *cli.MockUi) {
+	cmd, mockUI := setUpAppCommand(NewDriftCommandFactory)
+	driftCommand := cmd.(*DriftCommand)
+	driftCommand.storage = u.NewEmptyStorage()
+	driftCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+	exported := zipAppDirectory("../testdata/full_app", overrides)
+	stitchClient := u.NewMockAppStitchClient()
+	stitchClient.ExportFn = func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+		return "full-app_20180301.zip", u.NewResponseBody(bytes.NewReader(exported)), nil
+	}
+	driftCommand.stitchClient = stitchClient
+
+	return driftCommand, mockUI
+}
+
+var driftOverrides = map[string]string{
+	"values/value_a.json":                 `{"id": "5a1db2f34810c5a045135eed", "name": "a", "value": "edited in the UI", "private": false}`,
+	"triggers/authEventSubscription.json": "",
+	"functions/function_c/":               "",
+	"functions/function_c/config.json":    `{"name": "function_c"}`,
+	"functions/function_c/source.js":      `exports = function() {};`,
+}
+
+func TestDriftCommand(t *testing.T) {
+	args := []string{"--path=../testdata/full_app", "--app-id=full-app-abcde"}
+
+	t.Run("should report that the deployed app has not drifted", func(t *testing.T) {
+		driftCommand, mockUI := setUpDriftCommand(t, nil)
+
+		exitCode := driftCommand.Run(append(args, "--exit-code"))
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "Deployed app 'full-app-abcde' has not drifted from the local directory\n")
+	})
+
+	t.Run("should report drifted entities as text", func(t *testing.T) {
+		driftCommand, mockUI := setUpDriftCommand(t, driftOverrides)
+
+		exitCode := driftCommand.Run(args)
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `Deployed app 'full-app-abcde' has drifted from the local directory:
+~ value a (changed)
+    $.value: "AAAAAA" -> "edited in the UI"
++ function function_c (added)
+- trigger authEventSubscription (removed)
+Drifted entities: 3
+`)
+	})
+
+	t.Run("should report changed private values without their values", func(t *testing.T) {
+		driftCommand, mockUI := setUpDriftCommand(t, map[string]string{
+			"values/value_b.json": `{"name": "b", "value": "edited in the UI", "private": true}`,
+		})
+
+		exitCode := driftCommand.Run(args)
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `Deployed app 'full-app-abcde' has drifted from the local directory:
+~ value b (changed)
+    $.value: changed
+Drifted entities: 1
+`)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldNotContainSubstring, "BBBBBB")
+	})
+
+	t.Run("should exit with a distinct status on drift with --exit-code", func(t *testing.T) {
+		driftCommand, _ := setUpDriftCommand(t, driftOverrides)
+
+		exitCode := driftCommand.Run(append(args, "--exit-code"))
+		u.So(t, exitCode, gc.ShouldEqual, driftExitCode)
+	})
+
+	t.Run("should report drifted entities as JSON", func(t *testing.T) {
+		driftCommand, mockUI := setUpDriftCommand(t, driftOverrides)
+
+		exitCode := driftCommand.Run(append(args, "--format=json"))
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+
+		var report map[string]interface{}
+		u.So(t, json.Unmarshal(mockUI.OutputWriter.Bytes(), &report), gc.ShouldBeNil)
+		u.So(t, report["app_id"], gc.ShouldEqual, "full-app-abcde")
+		u.So(t, report["drifted"], gc.ShouldBeTrue)
+		u.So(t, report["entities"], gc.ShouldHaveLength, 3)
+		u.So(t, report["entities"].([]interface{})[0], gc.ShouldResemble, map[string]interface{}{
+			"kind":   "value",
+			"name":   "a",
+			"status": "changed",
+			"fields": []interface{}{
+				map[string]interface{}{"path": "$.value", "local": "AAAAAA", "deployed": "edited in the UI"},
+			},
+		})
+	})
+
+	t.Run("should report drifted entities as markdown", func(t *testing.T) {
+		driftCommand, mockUI := setUpDriftCommand(t, driftOverrides)
+
+		exitCode := driftCommand.Run(append(args, "--format=markdown"))
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "# Drift report for `full-app-abcde`"+`
+
+| Status | Kind | Name |
+| --- | --- | --- |
+| changed | value | `+"`a`"+` |
+| added | function | `+"`function_c`"+` |
+| removed | trigger | `+"`authEventSubscription`"+` |
+
+## value `+"`a`"+`
+
+| Path | Local | Deployed |
+| --- | --- | --- |
+| `+"`$.value` | `\"AAAAAA\"` | `\"edited in the UI\"`"+` |
+`)
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		driftCommand, mockUI := setUpDriftCommand(t, nil)
+
+		exitCode := driftCommand.Run(append(args, "--format=yaml"))
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `unknown format "yaml"`)
+	})
+}
@@ -14,6 +14,10 @@ import (
 	"github.com/mitchellh/go-homedir"
 )
 
+const exportFlagArchive = "archive"
+
+var errExportArchiveConflict = fmt.Errorf("--%s and --output cannot be supplied together", exportFlagArchive)
+
 // NewExportCommandFactory returns a new cli.CommandFactory given a cli.Ui
 func NewExportCommandFactory(ui cli.Ui) cli.CommandFactory {
 	return func() (cli.Command, error) {
@@ -25,6 +29,7 @@ func NewExportCommandFactory(ui cli.Ui) cli.CommandFactory {
 		return &ExportCommand{
 			workingDirectory:  workingDirectory,
 			exportToDirectory: utils.WriteZipToDir,
+			exportToArchive:   utils.WriteArchive,
 			BaseCommand: &BaseCommand{
 				Name: "export",
 				UI:   ui,
@@ -39,11 +44,14 @@ type ExportCommand struct {
 
 	workingDirectory  string
 	exportToDirectory func(dest string, zipData io.Reader, overwrite bool) error
+	exportToArchive   func(dest string, zipData io.Reader, overwrite bool) error
 
 	flagProjectID  string
 	flagAppID      string
 	flagOutput     string
+	flagArchive    string
 	flagAsTemplate bool
+	flagRedact     bool
 }
 
 // Help returns long-form help information for this command
@@ -61,8 +69,18 @@ OPTIONS:
   -o [string], --output [string]
 	Directory to write the exported configuration. Defaults to "<app_name>_<timestamp>"
 
+  --archive [string]
+	Write the exported configuration to a single archive file instead of a directory, e.g. to store it as an
+	immutable build artifact. The archive is a zip file, or a tar.gz file if the path ends in ".tar.gz" or
+	".tgz", and can be imported with "import --archive".
+
   --as-template
-	Indicate that the application should be exported as a template.` +
+	Indicate that the application should be exported as a template.
+
+  --redact
+	Replace private values, secrets and secret-like service configuration fields with placeholders, and write a
+	"secrets.template.json" file listing the values that must be provided when importing. Each entry is to be
+	replaced with the JSON value of its field, such as "a string", 42 or {"an": "object"}.` +
 		ec.BaseCommand.Help()
 }
 
@@ -79,7 +97,9 @@ func (ec *ExportCommand) Run(args []string) int {
 	set.StringVar(&ec.flagAppID, flagAppIDName, "", "")
 	set.StringVar(&ec.flagOutput, "output", "", "")
 	set.StringVar(&ec.flagOutput, "o", "", "")
+	set.StringVar(&ec.flagArchive, exportFlagArchive, "", "")
 	set.BoolVar(&ec.flagAsTemplate, "as-template", false, "")
+	set.BoolVar(&ec.flagRedact, "redact", false, "")
 
 	if err := ec.BaseCommand.run(args); err != nil {
 		ec.UI.Error(err.Error())
@@ -108,8 +128,14 @@ func (ec *ExportCommand) run() error {
 		return u.ErrNotLoggedIn
 	}
 
-	if dir, err := utils.GetDirectoryContainingFile(ec.workingDirectory, models.AppConfigFileName); err == nil {
-		return fmt.Errorf("cannot export within config directory %q", dir)
+	if ec.flagArchive != "" && ec.flagOutput != "" {
+		return errExportArchiveConflict
+	}
+
+	if ec.flagArchive == "" {
+		if dir, err := utils.GetDirectoryContainingFile(ec.workingDirectory, models.AppConfigFileName); err == nil {
+			return fmt.Errorf("cannot export within config directory %q", dir)
+		}
 	}
 
 	stitchClient, err := ec.StitchClient()
@@ -137,6 +163,24 @@ func (ec *ExportCommand) run() error {
 
 	defer body.Close()
 
+	if ec.flagArchive != "" {
+		archivePath, err := homedir.Expand(ec.flagArchive)
+		if err != nil {
+			return err
+		}
+
+		if ec.flagRedact {
+			redacted, err := utils.RedactAppZip(body)
+			if err != nil {
+				return fmt.Errorf("failed to redact exported app: %s", err)
+			}
+
+			return ec.exportToArchive(archivePath, redacted, false)
+		}
+
+		return ec.exportToArchive(archivePath, body, false)
+	}
+
 	if ec.flagOutput != "" {
 		filename, err = homedir.Expand(ec.flagOutput)
 		if err != nil {
@@ -146,5 +190,14 @@ func (ec *ExportCommand) run() error {
 		filename = filename[:lastUnderscoreIdx]
 	}
 
+	if ec.flagRedact {
+		redacted, err := utils.RedactAppZip(body)
+		if err != nil {
+			return fmt.Errorf("failed to redact exported app: %s", err)
+		}
+
+		return ec.exportToDirectory(filename, redacted, false)
+	}
+
 	return ec.exportToDirectory(filename, body, false)
 }
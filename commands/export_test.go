@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/10gen/stitch-cli/models"
 	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
 	u "github.com/10gen/stitch-cli/utils/test"
 	gc "github.com/smartystreets/goconvey/convey"
 
@@ -196,6 +199,95 @@ func TestExportCommand(t *testing.T) {
 			}
 		})
 
+		t.Run("redacts the exported app when the '--redact' flag is provided", func(t *testing.T) {
+			exportCommand, mockUI := setup()
+
+			var exported bytes.Buffer
+			w := zip.NewWriter(&exported)
+			fw, err := w.Create("values/value_b.json")
+			u.So(t, err, gc.ShouldBeNil)
+			_, err = fw.Write([]byte(`{"name": "b", "value": "BBBBBB", "private": true}`))
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, w.Close(), gc.ShouldBeNil)
+
+			exportCommand.stitchClient = &u.MockStitchClient{
+				FetchAppByClientAppIDFn: func(clientAppID string) (*models.App, error) {
+					return &models.App{ClientAppID: clientAppID, GroupID: "group-id", ID: "app-id"}, nil
+				},
+				ExportFn: func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+					return "my_app_123456.zip", u.NewResponseBody(bytes.NewReader(exported.Bytes())), nil
+				},
+			}
+			exportCommand.user = &user.User{
+				APIKey:      "my-api-key",
+				AccessToken: u.GenerateValidAccessToken(),
+			}
+
+			var written string
+			exportCommand.exportToDirectory = func(dest string, r io.Reader, overwrite bool) error {
+				b, err := ioutil.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				written = string(b)
+				return nil
+			}
+
+			exitCode := exportCommand.Run([]string{`--app-id=my-cool-app`, `--redact`})
+			u.So(t, exitCode, gc.ShouldEqual, 0)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+			u.So(t, written, gc.ShouldNotContainSubstring, "BBBBBB")
+			u.So(t, written, gc.ShouldContainSubstring, utils.SecretsTemplateFileName)
+		})
+
+		t.Run("saves the exported app to an archive when the '--archive' flag is provided", func(t *testing.T) {
+			exportCommand, mockUI := setup()
+
+			exportCommand.stitchClient = &u.MockStitchClient{
+				FetchAppByClientAppIDFn: func(clientAppID string) (*models.App, error) {
+					return &models.App{ClientAppID: clientAppID, GroupID: "group-id", ID: "app-id"}, nil
+				},
+				ExportFn: func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+					return "my_app_123456.zip", u.NewResponseBody(strings.NewReader("zip-contents")), nil
+				},
+			}
+			exportCommand.user = &user.User{
+				APIKey:      "my-api-key",
+				AccessToken: u.GenerateValidAccessToken(),
+			}
+			exportCommand.exportToDirectory = func(dest string, r io.Reader, overwrite bool) error {
+				return fmt.Errorf("should not export to a directory")
+			}
+
+			var destination, written string
+			exportCommand.exportToArchive = func(dest string, r io.Reader, overwrite bool) error {
+				b, err := ioutil.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				destination, written = dest, string(b)
+				return nil
+			}
+
+			exitCode := exportCommand.Run([]string{`--app-id=my-cool-app`, `--archive=artifacts/app.zip`})
+			u.So(t, exitCode, gc.ShouldEqual, 0)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+			u.So(t, destination, gc.ShouldEqual, "artifacts/app.zip")
+			u.So(t, written, gc.ShouldEqual, "zip-contents")
+		})
+
+		t.Run("does not accept both an archive and an output directory", func(t *testing.T) {
+			exportCommand, mockUI := setup()
+			exportCommand.user = &user.User{
+				APIKey:      "my-api-key",
+				AccessToken: u.GenerateValidAccessToken(),
+			}
+
+			exitCode := exportCommand.Run([]string{`--app-id=my-cool-app`, `--archive=app.zip`, `-o=app`})
+			u.So(t, exitCode, gc.ShouldEqual, 1)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errExportArchiveConflict.Error())
+		})
+
 		t.Run("returns an error when the response from the API is unexpected", func(t *testing.T) {
 			exportCommand, mockUI := setup()
 
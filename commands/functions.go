@@ -0,0 +1,500 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+var errFunctionNameRequired = errors.New("a function name must be supplied")
+
+// NewFunctionsListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewFunctionsListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("functions list", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &FunctionsListCommand{appCommand: ac}, nil
+	}
+}
+
+// FunctionsListCommand is used to list the functions of a Stitch App
+type FunctionsListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (flc *FunctionsListCommand) Synopsis() string {
+	return `List the functions of an app.`
+}
+
+// Help returns long-form help information for this command
+func (flc *FunctionsListCommand) Help() string {
+	return `List the functions of an app.
+
+OPTIONS:` +
+		flc.appCommand.Help()
+}
+
+// Run executes the command
+func (flc *FunctionsListCommand) Run(args []string) int {
+	flc.NewFlagSet()
+
+	if err := flc.BaseCommand.run(args); err != nil {
+		flc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := flc.list(); err != nil {
+		flc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (flc *FunctionsListCommand) list() error {
+	if flc.flagRemote {
+		app, err := flc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		stitchClient, err := flc.StitchClient()
+		if err != nil {
+			return err
+		}
+
+		functions, err := stitchClient.Functions(app.GroupID, app.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, fn := range functions {
+			flc.UI.Info(describeEntity(fn.Name, fn.Private))
+		}
+
+		return nil
+	}
+
+	appPath, err := flc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	functions, err := utils.ReadLocalFunctions(appPath)
+	if err != nil {
+		return err
+	}
+
+	for _, fn := range functions {
+		flc.UI.Info(describeEntity(fn.Name, fn.Private()))
+	}
+
+	return nil
+}
+
+// NewFunctionsShowCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewFunctionsShowCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("functions show", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &FunctionsShowCommand{appCommand: ac}, nil
+	}
+}
+
+// FunctionsShowCommand is used to print the config and source of a function
+type FunctionsShowCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (fsc *FunctionsShowCommand) Synopsis() string {
+	return `Print the configuration and source of a function.`
+}
+
+// Help returns long-form help information for this command
+func (fsc *FunctionsShowCommand) Help() string {
+	return `Print the configuration and source of a function.
+
+Usage: stitch-cli functions show <name> [options]
+
+OPTIONS:` +
+		fsc.appCommand.Help()
+}
+
+// Run executes the command
+func (fsc *FunctionsShowCommand) Run(args []string) int {
+	fsc.NewFlagSet()
+
+	if err := fsc.BaseCommand.run(args); err != nil {
+		fsc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := fsc.show(); err != nil {
+		fsc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (fsc *FunctionsShowCommand) show() error {
+	if err := fsc.checkPositionalArgs(1, 1, errFunctionNameRequired); err != nil {
+		return err
+	}
+	name := fsc.positionalArgs[0]
+
+	var config interface{}
+	var source string
+
+	if fsc.flagRemote {
+		app, err := fsc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		fn, err := fetchRemoteFunction(fsc.BaseCommand, app, name)
+		if err != nil {
+			return err
+		}
+
+		source = fn.Source
+		fn.Source = ""
+		config = fn
+	} else {
+		appPath, err := fsc.appDirectory()
+		if err != nil {
+			return err
+		}
+
+		fn, err := utils.ReadLocalFunction(appPath, name)
+		if err != nil {
+			return err
+		}
+
+		config = fn.Config
+		source = fn.Source
+	}
+
+	configJSON, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	fsc.UI.Output(string(configJSON))
+	fsc.UI.Output("")
+	fsc.UI.Output(source)
+
+	return nil
+}
+
+// NewFunctionsNewCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewFunctionsNewCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("functions new", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &FunctionsNewCommand{appCommand: ac}, nil
+	}
+}
+
+// FunctionsNewCommand is used to scaffold a new function and optionally push it to the deployed app
+type FunctionsNewCommand struct {
+	*appCommand
+
+	flagPrivate bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (fnc *FunctionsNewCommand) Synopsis() string {
+	return `Create a new function from a template.`
+}
+
+// Help returns long-form help information for this command
+func (fnc *FunctionsNewCommand) Help() string {
+	return `Create a new function from a template in the local app directory.
+
+With --remote, the function is also pushed to the deployed app: it is created if it does not exist yet,
+otherwise the deployed function is replaced with the contents of the local directory.
+
+Usage: stitch-cli functions new <name> [options]
+
+OPTIONS:
+  --private
+	Mark the function as private so that it can only be called by other functions and rules.
+` +
+		fnc.appCommand.Help()
+}
+
+// Run executes the command
+func (fnc *FunctionsNewCommand) Run(args []string) int {
+	set := fnc.NewFlagSet()
+
+	set.BoolVar(&fnc.flagPrivate, "private", false, "")
+
+	if err := fnc.BaseCommand.run(args); err != nil {
+		fnc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := fnc.create(); err != nil {
+		fnc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (fnc *FunctionsNewCommand) create() error {
+	if err := fnc.checkPositionalArgs(1, 1, errFunctionNameRequired); err != nil {
+		return err
+	}
+	name := fnc.positionalArgs[0]
+	if err := utils.ValidateFunctionName(name); err != nil {
+		return err
+	}
+
+	appPath, err := fnc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	localFunctions, err := utils.ReadLocalFunctions(appPath)
+	if err != nil {
+		return err
+	}
+
+	var fn *utils.LocalFunction
+	for _, localFunction := range localFunctions {
+		if localFunction.Name == name {
+			fn = localFunction
+		}
+	}
+
+	if fn == nil {
+		fn = &utils.LocalFunction{
+			Name: name,
+			Config: map[string]interface{}{
+				"name":    name,
+				"private": fnc.flagPrivate,
+			},
+			Source: utils.FunctionSourceTemplate,
+		}
+
+		if err := utils.WriteLocalFunction(appPath, fn); err != nil {
+			return err
+		}
+
+		fnc.UI.Info(fmt.Sprintf("Created function '%s' in %s", name, fn.Dir))
+	} else if !fnc.flagRemote {
+		return fmt.Errorf("function %q already exists in %s", name, fn.Dir)
+	}
+
+	if !fnc.flagRemote {
+		return nil
+	}
+
+	app, err := fnc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := fnc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	functions, err := stitchClient.Functions(app.GroupID, app.ID)
+	if err != nil {
+		return err
+	}
+
+	remote := &models.Function{
+		Name:    name,
+		Source:  fn.Source,
+		Private: fn.Private(),
+		Config:  fn.Config,
+	}
+
+	if canEvaluate, ok := fn.Config["can_evaluate"].(map[string]interface{}); ok {
+		remote.CanEvaluate = canEvaluate
+	}
+
+	if existing := findFunctionByName(functions, name); existing != nil {
+		remote.ID = existing.ID
+		if err := stitchClient.UpdateFunction(app.GroupID, app.ID, remote); err != nil {
+			return err
+		}
+
+		fnc.UI.Info(fmt.Sprintf("Updated function '%s' in '%s'", name, app.ClientAppID))
+		return nil
+	}
+
+	if _, err := stitchClient.CreateFunction(app.GroupID, app.ID, remote); err != nil {
+		return err
+	}
+
+	fnc.UI.Info(fmt.Sprintf("Created function '%s' in '%s'", name, app.ClientAppID))
+	return nil
+}
+
+// NewFunctionsRemoveCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewFunctionsRemoveCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("functions rm", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &FunctionsRemoveCommand{appCommand: ac}, nil
+	}
+}
+
+// FunctionsRemoveCommand is used to delete a function
+type FunctionsRemoveCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (frc *FunctionsRemoveCommand) Synopsis() string {
+	return `Delete a function.`
+}
+
+// Help returns long-form help information for this command
+func (frc *FunctionsRemoveCommand) Help() string {
+	return `Delete a function from the local app directory, or from the deployed app with --remote.
+
+Usage: stitch-cli functions rm <name> [options]
+
+OPTIONS:` +
+		frc.appCommand.Help()
+}
+
+// Run executes the command
+func (frc *FunctionsRemoveCommand) Run(args []string) int {
+	frc.NewFlagSet()
+
+	if err := frc.BaseCommand.run(args); err != nil {
+		frc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := frc.remove(); err != nil {
+		frc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (frc *FunctionsRemoveCommand) remove() error {
+	if err := frc.checkPositionalArgs(1, 1, errFunctionNameRequired); err != nil {
+		return err
+	}
+	name := frc.positionalArgs[0]
+
+	if frc.flagRemote {
+		app, err := frc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		fn, err := fetchRemoteFunction(frc.BaseCommand, app, name)
+		if err != nil {
+			return err
+		}
+
+		confirm, err := frc.AskYesNo(fmt.Sprintf("Delete function '%s' from '%s'?", name, app.ClientAppID))
+		if err != nil || !confirm {
+			return err
+		}
+
+		stitchClient, err := frc.StitchClient()
+		if err != nil {
+			return err
+		}
+
+		if err := stitchClient.DeleteFunction(app.GroupID, app.ID, fn.ID); err != nil {
+			return err
+		}
+
+		frc.UI.Info(fmt.Sprintf("Deleted function '%s' from '%s'", name, app.ClientAppID))
+		return nil
+	}
+
+	appPath, err := frc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	fn, err := utils.ReadLocalFunction(appPath, name)
+	if err != nil {
+		return err
+	}
+
+	confirm, err := frc.AskYesNo(fmt.Sprintf("Delete function '%s' from %s?", name, fn.Dir))
+	if err != nil || !confirm {
+		return err
+	}
+
+	if err := os.RemoveAll(fn.Dir); err != nil {
+		return err
+	}
+
+	frc.UI.Info(fmt.Sprintf("Deleted function '%s'", name))
+	return nil
+}
+
+// fetchRemoteFunction looks up a deployed function by name and returns it along with its source
+func fetchRemoteFunction(c *BaseCommand, app *models.App, name string) (*models.Function, error) {
+	stitchClient, err := c.StitchClient()
+	if err != nil {
+		return nil, err
+	}
+
+	functions, err := stitchClient.Functions(app.GroupID, app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fn := findFunctionByName(functions, name)
+	if fn == nil {
+		return nil, fmt.Errorf("function %q not found in '%s'", name, app.ClientAppID)
+	}
+
+	return stitchClient.Function(app.GroupID, app.ID, fn.ID)
+}
+
+func findFunctionByName(functions []*models.Function, name string) *models.Function {
+	for _, fn := range functions {
+		if fn.Name == name {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+// describeEntity returns the name of an app entity such as a function or value, flagged if it is private
+func describeEntity(name string, private bool) string {
+	if private {
+		return name + " (private)"
+	}
+
+	return name
+}
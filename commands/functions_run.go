@@ -0,0 +1,326 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	functionsRunFlagArgs     = "args"
+	functionsRunFlagArgsFile = "args-file"
+	functionsRunFlagAsUser   = "as-user"
+	functionsRunFlagFixtures = "fixtures"
+	functionsRunFlagTimeout  = "timeout"
+
+	defaultFunctionTestTimeout = 10 * time.Second
+)
+
+var errFunctionArgsConflict = errors.New("only one of --args and --args-file may be supplied")
+
+// NewFunctionsRunCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewFunctionsRunCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("functions run", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &FunctionsRunCommand{appCommand: ac}, nil
+	}
+}
+
+// FunctionsRunCommand is used to execute a function of a deployed app
+type FunctionsRunCommand struct {
+	*appCommand
+
+	flagArgs     string
+	flagArgsFile string
+	flagAsUser   string
+}
+
+// Synopsis returns a one-liner description for this command
+func (frc *FunctionsRunCommand) Synopsis() string {
+	return `Execute a function of a deployed app and print its result.`
+}
+
+// Help returns long-form help information for this command
+func (frc *FunctionsRunCommand) Help() string {
+	return `Execute a function of a deployed app and print its result, logs and errors.
+
+The command exits with a non-zero status if the function throws an error, which makes it suitable
+for smoke-testing a deployment.
+
+Usage: stitch-cli functions run <name> [options]
+
+OPTIONS:
+  --args [string]
+	The arguments to call the function with, as a JSON array. A single non-array value is passed as the only argument.
+	Values may use MongoDB Extended JSON, e.g. '[{"$oid": "5a1f..."}, 42]'.
+
+  --args-file [string]
+	A path to a file containing the arguments to call the function with, in the same format as --args.
+
+  --as-user [string]
+	The ID of the user to execute the function as. Defaults to executing the function as the system user.
+` +
+		frc.appCommand.Help()
+}
+
+// Run executes the command
+func (frc *FunctionsRunCommand) Run(args []string) int {
+	set := frc.NewFlagSet()
+
+	set.StringVar(&frc.flagArgs, functionsRunFlagArgs, "", "")
+	set.StringVar(&frc.flagArgsFile, functionsRunFlagArgsFile, "", "")
+	set.StringVar(&frc.flagAsUser, functionsRunFlagAsUser, "", "")
+
+	if err := frc.BaseCommand.run(args); err != nil {
+		frc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := frc.execute(); err != nil {
+		frc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (frc *FunctionsRunCommand) execute() error {
+	if err := frc.checkPositionalArgs(1, 1, errFunctionNameRequired); err != nil {
+		return err
+	}
+	name := frc.positionalArgs[0]
+
+	functionArgs, err := parseFunctionArgs(frc.flagArgs, frc.flagArgsFile)
+	if err != nil {
+		return err
+	}
+
+	app, err := frc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := frc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	execution, err := stitchClient.ExecuteFunction(app.GroupID, app.ID, frc.flagAsUser, name, functionArgs)
+	if err != nil {
+		return fmt.Errorf("failed to execute function %q: %s", name, err)
+	}
+
+	for _, log := range execution.Logs {
+		frc.UI.Info(log)
+	}
+
+	for _, log := range execution.ErrorLogs {
+		frc.UI.Warn(log)
+	}
+
+	if execution.Error != nil {
+		return fmt.Errorf("function %q threw an error: %s", name, execution.Error)
+	}
+
+	return outputFunctionResult(frc.UI, execution.Result)
+}
+
+// NewFunctionsTestCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewFunctionsTestCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("functions test", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &FunctionsTestCommand{appCommand: ac}, nil
+	}
+}
+
+// FunctionsTestCommand is used to run a function of a local app directory without deploying it
+type FunctionsTestCommand struct {
+	*appCommand
+
+	flagArgs     string
+	flagArgsFile string
+	flagFixtures string
+	flagTimeout  time.Duration
+}
+
+// Synopsis returns a one-liner description for this command
+func (ftc *FunctionsTestCommand) Synopsis() string {
+	return `Run a function from the local app directory and print its result.`
+}
+
+// Help returns long-form help information for this command
+func (ftc *FunctionsTestCommand) Help() string {
+	return `Run a function from the local app directory in an embedded JavaScript engine and print its result.
+
+No network access is made: context.values is read from values/*.json, context.functions.execute
+calls the other functions of the directory, and context.services is backed by a fixtures file.
+Functions may use ES6 syntax and async/await; like deployed services, the stubbed service methods
+return promises.
+
+Usage: stitch-cli functions test <name> [options]
+
+OPTIONS:
+  --args [string]
+	The arguments to call the function with, as a JSON array. A single non-array value is passed as the only argument.
+
+  --args-file [string]
+	A path to a file containing the arguments to call the function with, in the same format as --args.
+
+  --fixtures [string]
+	A path to a JSON file mapping each service name to the values its methods return, e.g.
+	{"http": {"get": {...}}, "mongodb-atlas": {"db.collection": {"findOne": {...}, "find": [...]}}}
+
+  --timeout [duration] (default: 10s)
+	How long to let the function run before halting it.
+` +
+		ftc.appCommand.Help()
+}
+
+// Run executes the command
+func (ftc *FunctionsTestCommand) Run(args []string) int {
+	set := ftc.NewFlagSet()
+
+	set.StringVar(&ftc.flagArgs, functionsRunFlagArgs, "", "")
+	set.StringVar(&ftc.flagArgsFile, functionsRunFlagArgsFile, "", "")
+	set.StringVar(&ftc.flagFixtures, functionsRunFlagFixtures, "", "")
+	set.DurationVar(&ftc.flagTimeout, functionsRunFlagTimeout, defaultFunctionTestTimeout, "")
+
+	if err := ftc.BaseCommand.run(args); err != nil {
+		ftc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := ftc.test(); err != nil {
+		ftc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (ftc *FunctionsTestCommand) test() error {
+	if err := ftc.checkPositionalArgs(1, 1, errFunctionNameRequired); err != nil {
+		return err
+	}
+	name := ftc.positionalArgs[0]
+
+	functionArgs, err := parseFunctionArgs(ftc.flagArgs, ftc.flagArgsFile)
+	if err != nil {
+		return err
+	}
+
+	fixtures := map[string]interface{}{}
+	if ftc.flagFixtures != "" {
+		path, err := homedir.Expand(ftc.flagFixtures)
+		if err != nil {
+			return err
+		}
+
+		if err := utils.ReadAndUnmarshalInto(json.Unmarshal, path, &fixtures); err != nil {
+			return err
+		}
+	}
+
+	appPath, err := ftc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	app, err := utils.UnmarshalFromDir(appPath)
+	if err != nil {
+		return err
+	}
+
+	runner, err := utils.NewFunctionRunner(app, fixtures)
+	if err != nil {
+		return err
+	}
+
+	result, err := runner.Run(name, functionArgs, ftc.flagTimeout)
+	if result != nil {
+		for _, log := range result.Logs {
+			ftc.UI.Info(log)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to run function %q: %s", name, err)
+	}
+
+	return outputFunctionResult(ftc.UI, result.Result)
+}
+
+// outputFunctionResult prints the JSON result of a function, or undefined if it returned nothing
+func outputFunctionResult(ui cli.Ui, result json.RawMessage) error {
+	if len(result) == 0 {
+		ui.Output("undefined")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, result, "", "    "); err != nil {
+		return err
+	}
+
+	ui.Output(buf.String())
+	return nil
+}
+
+// parseFunctionArgs parses the function arguments supplied through either --args or --args-file
+func parseFunctionArgs(args, argsFile string) ([]json.RawMessage, error) {
+	if args != "" && argsFile != "" {
+		return nil, errFunctionArgsConflict
+	}
+
+	data := []byte(args)
+	source := "--" + functionsRunFlagArgs
+
+	if argsFile != "" {
+		path, err := homedir.Expand(argsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err = ioutil.ReadFile(path); err != nil {
+			return nil, err
+		}
+		source = path
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return []json.RawMessage{}, nil
+	}
+
+	if data[0] == '[' {
+		var args []json.RawMessage
+		if err := json.Unmarshal(data, &args); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", source, err)
+		}
+		return args, nil
+	}
+
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("failed to parse %s: invalid JSON", source)
+	}
+
+	return []json.RawMessage{json.RawMessage(data)}, nil
+}
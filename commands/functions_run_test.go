@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+func setUpFunctionsRunCommand(executeFn func(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error)) *FunctionsRunCommand {
+	cmd, _ := setUpAppCommand(NewFunctionsRunCommandFactory)
+
+	runCommand := cmd.(*FunctionsRunCommand)
+	runCommand.storage = u.NewEmptyStorage()
+	runCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+	stitchClient := u.NewMockAppStitchClient()
+	stitchClient.ExecuteFunctionFn = executeFn
+
+	runCommand.stitchClient = stitchClient
+
+	return runCommand
+}
+
+func TestFunctionsRunCommand(t *testing.T) {
+	t.Run("should require a function name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsRunCommandFactory)
+		runCommand := cmd.(*FunctionsRunCommand)
+		runCommand.storage = u.NewEmptyStorage()
+
+		exitCode := runCommand.Run([]string{"--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errFunctionNameRequired.Error())
+	})
+
+	t.Run("should reject both --args and --args-file", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsRunCommandFactory)
+		runCommand := cmd.(*FunctionsRunCommand)
+		runCommand.storage = u.NewEmptyStorage()
+
+		exitCode := runCommand.Run([]string{"my_func", "--app-id=my-app-abcdef", "--args=[]", "--args-file=args.json"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errFunctionArgsConflict.Error())
+	})
+
+	t.Run("should reject invalid arguments", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsRunCommandFactory)
+		runCommand := cmd.(*FunctionsRunCommand)
+		runCommand.storage = u.NewEmptyStorage()
+
+		exitCode := runCommand.Run([]string{"my_func", "--app-id=my-app-abcdef", "--args={nope"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "failed to parse --args")
+	})
+
+	t.Run("should execute the function as the given user and print its result and logs", func(t *testing.T) {
+		var executedName, executedUserID string
+		var executedArgs []json.RawMessage
+
+		runCommand := setUpFunctionsRunCommand(func(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error) {
+			u.So(t, groupID, gc.ShouldEqual, "group-id")
+			u.So(t, appID, gc.ShouldEqual, "app-id")
+			executedName, executedUserID, executedArgs = name, userID, args
+			return &models.FunctionExecution{
+				Result: json.RawMessage(`{"sum":{"$numberInt":"3"}}`),
+				Logs:   []string{"adding numbers"},
+			}, nil
+		})
+		mockUI := runCommand.UI.(*cli.MockUi)
+
+		exitCode := runCommand.Run([]string{"sum", "--app-id=my-app-abcdef", "--args=[1, 2]", "--as-user=user-id"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		u.So(t, executedName, gc.ShouldEqual, "sum")
+		u.So(t, executedUserID, gc.ShouldEqual, "user-id")
+		u.So(t, executedArgs, gc.ShouldResemble, []json.RawMessage{json.RawMessage("1"), json.RawMessage("2")})
+
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "adding numbers\n{\n    \"sum\": {\n        \"$numberInt\": \"3\"\n    }\n}\n")
+	})
+
+	t.Run("should read arguments from a file and wrap a single value", func(t *testing.T) {
+		argsFile, err := ioutil.TempFile("", "args")
+		u.So(t, err, gc.ShouldBeNil)
+		defer os.Remove(argsFile.Name())
+
+		_, err = argsFile.WriteString(`{"$oid": "5a1f0c3f4fdd1d1e8c7f2a1b"}`)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, argsFile.Close(), gc.ShouldBeNil)
+
+		var executedUserID string
+		var executedArgs []json.RawMessage
+
+		runCommand := setUpFunctionsRunCommand(func(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error) {
+			executedUserID, executedArgs = userID, args
+			return &models.FunctionExecution{}, nil
+		})
+		mockUI := runCommand.UI.(*cli.MockUi)
+
+		exitCode := runCommand.Run([]string{"lookup", "--app-id=my-app-abcdef", "--args-file=" + argsFile.Name()})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, executedUserID, gc.ShouldBeEmpty)
+		u.So(t, executedArgs, gc.ShouldResemble, []json.RawMessage{json.RawMessage(`{"$oid": "5a1f0c3f4fdd1d1e8c7f2a1b"}`)})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "undefined\n")
+	})
+
+	t.Run("should fail when the function throws an error", func(t *testing.T) {
+		runCommand := setUpFunctionsRunCommand(func(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error) {
+			return &models.FunctionExecution{
+				ErrorLogs: []string{"something looks off"},
+				Error:     &models.FunctionExecutionError{Name: "TypeError", Message: "x is undefined"},
+			}, nil
+		})
+		mockUI := runCommand.UI.(*cli.MockUi)
+
+		exitCode := runCommand.Run([]string{"broken", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "something looks off")
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `function "broken" threw an error: TypeError: x is undefined`)
+	})
+}
+
+func TestFunctionsTestCommand(t *testing.T) {
+	t.Run("should run a function from the local app directory", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsTestCommandFactory)
+		testCommand := cmd.(*FunctionsTestCommand)
+		testCommand.storage = u.NewEmptyStorage()
+
+		exitCode := testCommand.Run([]string{"function_a", "--path=../testdata/full_app", "--args=[41]"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "42\n")
+	})
+
+	t.Run("should stub context from values, sibling functions and fixtures", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		err := utils.WriteJSONFile(filepath.Join(appDir, "values", "increment.json"), map[string]interface{}{
+			"name":  "increment",
+			"value": 1,
+		})
+		u.So(t, err, gc.ShouldBeNil)
+
+		for name, source := range map[string]string{
+			"add": `exports = (a, b) => a + b;`,
+			"visit": `exports = async function() {
+  const counter = await context.services.get("mongodb-atlas").db("app").collection("counters").findOne({ name: "visits" });
+  console.log("current count", counter.count);
+  return context.functions.execute("add", counter.count, context.values.get("increment"));
+};`,
+		} {
+			err := utils.WriteLocalFunction(appDir, &utils.LocalFunction{
+				Name:   name,
+				Config: map[string]interface{}{"name": name},
+				Source: source,
+			})
+			u.So(t, err, gc.ShouldBeNil)
+		}
+
+		cmd, mockUI := setUpAppCommand(NewFunctionsTestCommandFactory)
+		testCommand := cmd.(*FunctionsTestCommand)
+		testCommand.storage = u.NewEmptyStorage()
+
+		exitCode := testCommand.Run([]string{"visit", "--path=" + appDir, "--fixtures=../testdata/function_fixtures/services.json"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "current count 41\n42\n")
+	})
+
+	t.Run("should fail when the function cannot be run", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsTestCommandFactory)
+		testCommand := cmd.(*FunctionsTestCommand)
+		testCommand.storage = u.NewEmptyStorage()
+
+		exitCode := testCommand.Run([]string{"missing", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `failed to run function "missing": function "missing" not found`)
+	})
+}
@@ -0,0 +1,316 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+func newTempAppDirectory(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "stitch-app")
+	u.So(t, err, gc.ShouldBeNil)
+
+	err = ioutil.WriteFile(filepath.Join(dir, models.AppConfigFileName), []byte(`{"app_id": "my-app-abcdef", "name": "my-app"}`), 0600)
+	u.So(t, err, gc.ShouldBeNil)
+
+	return dir
+}
+
+// copyTestdataDirToTempApp returns a new temporary app directory holding a copy of the files in the given
+// subdirectory of the full_app testdata, e.g. "triggers"
+func copyTestdataDirToTempApp(t *testing.T, subdir string) string {
+	appDir := newTempAppDirectory(t)
+
+	files, err := filepath.Glob(filepath.Join("../testdata/full_app", subdir, "*"))
+	u.So(t, err, gc.ShouldBeNil)
+
+	u.So(t, os.MkdirAll(filepath.Join(appDir, subdir), 0700), gc.ShouldBeNil)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, ioutil.WriteFile(filepath.Join(appDir, subdir, filepath.Base(file)), data, 0600), gc.ShouldBeNil)
+	}
+
+	return appDir
+}
+
+func setUpAppCommand(factory func(ui cli.Ui) cli.CommandFactory) (cli.Command, *cli.MockUi) {
+	mockUI := cli.NewMockUi()
+	cmd, err := factory(mockUI)()
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd, mockUI
+}
+
+func TestFunctionsListCommand(t *testing.T) {
+	t.Run("should list local functions", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsListCommandFactory)
+		listCommand := cmd.(*FunctionsListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "function_a (private)\nfunction_b (private)\n")
+	})
+
+	t.Run("should require the user to be logged in to list deployed functions", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsListCommandFactory)
+		listCommand := cmd.(*FunctionsListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/full_app", "--remote"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, user.ErrNotLoggedIn.Error())
+	})
+
+	t.Run("should list deployed functions", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsListCommandFactory)
+		listCommand := cmd.(*FunctionsListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+		listCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.FunctionsFn = func(groupID, appID string) ([]*models.Function, error) {
+			u.So(t, groupID, gc.ShouldEqual, "group-id")
+			u.So(t, appID, gc.ShouldEqual, "app-id")
+			return []*models.Function{{Name: "remote_fn"}}, nil
+		}
+		listCommand.stitchClient = stitchClient
+
+		exitCode := listCommand.Run([]string{"--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "remote_fn\n")
+	})
+}
+
+func TestFunctionsShowCommand(t *testing.T) {
+	t.Run("should require a function name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsShowCommandFactory)
+		showCommand := cmd.(*FunctionsShowCommand)
+		showCommand.storage = u.NewEmptyStorage()
+
+		exitCode := showCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errFunctionNameRequired.Error())
+	})
+
+	t.Run("should reject unexpected arguments", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsShowCommandFactory)
+		showCommand := cmd.(*FunctionsShowCommand)
+		showCommand.storage = u.NewEmptyStorage()
+
+		exitCode := showCommand.Run([]string{"function_a", "function_b", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "unexpected arguments: function_b")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldBeEmpty)
+	})
+
+	t.Run("should print the config and source of a local function", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsShowCommandFactory)
+		showCommand := cmd.(*FunctionsShowCommand)
+		showCommand.storage = u.NewEmptyStorage()
+
+		exitCode := showCommand.Run([]string{"function_a", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, `"name": "function_a"`)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "return x + 1;")
+	})
+
+	t.Run("should print the source of a deployed function", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsShowCommandFactory)
+		showCommand := cmd.(*FunctionsShowCommand)
+		showCommand.storage = u.NewEmptyStorage()
+		showCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.FunctionsFn = func(groupID, appID string) ([]*models.Function, error) {
+			return []*models.Function{{ID: "fn-id", Name: "remote_fn"}}, nil
+		}
+		stitchClient.FunctionFn = func(groupID, appID, functionID string) (*models.Function, error) {
+			u.So(t, functionID, gc.ShouldEqual, "fn-id")
+			return &models.Function{ID: "fn-id", Name: "remote_fn", Source: "exports = function() { return 42; };"}, nil
+		}
+		showCommand.stitchClient = stitchClient
+
+		exitCode := showCommand.Run([]string{"remote_fn", "--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "return 42;")
+	})
+}
+
+func TestFunctionsNewCommand(t *testing.T) {
+	t.Run("should scaffold a new local function", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewFunctionsNewCommandFactory)
+		newCommand := cmd.(*FunctionsNewCommand)
+		newCommand.storage = u.NewEmptyStorage()
+
+		exitCode := newCommand.Run([]string{"my_func", "--path=" + appDir, "--private"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		fn, err := utils.ReadLocalFunction(appDir, "my_func")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, fn.Private(), gc.ShouldBeTrue)
+		u.So(t, fn.Source, gc.ShouldEqual, utils.FunctionSourceTemplate)
+
+		app, err := utils.UnmarshalFromDir(appDir)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app["functions"], gc.ShouldHaveLength, 1)
+
+		t.Run("and refuse to overwrite it", func(t *testing.T) {
+			cmd, mockUI := setUpAppCommand(NewFunctionsNewCommandFactory)
+			newCommand := cmd.(*FunctionsNewCommand)
+			newCommand.storage = u.NewEmptyStorage()
+
+			exitCode := newCommand.Run([]string{"my_func", "--path=" + appDir})
+			u.So(t, exitCode, gc.ShouldEqual, 1)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "already exists")
+		})
+
+		t.Run("and push it to the deployed app", func(t *testing.T) {
+			fn.Config["run_as_system"] = true
+			u.So(t, utils.WriteLocalFunction(appDir, fn), gc.ShouldBeNil)
+
+			cmd, mockUI := setUpAppCommand(NewFunctionsNewCommandFactory)
+			newCommand := cmd.(*FunctionsNewCommand)
+			newCommand.storage = u.NewEmptyStorage()
+			newCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+			var updated *models.Function
+			newCommand.stitchClient = &u.MockStitchClient{
+				FetchAppByClientAppIDFn: func(clientAppID string) (*models.App, error) {
+					u.So(t, clientAppID, gc.ShouldEqual, "my-app-abcdef")
+					return &models.App{GroupID: "group-id", ID: "app-id", ClientAppID: clientAppID}, nil
+				},
+				FunctionsFn: func(groupID, appID string) ([]*models.Function, error) {
+					return []*models.Function{{ID: "fn-id", Name: "my_func"}}, nil
+				},
+				UpdateFunctionFn: func(groupID, appID string, function *models.Function) error {
+					updated = function
+					return nil
+				},
+			}
+
+			exitCode := newCommand.Run([]string{"my_func", "--path=" + appDir, "--remote"})
+			u.So(t, exitCode, gc.ShouldEqual, 0)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+			u.So(t, updated.ID, gc.ShouldEqual, "fn-id")
+			u.So(t, updated.Private, gc.ShouldBeTrue)
+			u.So(t, updated.Source, gc.ShouldEqual, utils.FunctionSourceTemplate)
+
+			body, err := json.Marshal(updated)
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, string(body), gc.ShouldContainSubstring, `"run_as_system":true`)
+			u.So(t, string(body), gc.ShouldContainSubstring, `"_id":"fn-id"`)
+		})
+	})
+
+	t.Run("should reject function names that are not valid directory names", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewFunctionsNewCommandFactory)
+		newCommand := cmd.(*FunctionsNewCommand)
+		newCommand.storage = u.NewEmptyStorage()
+
+		exitCode := newCommand.Run([]string{"../../escaped", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `invalid function name "../../escaped"`)
+
+		_, err := os.Stat(filepath.Join(appDir, "..", "escaped"))
+		u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	})
+
+	t.Run("should create a function that does not exist on the deployed app", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewFunctionsNewCommandFactory)
+		newCommand := cmd.(*FunctionsNewCommand)
+		newCommand.storage = u.NewEmptyStorage()
+		newCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var created *models.Function
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.FunctionsFn = func(groupID, appID string) ([]*models.Function, error) {
+			return []*models.Function{}, nil
+		}
+		stitchClient.CreateFunctionFn = func(groupID, appID string, function *models.Function) (*models.Function, error) {
+			created = function
+			return function, nil
+		}
+		newCommand.stitchClient = stitchClient
+
+		exitCode := newCommand.Run([]string{"my_func", "--path=" + appDir, "--remote"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, created.Name, gc.ShouldEqual, "my_func")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Created function 'my_func' in 'my-app-abcdef'")
+	})
+}
+
+func TestFunctionsRemoveCommand(t *testing.T) {
+	t.Run("should remove a local function once confirmed", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		err := utils.WriteLocalFunction(appDir, &utils.LocalFunction{
+			Name:   "my_func",
+			Config: map[string]interface{}{"name": "my_func"},
+			Source: utils.FunctionSourceTemplate,
+		})
+		u.So(t, err, gc.ShouldBeNil)
+
+		cmd, mockUI := setUpAppCommand(NewFunctionsRemoveCommandFactory)
+		removeCommand := cmd.(*FunctionsRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		mockUI.InputReader = strings.NewReader("y\n")
+
+		exitCode := removeCommand.Run([]string{"my_func", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+
+		_, err = os.Stat(filepath.Join(appDir, "functions", "my_func"))
+		u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	})
+
+	t.Run("should remove a deployed function", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewFunctionsRemoveCommandFactory)
+		removeCommand := cmd.(*FunctionsRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		removeCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var deletedID string
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.FunctionsFn = func(groupID, appID string) ([]*models.Function, error) {
+			return []*models.Function{{ID: "fn-id", Name: "remote_fn"}}, nil
+		}
+		stitchClient.FunctionFn = func(groupID, appID, functionID string) (*models.Function, error) {
+			return &models.Function{ID: functionID, Name: "remote_fn"}, nil
+		}
+		stitchClient.DeleteFunctionFn = func(groupID, appID, functionID string) error {
+			deletedID = functionID
+			return nil
+		}
+		removeCommand.stitchClient = stitchClient
+
+		exitCode := removeCommand.Run([]string{"remote_fn", "--remote", "--app-id=my-app-abcdef", "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, deletedID, gc.ShouldEqual, "fn-id")
+	})
+}
@@ -21,10 +21,16 @@ const (
 	importFlagPath        = "path"
 	importFlagStrategy    = "strategy"
 	importFlagAppName     = "app-name"
+	importFlagRedacted    = "redacted-values"
+	importFlagTemplate    = "template"
+	importFlagParam       = "param"
+	importFlagArchive     = "archive"
 	importStrategyMerge   = "merge"
 	importStrategyReplace = "replace"
 )
 
+var errImportArchiveConflict = fmt.Errorf("--%s cannot be supplied together with --%s or --%s", importFlagArchive, importFlagPath, importFlagTemplate)
+
 func errCreateAppSyncFailure(err error) error {
 	return fmt.Errorf("failed to sync app with local directory after creation: %s", err)
 }
@@ -63,17 +69,25 @@ type ImportCommand struct {
 	writeAppConfigToFile func(dest string, app models.AppInstanceData) error
 	workingDirectory     string
 
-	flagAppID    string
-	flagAppPath  string
-	flagAppName  string
-	flagGroupID  string
-	flagStrategy string
+	flagAppID          string
+	flagAppPath        string
+	flagAppName        string
+	flagGroupID        string
+	flagStrategy       string
+	flagRedactedValues string
+	flagTemplate       bool
+	flagParams         keyValueFlag
+	flagArchive        string
 }
 
 // Help returns long-form help information for this command
 func (ic *ImportCommand) Help() string {
 	return `Import and deploy a stitch application from a local directory.
 
+Before importing into an existing app, the changes are shown grouped by entity for confirmation, along with
+a line-level diff of the source of each modified function. When stdout is a terminal they are paged through
+$PAGER (default: "less -FRX").
+
 REQUIRED:
   --app-id [string]
 	The App ID for your app (i.e. the name of your app followed by a unique suffix, like "my-app-nysja").
@@ -93,6 +107,22 @@ OPTIONS:
 
 	merge - import and overwrite existing entities while preserving those that exist on Stitch. Secrets missing will not be lost.
 	replace - like merge but does not preserve entities missing from the local directory's app configuration.
+
+  --redacted-values [string]
+	A path to a filled-in copy of the "secrets.template.json" file written by "export --redact", holding the
+	JSON value of each redacted field. Required when the local directory contains redacted placeholders.
+
+  --template
+	Create a new app from the template in the local directory. The parameters declared in its template.json
+	are substituted for their {{name}} placeholders, prompting for any not supplied with --param. The
+	template directory itself is left untouched.
+
+  --param [name=value]
+	The value of a template parameter. May be repeated. List parameters take comma-separated values.
+
+  --archive [string]
+	Import the app from a zip or tar.gz archive, such as one written by "export --archive", instead of a
+	directory. The archive is read as is and left untouched, so it is not updated with the imported IDs.
 	` +
 		ic.BaseCommand.Help()
 }
@@ -111,6 +141,12 @@ func (ic *ImportCommand) Run(args []string) int {
 	set.StringVar(&ic.flagGroupID, flagProjectIDName, "", "")
 	set.StringVar(&ic.flagAppName, importFlagAppName, "", "")
 	set.StringVar(&ic.flagStrategy, importFlagStrategy, importStrategyMerge, "")
+	set.StringVar(&ic.flagRedactedValues, importFlagRedacted, "", "")
+	set.BoolVar(&ic.flagTemplate, importFlagTemplate, false, "")
+	set.StringVar(&ic.flagArchive, importFlagArchive, "", "")
+
+	ic.flagParams = keyValueFlag{}
+	set.Var(ic.flagParams, importFlagParam, "")
 
 	if err := ic.BaseCommand.run(args); err != nil {
 		ic.UI.Error(err.Error())
@@ -140,19 +176,42 @@ func (ic *ImportCommand) importApp() error {
 		return u.ErrNotLoggedIn
 	}
 
-	appPath, err := ic.resolveAppDirectory()
-	if err != nil {
-		return err
-	}
+	var appPath string
+	var appInstanceData models.AppInstanceData
+	var loadedApp map[string]interface{}
+	if ic.flagArchive != "" {
+		if ic.flagAppPath != "" || ic.flagTemplate {
+			return errImportArchiveConflict
+		}
 
-	appInstanceData, err := ic.resolveAppInstanceData(appPath)
-	if err != nil {
-		return err
+		if loadedApp, err = ic.loadArchive(); err != nil {
+			return err
+		}
+
+		appInstanceData = ic.resolveArchiveInstanceData(loadedApp)
+	} else {
+		if appPath, err = ic.resolveAppDirectory(); err != nil {
+			return err
+		}
+
+		if ic.flagTemplate {
+			return ic.importTemplate(appPath)
+		}
+
+		if appInstanceData, err = ic.resolveAppInstanceData(appPath); err != nil {
+			return err
+		}
+
+		if loadedApp, err = utils.UnmarshalFromDir(appPath); err != nil {
+			return err
+		}
 	}
 
-	loadedApp, err := utils.UnmarshalFromDir(appPath)
-	if err != nil {
-		return err
+	isRedacted := len(utils.RedactedKeys(loadedApp)) != 0
+	if isRedacted {
+		if err := ic.resolveRedactedValues(loadedApp); err != nil {
+			return err
+		}
 	}
 
 	appData, err := json.Marshal(loadedApp)
@@ -197,8 +256,10 @@ func (ic *ImportCommand) importApp() error {
 		appInstanceData[models.AppIDField] = app.ClientAppID
 		appInstanceData[models.AppNameField] = app.Name
 
-		if err := ic.writeAppConfigToFile(appPath, appInstanceData); err != nil {
-			return errCreateAppSyncFailure(err)
+		if appPath != "" {
+			if err := ic.writeAppConfigToFile(appPath, appInstanceData); err != nil {
+				return errCreateAppSyncFailure(err)
+			}
 		}
 	}
 
@@ -214,9 +275,9 @@ func (ic *ImportCommand) importApp() error {
 			return nil
 		}
 
-		for _, diff := range diffs {
-			ic.UI.Info(diff)
-		}
+		ic.showImportDiffs(diffs, loadedApp, func() (map[string]interface{}, error) {
+			return exportDeployedApp(stitchClient, app)
+		})
 
 		confirm, err := ic.AskYesNo("Please confirm the changes shown above:")
 		if err != nil {
@@ -232,6 +293,12 @@ func (ic *ImportCommand) importApp() error {
 		return fmt.Errorf("failed to import app: %s", err)
 	}
 
+	// archives are immutable artifacts, so only directories are synced
+	if appPath == "" {
+		ic.UI.Info(fmt.Sprintf("Successfully imported '%s'", app.ClientAppID))
+		return nil
+	}
+
 	// re-fetch imported app to sync IDs
 	_, body, err := stitchClient.Export(app.GroupID, app.ID, false)
 	if err != nil {
@@ -240,7 +307,16 @@ func (ic *ImportCommand) importApp() error {
 
 	defer body.Close()
 
-	if err := ic.writeToDirectory(appPath, body, true); err != nil {
+	// keep plaintext values out of a directory that was imported from a redacted export
+	var appZip io.Reader = body
+	if isRedacted {
+		appZip, err = utils.RedactAppZip(body)
+		if err != nil {
+			return errImportAppSyncFailure(err)
+		}
+	}
+
+	if err := ic.writeToDirectory(appPath, appZip, true); err != nil {
 		return errImportAppSyncFailure(err)
 	}
 
@@ -249,127 +325,135 @@ func (ic *ImportCommand) importApp() error {
 	return nil
 }
 
-func (ic *ImportCommand) resolveGroupID() (string, error) {
-	if ic.flagGroupID != "" {
-		return ic.flagGroupID, nil
+func (ic *ImportCommand) askCreateEmptyApp(query string, defaultAppName string, stitchClient api.StitchClient) (*models.App, bool, error) {
+	if ic.flagAppName != "" {
+		defaultAppName = ic.flagAppName
 	}
 
-	atlasClient, err := ic.AtlasClient()
+	confirm, err := ic.AskYesNo(fmt.Sprintf("%s: would you like to create a new app?", query))
 	if err != nil {
-		return "", fmt.Errorf("failed to find Project: %s", err)
+		return nil, false, err
 	}
 
-	groups, err := atlasClient.Groups()
-	if err != nil {
-		return "", fmt.Errorf("failed to find Project: %s", err)
+	if !confirm {
+		return nil, false, nil
 	}
 
-	groupsByName := map[string]string{}
-	for _, group := range groups {
-		groupsByName[group.Name] = group.ID
+	appName, err := ic.Ask("App name", defaultAppName)
+	if err != nil {
+		return nil, false, err
 	}
 
-	if len(groupsByName) == 0 {
-		return "", errors.New("no available Projects")
+	app, err := ic.createEmptyApp(ic.flagGroupID, appName, stitchClient)
+	if err != nil {
+		return nil, false, err
 	}
 
-	ic.UI.Info("Available Projects:")
+	return app, true, nil
+}
 
-	for name, id := range groupsByName {
-		ic.UI.Info(fmt.Sprintf("%s - %s", name, id))
+// importTemplate creates a new app from the template in appPath, substituting its parameters
+func (ic *ImportCommand) importTemplate(appPath string) error {
+	manifest, err := utils.ReadTemplateManifest(appPath)
+	if err != nil {
+		return err
 	}
 
-	var groupID string
-	for {
-		projectResponse, err := ic.Ask("Atlas Project Name or ID", groups[0].Name)
-		if err != nil {
-			return "", err
-		}
+	values, err := ic.resolveTemplateParams(manifest)
+	if err != nil {
+		return err
+	}
 
-		if isObjectIDHex(projectResponse) {
-			groupID = projectResponse
-			break
-		}
+	loadedApp, err := utils.UnmarshalFromDir(appPath)
+	if err != nil {
+		return err
+	}
 
-		groupID = groupsByName[projectResponse]
-		if groupID != "" {
-			break
-		}
+	if err := manifest.Apply(loadedApp, values); err != nil {
+		return err
+	}
 
-		groupFromName, err := atlasClient.GroupByName(projectResponse)
-		if err != nil {
-			return "", err
+	if len(utils.RedactedKeys(loadedApp)) != 0 {
+		if err := ic.resolveRedactedValues(loadedApp); err != nil {
+			return err
 		}
+	}
 
-		groupID = groupFromName.ID
-		if groupID != "" {
-			break
+	appName := ic.flagAppName
+	if appName == "" {
+		appName, _ = loadedApp[models.AppNameField].(string)
+	}
+	if appName == "" {
+		if appName, err = ic.Ask("App name", ""); err != nil {
+			return err
 		}
-
-		ic.UI.Info("Could not understand response, please try again")
 	}
 
-	return groupID, nil
-}
-
-func (ic *ImportCommand) askCreateEmptyApp(query string, defaultAppName string, stitchClient api.StitchClient) (*models.App, bool, error) {
-	if ic.flagAppName != "" {
-		defaultAppName = ic.flagAppName
+	stitchClient, err := ic.StitchClient()
+	if err != nil {
+		return err
 	}
 
-	confirm, err := ic.AskYesNo(fmt.Sprintf("%s: would you like to create a new app?", query))
+	app, err := ic.createEmptyApp(ic.flagGroupID, appName, stitchClient)
 	if err != nil {
-		return nil, false, err
+		return err
 	}
 
-	if !confirm {
-		return nil, false, nil
-	}
+	loadedApp[models.AppNameField] = app.Name
+	delete(loadedApp, models.AppIDField)
 
-	appName, err := ic.Ask("App name", defaultAppName)
+	appData, err := json.Marshal(loadedApp)
 	if err != nil {
-		return nil, false, err
+		return err
 	}
 
-	groupID, err := ic.resolveGroupID()
-	if err != nil {
-		return nil, false, err
+	if err := stitchClient.Import(app.GroupID, app.ID, appData, importStrategyReplace); err != nil {
+		return fmt.Errorf("failed to import template into app '%s': %s", app.ClientAppID, err)
 	}
 
-	apps, err := stitchClient.FetchAppsByGroupID(groupID)
-	if err != nil {
-		return nil, false, err
-	}
+	ic.UI.Info(fmt.Sprintf("Successfully imported template into '%s'. Use \"stitch-cli export --app-id=%s\" to work on it locally", app.ClientAppID, app.ClientAppID))
 
-	for _, app := range apps {
-		if app.Name == appName {
-			return nil, false, fmt.Errorf("app already exists with name %q", appName)
-		}
+	return nil
+}
+
+// resolveTemplateParams returns the value of every parameter of the template, taken from the --param
+// flags or prompted for
+func (ic *ImportCommand) resolveTemplateParams(manifest *utils.TemplateManifest) (map[string]string, error) {
+	declared := map[string]bool{}
+	for _, param := range manifest.Parameters {
+		declared[param.Name] = true
 	}
 
-	app, err := stitchClient.CreateEmptyApp(groupID, appName)
-	if err != nil {
-		return nil, false, err
+	for name := range ic.flagParams {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown template parameter %q", name)
+		}
 	}
 
-	ic.UI.Info(fmt.Sprintf("New app created: %s", app.ClientAppID))
-	return app, true, nil
-}
+	values := map[string]string{}
+	for _, param := range manifest.Parameters {
+		if value, ok := ic.flagParams[param.Name]; ok {
+			values[param.Name] = value
+			continue
+		}
 
-func (ic *ImportCommand) resolveAppDirectory() (string, error) {
-	if ic.flagAppPath != "" {
-		path, err := homedir.Expand(ic.flagAppPath)
-		if err != nil {
-			return "", err
+		query := param.Name
+		if param.Description != "" {
+			query = fmt.Sprintf("%s (%s)", param.Description, param.Name)
 		}
 
-		if _, err := os.Stat(path); err != nil {
-			return "", errors.New("directory does not exist")
+		value, err := ic.Ask(query, param.Default)
+		if err != nil {
+			return nil, err
 		}
-		return path, nil
+		values[param.Name] = value
 	}
 
-	return utils.GetDirectoryContainingFile(ic.workingDirectory, models.AppConfigFileName)
+	return values, nil
+}
+
+func (ic *ImportCommand) resolveAppDirectory() (string, error) {
+	return resolveAppDirectory(ic.flagAppPath, ic.workingDirectory)
 }
 
 // resolveAppInstanceData loads data for an app from a stitch.json file located in the provided directory path,
@@ -395,6 +479,39 @@ func (ic *ImportCommand) resolveAppInstanceData(path string) (models.AppInstance
 	return appInstanceDataFromFile, nil
 }
 
+// loadArchive loads the app from the archive passed with the --archive flag
+func (ic *ImportCommand) loadArchive() (map[string]interface{}, error) {
+	archivePath, err := homedir.Expand(ic.flagArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.UnmarshalFromArchive(archivePath)
+}
+
+// resolveArchiveInstanceData returns the data identifying the app loaded from an archive, merging in any
+// overridden parameters from command line flags
+func (ic *ImportCommand) resolveArchiveInstanceData(loadedApp map[string]interface{}) models.AppInstanceData {
+	appInstanceData := models.AppInstanceData{}
+	for _, field := range []string{models.AppIDField, models.AppNameField} {
+		if value, ok := loadedApp[field].(string); ok {
+			appInstanceData[field] = value
+		}
+	}
+
+	if ic.flagAppID != "" {
+		appInstanceData[models.AppIDField] = ic.flagAppID
+	}
+
+	return appInstanceData
+}
+
+// resolveRedactedValues replaces the redacted placeholders in the loaded app with the values provided
+// in the file passed with the --redacted-values flag
+func (ic *ImportCommand) resolveRedactedValues(app map[string]interface{}) error {
+	return ic.resolveRedactedValuesFromFile(app, ic.flagRedactedValues)
+}
+
 // isObjectIDHex returns whether s is a valid hex representation of an ObjectId.
 // copied from mgo/bson#IsObjectIdHex
 func isObjectIDHex(s string) bool {
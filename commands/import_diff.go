@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/10gen/stitch-cli/api"
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mattn/go-isatty"
+	"github.com/mitchellh/cli"
+)
+
+const (
+	importDiffKindFunction = "function"
+
+	// defaultPager is used to page import diffs when $PAGER is not set. It exits right away if the diff
+	// fits on one screen and passes colors through
+	defaultPager = "less -FRX"
+)
+
+var (
+	importDiffSymbols = map[string]string{
+		utils.DiffAdded:    "+",
+		utils.DiffModified: "~",
+		utils.DiffRemoved:  "-",
+	}
+
+	importDiffColors = map[string]cli.UiColor{
+		"+":  cli.UiColorGreen,
+		"~":  cli.UiColorYellow,
+		"-":  cli.UiColorRed,
+		"@@": cli.UiColorCyan,
+	}
+)
+
+// showImportDiffs displays the diffs returned by the server grouped by entity, along with a line-level diff
+// of the source of each modified function. deployedApp is only called when such functions are found
+func (c *BaseCommand) showImportDiffs(diffs []string, localApp map[string]interface{}, deployedApp func() (map[string]interface{}, error)) {
+	groups := utils.GroupImportDiffs(diffs)
+
+	var localSources, deployedSources map[string]string
+	if hasModifiedFunctions(groups) {
+		deployed, err := deployedApp()
+		if err != nil {
+			c.UI.Warn(fmt.Sprintf("Unable to show function source changes: %s", err))
+		} else {
+			localSources = utils.FunctionSources(localApp)
+			deployedSources = utils.FunctionSources(deployed)
+		}
+	}
+
+	c.page(renderImportDiffs(groups, localSources, deployedSources, c.colorEnabled()))
+}
+
+// colorEnabled returns whether output may contain color codes
+func (c *BaseCommand) colorEnabled() bool {
+	return !c.flagColorDisabled && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// page writes content through $PAGER when stdout is a terminal, and to the UI otherwise or if the pager
+// cannot be run
+func (c *BaseCommand) page(content string) {
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		c.UI.Output(content)
+		return
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = strings.Fields(defaultPager)
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = strings.NewReader(content + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		c.UI.Output(content)
+	}
+}
+
+// exportDeployedApp exports the deployed configuration of app and unpacks it into app data
+func exportDeployedApp(stitchClient api.StitchClient, app *models.App) (map[string]interface{}, error) {
+	_, body, err := stitchClient.Export(app.GroupID, app.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return utils.UnmarshalFromZip(body)
+}
+
+func hasModifiedFunctions(groups []utils.ImportDiffGroup) bool {
+	for _, group := range groups {
+		if group.Kind != importDiffKindFunction {
+			continue
+		}
+
+		for _, diff := range group.Diffs {
+			if diff.Action == utils.DiffModified {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// renderImportDiffs formats grouped import diffs under a summary header. The sources are those of the
+// local and deployed functions, used to diff modified functions when both are known
+func renderImportDiffs(groups []utils.ImportDiffGroup, localSources, deployedSources map[string]string, colored bool) string {
+	paint := func(symbol, line string) string {
+		if !colored {
+			return line
+		}
+		color := importDiffColors[symbol]
+		return fmt.Sprintf("\033[0;%dm%s\033[0m", color.Code, line)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Summary: %s\n", utils.SummarizeImportDiffs(groups))
+
+	for _, group := range groups {
+		fmt.Fprintf(&buf, "\n%s:\n", importDiffGroupTitle(group.Kind))
+
+		for _, diff := range group.Diffs {
+			symbol := importDiffSymbols[diff.Action]
+			fmt.Fprintf(&buf, "  %s\n", paint(symbol, symbol+" "+diff.Name))
+
+			for _, detail := range diff.Details {
+				fmt.Fprintf(&buf, "      %s\n", detail)
+			}
+
+			if group.Kind != importDiffKindFunction || diff.Action != utils.DiffModified {
+				continue
+			}
+
+			localSource, inLocal := localSources[diff.Name]
+			deployedSource, inDeployed := deployedSources[diff.Name]
+			if !inLocal || !inDeployed {
+				continue
+			}
+
+			for _, line := range utils.UnifiedDiff(diff.Name+" (deployed)", diff.Name+" (local)", deployedSource, localSource) {
+				switch {
+				case strings.HasPrefix(line, "@@"):
+					line = paint("@@", line)
+				case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+				case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+					line = paint(line[:1], line)
+				}
+				fmt.Fprintf(&buf, "      %s\n", line)
+			}
+		}
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// importDiffGroupTitle returns the heading of a group of diffs, such as "Auth Providers" for "auth provider"
+func importDiffGroupTitle(kind string) string {
+	if kind == utils.DiffKindOther {
+		return "Other"
+	}
+	return strings.Title(kind) + "s"
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestImportDiffs(t *testing.T) {
+	diffs := []string{
+		"Removed Value: b",
+		"Modified Function: function_a",
+		"New Function: function_c\n+ can_evaluate: {}",
+		"sample-diff-contents",
+	}
+
+	setup := func(exportFn func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error)) (*ImportCommand, *u.MockStitchClient, func() string) {
+		importCommand, mockUI := setUpBasicCommand()
+		importCommand.user = &user.User{APIKey: "my-api-key", AccessToken: u.GenerateValidAccessToken()}
+
+		mockUI.InputReader = strings.NewReader("n\n")
+		stitchClient := &u.MockStitchClient{
+			FetchAppByClientAppIDFn: func(clientAppID string) (*models.App, error) {
+				return &models.App{GroupID: "group-id", ID: "app-id"}, nil
+			},
+			DiffFn: func(groupID, appID string, appData []byte, strategy string) ([]string, error) {
+				return diffs, nil
+			},
+			ExportFn: exportFn,
+		}
+		importCommand.stitchClient = stitchClient
+
+		return importCommand, stitchClient, func() string { return mockUI.OutputWriter.String() + mockUI.ErrorWriter.String() }
+	}
+
+	t.Run("should group the diffs by entity under a summary and diff modified function sources", func(t *testing.T) {
+		exported := zipAppDirectory("../testdata/full_app", map[string]string{
+			"functions/function_a/source.js": "exports = function(x) {\n  return x + 2;\n};\n",
+		})
+		importCommand, stitchClient, output := setup(func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+			return "full-app_20180301.zip", u.NewResponseBody(bytes.NewReader(exported)), nil
+		})
+
+		exitCode := importCommand.Run([]string{"--path=../testdata/full_app", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, stitchClient.ImportFnCalls, gc.ShouldBeEmpty)
+
+		u.So(t, output(), gc.ShouldContainSubstring, strings.Join([]string{
+			"Summary: 1 function(s) added, 1 function(s) modified, 1 value(s) removed, 1 other change(s) modified",
+			"",
+			"Functions:",
+			"  + function_c",
+			"      + can_evaluate: {}",
+			"  ~ function_a",
+			"      --- function_a (deployed)",
+			"      +++ function_a (local)",
+			"      @@ -1,3 +1,3 @@",
+			"       exports = function(x) {",
+			"      -  return x + 2;",
+			"      +  return x + 1;",
+			"       };",
+			"",
+			"Values:",
+			"  - b",
+			"",
+			"Other:",
+			"  ~ sample-diff-contents",
+		}, "\n"))
+	})
+
+	t.Run("should still show the diffs if the deployed app cannot be exported", func(t *testing.T) {
+		importCommand, _, output := setup(func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+			return "", nil, errors.New("oh noes")
+		})
+
+		exitCode := importCommand.Run([]string{"--path=../testdata/full_app", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, output(), gc.ShouldContainSubstring, "Unable to show function source changes: oh noes")
+		u.So(t, output(), gc.ShouldContainSubstring, "  ~ function_a\n\nValues:")
+	})
+}
+
+func TestRenderImportDiffs(t *testing.T) {
+	groups := utils.GroupImportDiffs([]string{"Modified Function: f", "Deleted Auth Provider: api-key"})
+	sources := func(source string) map[string]string { return map[string]string{"f": source} }
+
+	t.Run("should title groups by kind", func(t *testing.T) {
+		rendered := renderImportDiffs(groups, nil, nil, false)
+		u.So(t, rendered, gc.ShouldEqual, strings.Join([]string{
+			"Summary: 1 auth provider(s) removed, 1 function(s) modified",
+			"",
+			"Auth Providers:",
+			"  - api-key",
+			"",
+			"Functions:",
+			"  ~ f",
+		}, "\n"))
+	})
+
+	t.Run("should color changes when enabled", func(t *testing.T) {
+		rendered := renderImportDiffs(groups, sources("a\nb\n"), sources("a\n"), true)
+		u.So(t, rendered, gc.ShouldContainSubstring, "\033[0;31m- api-key\033[0m")
+		u.So(t, rendered, gc.ShouldContainSubstring, "\033[0;33m~ f\033[0m")
+		u.So(t, rendered, gc.ShouldContainSubstring, "\033[0;36m@@ -1 +1,2 @@\033[0m")
+		u.So(t, rendered, gc.ShouldContainSubstring, "      \033[0;32m+b\033[0m")
+		u.So(t, rendered, gc.ShouldContainSubstring, "      --- f (deployed)\n")
+	})
+}
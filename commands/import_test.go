@@ -1,10 +1,13 @@
 package commands
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -13,6 +16,7 @@ import (
 	"github.com/10gen/stitch-cli/api/mdbcloud"
 	"github.com/10gen/stitch-cli/models"
 	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
 	u "github.com/10gen/stitch-cli/utils/test"
 	gc "github.com/smartystreets/goconvey/convey"
 
@@ -583,6 +587,68 @@ func TestImportCommand(t *testing.T) {
 			})
 		}
 
+		t.Run("with a redacted app directory", func(t *testing.T) {
+			redactedArgs := append([]string{"--path=../testdata/redacted_app"}, validArgs...)
+
+			t.Run("it fails if no redacted values are provided", func(t *testing.T) {
+				importCommand, mockUI := setup()
+				mockUI.InputReader = strings.NewReader("y\n")
+
+				exitCode := importCommand.Run(redactedArgs)
+				u.So(t, exitCode, gc.ShouldEqual, 1)
+				u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "app contains redacted values")
+				u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "values/value_b.json:value")
+			})
+
+			t.Run("it imports the provided values and keeps the synced directory redacted", func(t *testing.T) {
+				importCommand, mockUI := setup()
+				mockUI.InputReader = strings.NewReader("y\n")
+
+				var exported bytes.Buffer
+				w := zip.NewWriter(&exported)
+				fw, err := w.Create("values/value_b.json")
+				u.So(t, err, gc.ShouldBeNil)
+				_, err = fw.Write([]byte(`{"name": "b", "value": "BBBBBB", "private": true}`))
+				u.So(t, err, gc.ShouldBeNil)
+				u.So(t, w.Close(), gc.ShouldBeNil)
+
+				var importedData string
+				importCommand.stitchClient = &u.MockStitchClient{
+					ExportFn: func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+						return "", u.NewResponseBody(bytes.NewReader(exported.Bytes())), nil
+					},
+					DiffFn: func(groupID, appID string, appData []byte, strategy string) ([]string, error) {
+						return []string{"sample-diff-contents"}, nil
+					},
+					ImportFn: func(groupID, appID string, appData []byte, strategy string) error {
+						importedData = string(appData)
+						return nil
+					},
+					FetchAppByClientAppIDFn: func(clientAppID string) (*models.App, error) {
+						return &models.App{GroupID: "group-id", ID: "app-id"}, nil
+					},
+				}
+
+				var writeContent string
+				importCommand.writeToDirectory = func(dest string, zipData io.Reader, overwrite bool) error {
+					b, err := ioutil.ReadAll(zipData)
+					if err != nil {
+						return err
+					}
+					writeContent = string(b)
+					return nil
+				}
+
+				exitCode := importCommand.Run(append(redactedArgs, "--redacted-values=../testdata/redacted_app_values.json"))
+				u.So(t, exitCode, gc.ShouldEqual, 0)
+				u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+				u.So(t, importedData, gc.ShouldContainSubstring, "BBBBBB")
+				u.So(t, importedData, gc.ShouldNotContainSubstring, utils.RedactedPlaceholderPrefix)
+				u.So(t, writeContent, gc.ShouldNotContainSubstring, "BBBBBB")
+				u.So(t, writeContent, gc.ShouldContainSubstring, utils.SecretsTemplateFileName)
+			})
+		})
+
 		t.Run("syncing data after a successful import", func(t *testing.T) {
 			t.Run("on success", func(t *testing.T) {
 				type testCase struct {
@@ -654,6 +720,174 @@ func TestImportCommand(t *testing.T) {
 	})
 }
 
+func TestImportTemplate(t *testing.T) {
+	setup := func() (*ImportCommand, *cli.MockUi, *[]string, *string) {
+		importCommand, mockUI := setUpBasicCommand()
+		importCommand.user = &user.User{
+			APIKey:      "my-api-key",
+			AccessToken: u.GenerateValidAccessToken(),
+		}
+
+		createdGroupIDs := []string{}
+		var importedData string
+		importCommand.stitchClient = &u.MockStitchClient{
+			CreateEmptyAppFn: func(groupID, appName string) (*models.App, error) {
+				createdGroupIDs = append(createdGroupIDs, groupID)
+				return &models.App{GroupID: groupID, ID: "app-id", Name: appName, ClientAppID: appName + "-abcdef"}, nil
+			},
+			FetchAppsByGroupIDFn: func(groupID string) ([]*models.App, error) {
+				return []*models.App{{Name: "taken"}}, nil
+			},
+			ImportFn: func(groupID, appID string, appData []byte, strategy string) error {
+				importedData = string(appData)
+				return nil
+			},
+		}
+
+		return importCommand, mockUI, &createdGroupIDs, &importedData
+	}
+
+	t.Run("it substitutes flags and prompted values and creates the app in the chosen project", func(t *testing.T) {
+		importCommand, mockUI, createdGroupIDs, importedData := setup()
+		mockUI.InputReader = strings.NewReader("Cluster1\nhttps://api.example.com\n")
+
+		exitCode := importCommand.Run([]string{
+			"--path=../testdata/template_app",
+			"--template",
+			"--project-id=group-id",
+			"--param=app_name=shop",
+			"--param=allowed_origins=http://a.com,http://b.com",
+		})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, *createdGroupIDs, gc.ShouldResemble, []string{"group-id"})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Atlas cluster to link (cluster_name)")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Successfully imported template into 'shop-abcdef'")
+
+		var app map[string]interface{}
+		u.So(t, json.Unmarshal([]byte(*importedData), &app), gc.ShouldBeNil)
+		u.So(t, app["name"], gc.ShouldEqual, "shop")
+		u.So(t, app["security"], gc.ShouldResemble, map[string]interface{}{
+			"allowed_request_origins": []interface{}{"http://a.com", "http://b.com"},
+		})
+		u.So(t, *importedData, gc.ShouldContainSubstring, `"clusterName":"Cluster1"`)
+		u.So(t, *importedData, gc.ShouldContainSubstring, `"value":"https://api.example.com"`)
+
+		data, err := ioutil.ReadFile("../testdata/template_app/stitch.json")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(data), gc.ShouldContainSubstring, "{{app_name}}")
+	})
+
+	t.Run("it prefers --app-name to the templated name", func(t *testing.T) {
+		importCommand, mockUI, _, importedData := setup()
+
+		exitCode := importCommand.Run([]string{
+			"--path=../testdata/template_app",
+			"--template",
+			"--project-id=group-id",
+			"--app-name=store",
+			"--param=app_name=shop",
+			"--param=allowed_origins=",
+			"--param=api_url=https://api.example.com",
+			"-y",
+		})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, *importedData, gc.ShouldContainSubstring, `"name":"store"`)
+		u.So(t, *importedData, gc.ShouldContainSubstring, `"clusterName":"Cluster0"`)
+		u.So(t, *importedData, gc.ShouldContainSubstring, `"allowed_request_origins":[]`)
+	})
+
+	for _, tc := range []struct {
+		Description   string
+		Args          []string
+		ExpectedError string
+	}{
+		{
+			Description:   "it fails for directories that are not templates",
+			Args:          []string{"--path=../testdata/simple_app"},
+			ExpectedError: "is not an app template",
+		},
+		{
+			Description:   "it fails for unknown parameters",
+			Args:          []string{"--path=../testdata/template_app", "--param=region=us-east-1"},
+			ExpectedError: `unknown template parameter "region"`,
+		},
+		{
+			Description:   "it fails if an app with the same name exists",
+			Args:          []string{"--path=../testdata/template_app", "--param=app_name=taken", "--param=allowed_origins=", "--param=api_url=", "-y"},
+			ExpectedError: `app already exists with name "taken"`,
+		},
+	} {
+		t.Run(tc.Description, func(t *testing.T) {
+			importCommand, mockUI, _, importedData := setup()
+
+			exitCode := importCommand.Run(append([]string{"--template", "--project-id=group-id"}, tc.Args...))
+			u.So(t, exitCode, gc.ShouldEqual, 1)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, tc.ExpectedError)
+			u.So(t, *importedData, gc.ShouldBeEmpty)
+		})
+	}
+}
+
+func TestImportArchive(t *testing.T) {
+	setup := func(t *testing.T) (*ImportCommand, *cli.MockUi, *string) {
+		importCommand, mockUI := setUpBasicCommand()
+		importCommand.user = &user.User{
+			APIKey:      "my-api-key",
+			AccessToken: u.GenerateValidAccessToken(),
+		}
+		importCommand.writeToDirectory = func(dest string, r io.Reader, overwrite bool) error {
+			t.Errorf("should not write to %q", dest)
+			return nil
+		}
+
+		var importedData string
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.ImportFn = func(groupID, appID string, appData []byte, strategy string) error {
+			importedData = string(appData)
+			return nil
+		}
+		importCommand.stitchClient = stitchClient
+
+		return importCommand, mockUI, &importedData
+	}
+
+	t.Run("it imports the app within the archive without syncing it", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "stitch-archive")
+		u.So(t, err, gc.ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		archivePath := filepath.Join(dir, "app.zip")
+		u.So(t, ioutil.WriteFile(archivePath, zipAppDirectory("../testdata/full_app", nil), 0600), gc.ShouldBeNil)
+
+		importCommand, mockUI, importedData := setup(t)
+		exitCode := importCommand.Run([]string{"--archive=" + archivePath, "--app-id=full-app-abcdef", "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Successfully imported 'full-app-abcdef'")
+
+		var imported map[string]interface{}
+		u.So(t, json.Unmarshal([]byte(*importedData), &imported), gc.ShouldBeNil)
+		u.So(t, imported["name"], gc.ShouldEqual, "full-app")
+		u.So(t, imported["functions"], gc.ShouldHaveLength, 2)
+	})
+
+	t.Run("it fails if the archive cannot be read", func(t *testing.T) {
+		importCommand, mockUI, _ := setup(t)
+		exitCode := importCommand.Run([]string{"--archive=../testdata/full_app/stitch.json", "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "must be a zip or tar.gz file")
+	})
+
+	t.Run("it does not accept both an archive and a directory", func(t *testing.T) {
+		importCommand, mockUI, _ := setup(t)
+		exitCode := importCommand.Run([]string{"--archive=app.zip", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errImportArchiveConflict.Error())
+	})
+}
+
 func abs(path string) string {
 	p, err := filepath.Abs(path)
 	if err != nil {
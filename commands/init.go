@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+const (
+	initFlagTemplate  = "template"
+	initFlagName      = "name"
+	initFlagPath      = "path"
+	initFlagCreateApp = "create-app"
+
+	initTemplateBlank       = "blank"
+	initTemplateWithCluster = "with-cluster"
+
+	defaultClusterName = "Cluster0"
+)
+
+// NewInitCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewInitCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		workingDirectory, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+
+		return &InitCommand{
+			BaseCommand: &BaseCommand{
+				Name: "init",
+				UI:   ui,
+			},
+			workingDirectory: workingDirectory,
+		}, nil
+	}
+}
+
+// InitCommand is used to scaffold a new app directory
+type InitCommand struct {
+	*BaseCommand
+
+	workingDirectory string
+
+	flagTemplate  string
+	flagName      string
+	flagPath      string
+	flagCluster   string
+	flagCreateApp bool
+	flagProjectID string
+}
+
+// Synopsis returns a one-liner description for this command
+func (ic *InitCommand) Synopsis() string {
+	return `Create a new app directory from a template.`
+}
+
+// Help returns long-form help information for this command
+func (ic *InitCommand) Help() string {
+	return `Create a new app directory with a stitch.json and empty entity directories.
+
+The directory must not be within an existing app directory, and must be empty if it exists.
+
+Usage: stitch-cli init [options]
+
+OPTIONS:
+  --template [blank|with-cluster|string] (default: blank)
+	The template to create the app from.
+
+	blank - an app without any entities.
+	with-cluster - an app with a mongodb-atlas service linked to the cluster given by --cluster.
+	Any other value is the path to an app directory to copy, such as a git checkout.
+
+  --name [string]
+	The name of the app. Defaults to the name of a copied app directory, or is prompted for.
+
+  --path [string]
+	The directory to create the app in. Defaults to a directory named after the app within the
+	working directory.
+
+  --cluster [string] (default: Cluster0)
+	The name of the Atlas cluster linked by the with-cluster template.
+
+  --create-app
+	Create an empty app on Stitch and write its App ID to the new stitch.json.
+
+  --project-id [string]
+	The Atlas Project ID to create the app in. Prompted for if --create-app is supplied without it.
+` +
+		ic.BaseCommand.Help()
+}
+
+// Run executes the command
+func (ic *InitCommand) Run(args []string) int {
+	set := ic.NewFlagSet()
+
+	set.StringVar(&ic.flagTemplate, initFlagTemplate, initTemplateBlank, "")
+	set.StringVar(&ic.flagName, initFlagName, "", "")
+	set.StringVar(&ic.flagPath, initFlagPath, "", "")
+	set.StringVar(&ic.flagCluster, clustersFlagCluster, defaultClusterName, "")
+	set.BoolVar(&ic.flagCreateApp, initFlagCreateApp, false, "")
+	set.StringVar(&ic.flagProjectID, flagProjectIDName, "", "")
+
+	if err := ic.BaseCommand.run(args); err != nil {
+		ic.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := ic.init(); err != nil {
+		ic.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (ic *InitCommand) init() error {
+	if ic.flagCreateApp {
+		if err := ic.requireLogin(); err != nil {
+			return err
+		}
+	}
+
+	name, err := ic.resolveName()
+	if err != nil {
+		return err
+	}
+
+	appPath := ic.flagPath
+	if appPath == "" {
+		appPath = filepath.Join(ic.workingDirectory, name)
+	}
+
+	if dir, err := utils.GetDirectoryContainingFile(appPath, models.AppConfigFileName); err == nil {
+		return fmt.Errorf("cannot initialize an app within app directory %q", dir)
+	}
+
+	if err := utils.EnsureEmptyDirectory(appPath); err != nil {
+		return err
+	}
+
+	if err := ic.writeTemplate(appPath, name); err != nil {
+		return err
+	}
+
+	appInstanceData := models.AppInstanceData{}
+	if err := appInstanceData.UnmarshalFile(appPath); err != nil {
+		return err
+	}
+
+	appInstanceData[models.AppNameField] = name
+	delete(appInstanceData, models.AppIDField)
+	if _, ok := appInstanceData["config_version"]; !ok {
+		appInstanceData["config_version"] = utils.AppConfigVersion
+	}
+
+	if ic.flagCreateApp {
+		stitchClient, err := ic.StitchClient()
+		if err != nil {
+			return err
+		}
+
+		app, err := ic.createEmptyApp(ic.flagProjectID, name, stitchClient)
+		if err != nil {
+			return err
+		}
+
+		appInstanceData[models.AppIDField] = app.ClientAppID
+	}
+
+	if err := appInstanceData.MarshalFile(appPath); err != nil {
+		return err
+	}
+
+	ic.UI.Info(fmt.Sprintf("Initialized app '%s' in %s", name, appPath))
+	return nil
+}
+
+// resolveName returns the name of the new app, taken from --name, the copied app directory or a prompt
+func (ic *InitCommand) resolveName() (string, error) {
+	if ic.flagName != "" {
+		return ic.flagName, nil
+	}
+
+	var defaultName string
+	if !ic.isBuiltInTemplate() {
+		appInstanceData := models.AppInstanceData{}
+		if err := appInstanceData.UnmarshalFile(ic.flagTemplate); err == nil {
+			defaultName = appInstanceData.AppName()
+		}
+	}
+
+	name, err := ic.Ask("App name", defaultName)
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("an app name (--%s=[string]) must be supplied", initFlagName)
+	}
+
+	return name, nil
+}
+
+func (ic *InitCommand) isBuiltInTemplate() bool {
+	return ic.flagTemplate == initTemplateBlank || ic.flagTemplate == initTemplateWithCluster
+}
+
+// writeTemplate writes the chosen template to the app directory at appPath
+func (ic *InitCommand) writeTemplate(appPath, name string) error {
+	switch ic.flagTemplate {
+	case initTemplateBlank:
+		return utils.InitAppDirectory(appPath, name)
+	case initTemplateWithCluster:
+		if err := utils.InitAppDirectory(appPath, name); err != nil {
+			return err
+		}
+
+		service, err := utils.ServiceTemplates[atlasServiceType].NewLocalService(appPath, atlasServiceType, map[string]string{
+			"clusterName": ic.flagCluster,
+		})
+		if err != nil {
+			return err
+		}
+
+		return utils.WriteLocalService(service)
+	}
+
+	templatePath, err := filepath.Abs(ic.flagTemplate)
+	if err != nil {
+		return err
+	}
+
+	return utils.CopyAppDirectory(templatePath, appPath)
+}
@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+func setUpInitCommand(t *testing.T) (*InitCommand, *cli.MockUi, string) {
+	workingDirectory, err := ioutil.TempDir("", "stitch-init")
+	u.So(t, err, gc.ShouldBeNil)
+
+	mockUI := cli.NewMockUi()
+	cmd, err := NewInitCommandFactory(mockUI)()
+	u.So(t, err, gc.ShouldBeNil)
+
+	initCommand := cmd.(*InitCommand)
+	initCommand.storage = u.NewEmptyStorage()
+	initCommand.workingDirectory = workingDirectory
+
+	return initCommand, mockUI, workingDirectory
+}
+
+func readInitializedAppConfig(t *testing.T, appPath string) models.AppInstanceData {
+	appInstanceData := models.AppInstanceData{}
+	u.So(t, appInstanceData.UnmarshalFile(appPath), gc.ShouldBeNil)
+	return appInstanceData
+}
+
+func TestInitCommand(t *testing.T) {
+	t.Run("should scaffold a blank app named after the app within the working directory", func(t *testing.T) {
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		exitCode := initCommand.Run([]string{"--name=my-app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		appPath := filepath.Join(workingDirectory, "my-app")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Initialized app 'my-app' in "+appPath)
+		u.So(t, readInitializedAppConfig(t, appPath), gc.ShouldResemble, models.AppInstanceData{
+			"config_version": float64(utils.AppConfigVersion),
+			"name":           "my-app",
+			"security":       map[string]interface{}{"allowed_request_origins": []interface{}{}},
+		})
+
+		for _, dir := range utils.AppEntityDirectories {
+			info, err := os.Stat(filepath.Join(appPath, dir))
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, info.IsDir(), gc.ShouldBeTrue)
+		}
+
+		app, err := utils.UnmarshalFromDir(appPath)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app["name"], gc.ShouldEqual, "my-app")
+	})
+
+	t.Run("should link a cluster with the with-cluster template", func(t *testing.T) {
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		appPath := filepath.Join(workingDirectory, "app")
+		exitCode := initCommand.Run([]string{"--name=my-app", "--template=with-cluster", "--cluster=Analytics", "--path=" + appPath})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		service, err := utils.ReadLocalService(appPath, "mongodb-atlas")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, service.Type(), gc.ShouldEqual, "mongodb-atlas")
+		u.So(t, service.Config["config"], gc.ShouldResemble, map[string]interface{}{"clusterName": "Analytics"})
+	})
+
+	t.Run("should copy an app directory and drop its App ID", func(t *testing.T) {
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		exitCode := initCommand.Run([]string{"--template=../testdata/simple_app_with_instance_data", "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		appPath := filepath.Join(workingDirectory, "simple-app")
+		appInstanceData := readInitializedAppConfig(t, appPath)
+		u.So(t, appInstanceData.AppName(), gc.ShouldEqual, "simple-app")
+		u.So(t, appInstanceData.AppID(), gc.ShouldBeEmpty)
+
+		info, err := os.Stat(filepath.Join(appPath, "functions"))
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, info.IsDir(), gc.ShouldBeTrue)
+	})
+
+	t.Run("should create the app on Stitch and write its App ID", func(t *testing.T) {
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		initCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var createdGroupID string
+		initCommand.stitchClient = &u.MockStitchClient{
+			FetchAppsByGroupIDFn: func(groupID string) ([]*models.App, error) {
+				return []*models.App{}, nil
+			},
+			CreateEmptyAppFn: func(groupID, appName string) (*models.App, error) {
+				createdGroupID = groupID
+				return &models.App{GroupID: groupID, Name: appName, ClientAppID: appName + "-abcdef"}, nil
+			},
+		}
+
+		exitCode := initCommand.Run([]string{"--name=my-app", "--create-app", "--project-id=group-id"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, createdGroupID, gc.ShouldEqual, "group-id")
+		u.So(t, readInitializedAppConfig(t, filepath.Join(workingDirectory, "my-app")).AppID(), gc.ShouldEqual, "my-app-abcdef")
+	})
+
+	t.Run("should require the user to be logged in to create the app", func(t *testing.T) {
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		exitCode := initCommand.Run([]string{"--name=my-app", "--create-app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, user.ErrNotLoggedIn.Error())
+
+		_, err := os.Stat(filepath.Join(workingDirectory, "my-app"))
+		u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	})
+
+	t.Run("should refuse to run within an existing app directory", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		exitCode := initCommand.Run([]string{"--name=my-app", "--path=" + filepath.Join(appDir, "nested")})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "cannot initialize an app within app directory")
+	})
+
+	t.Run("should refuse to write to a directory that is not empty", func(t *testing.T) {
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		u.So(t, ioutil.WriteFile(filepath.Join(workingDirectory, "notes.txt"), []byte("notes"), 0600), gc.ShouldBeNil)
+
+		exitCode := initCommand.Run([]string{"--name=my-app", "--path=" + workingDirectory})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "already exists and is not empty")
+	})
+
+	t.Run("should fail for template paths that are not app directories", func(t *testing.T) {
+		initCommand, mockUI, workingDirectory := setUpInitCommand(t)
+		defer os.RemoveAll(workingDirectory)
+
+		exitCode := initCommand.Run([]string{"--name=my-app", "--template=../testdata/function_fixtures"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "is not an app directory")
+	})
+}
@@ -0,0 +1,322 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/10gen/stitch-cli/models"
+
+	"github.com/mitchellh/cli"
+)
+
+const (
+	logsFlagType       = "type"
+	logsFlagErrorsOnly = "errors-only"
+	logsFlagSince      = "since"
+	logsFlagFollow     = "follow"
+	logsFlagFormat     = "format"
+
+	logsFormatText = "text"
+	logsFormatJSON = "json"
+
+	logsPageSize            = 100
+	defaultLogsSince        = "1h"
+	defaultLogsPollInterval = 5 * time.Second
+	logsTimeFormat          = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// logTypes maps the values accepted by --type to the log types of the Admin API
+var logTypes = map[string]string{
+	"function": "FUNCTION",
+	"trigger":  "TRIGGER",
+	"webhook":  "WEBHOOK",
+	"auth":     "AUTH",
+}
+
+// NewLogsCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewLogsCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("logs", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &LogsCommand{appCommand: ac, pollInterval: defaultLogsPollInterval}, nil
+	}
+}
+
+// LogsCommand is used to display the logs of a deployed app
+type LogsCommand struct {
+	*appCommand
+
+	pollInterval time.Duration
+	interrupt    chan os.Signal
+
+	flagType       string
+	flagErrorsOnly bool
+	flagSince      string
+	flagFollow     bool
+	flagFormat     string
+}
+
+// Synopsis returns a one-liner description for this command
+func (lc *LogsCommand) Synopsis() string {
+	return `Display the logs of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (lc *LogsCommand) Help() string {
+	return `Display the logs of a deployed app, newest first. Each page of logs is displayed as soon as it is fetched.
+
+Usage: stitch-cli logs [options]
+
+OPTIONS:
+  --type [function|trigger|webhook|auth]
+	Only display logs of the given type.
+
+  --errors-only
+	Only display logs of requests that failed.
+
+  --since [string] (default: 1h)
+	Display logs starting from this long ago (e.g. 30m), or from the given RFC 3339 timestamp.
+
+  --follow
+	Keep polling for new logs and display them as they arrive, until interrupted.
+
+  --format [text|json] (default: text)
+	The output format. With json, each log entry is printed as a JSON object on its own line.
+` +
+		lc.appCommand.Help()
+}
+
+// Run executes the command
+func (lc *LogsCommand) Run(args []string) int {
+	set := lc.NewFlagSet()
+
+	set.StringVar(&lc.flagType, logsFlagType, "", "")
+	set.BoolVar(&lc.flagErrorsOnly, logsFlagErrorsOnly, false, "")
+	set.StringVar(&lc.flagSince, logsFlagSince, defaultLogsSince, "")
+	set.BoolVar(&lc.flagFollow, logsFlagFollow, false, "")
+	set.StringVar(&lc.flagFormat, logsFlagFormat, logsFormatText, "")
+
+	if err := lc.BaseCommand.run(args); err != nil {
+		lc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := lc.logs(); err != nil {
+		lc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (lc *LogsCommand) logs() error {
+	query := models.LogsQuery{ErrorsOnly: lc.flagErrorsOnly, Limit: logsPageSize}
+
+	if lc.flagType != "" {
+		logType, ok := logTypes[lc.flagType]
+		if !ok {
+			return fmt.Errorf("invalid --%s %q: must be one of function, trigger, webhook or auth", logsFlagType, lc.flagType)
+		}
+		query.Type = logType
+	}
+
+	if lc.flagFormat != logsFormatText && lc.flagFormat != logsFormatJSON {
+		return fmt.Errorf("invalid --%s %q: must be either %s or %s", logsFlagFormat, lc.flagFormat, logsFormatText, logsFormatJSON)
+	}
+
+	since, err := parseLogsSince(lc.flagSince, time.Now())
+	if err != nil {
+		return err
+	}
+	query.StartDate = since
+
+	app, err := lc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	// entries started at the same time as the latest one displayed are fetched again by the next poll
+	// of --follow, so the IDs of the latest entries are kept to avoid printing them twice
+	latest, seen := query.StartDate, map[string]time.Time{}
+	display := func(page []*models.LogEntry) error {
+		fresh := make([]*models.LogEntry, 0, len(page))
+		for _, entry := range page {
+			if _, ok := seen[entry.ID]; ok {
+				continue
+			}
+			fresh = append(fresh, entry)
+
+			if entry.Started.After(latest) {
+				latest = entry.Started
+			}
+			if !entry.Started.Before(latest) {
+				seen[entry.ID] = entry.Started
+			}
+		}
+
+		return lc.output(fresh)
+	}
+	pollStart := func() time.Time {
+		for id, started := range seen {
+			if started.Before(latest) {
+				delete(seen, id)
+			}
+		}
+		return latest
+	}
+
+	if err := lc.fetch(app, query, display); err != nil {
+		return err
+	}
+
+	if !lc.flagFollow {
+		return nil
+	}
+
+	return lc.follow(app, query, pollStart, display)
+}
+
+// follow polls for log entries started since the latest one displayed until interrupted, passing each
+// page to display
+func (lc *LogsCommand) follow(app *models.App, query models.LogsQuery, pollStart func() time.Time, display func([]*models.LogEntry) error) error {
+	if lc.interrupt == nil {
+		lc.interrupt = make(chan os.Signal, 1)
+		signal.Notify(lc.interrupt, os.Interrupt)
+		defer signal.Stop(lc.interrupt)
+	}
+
+	ticker := time.NewTicker(lc.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			query.StartDate = pollStart()
+			if err := lc.fetch(app, query, display); err != nil {
+				if _, ok := err.(logsDisplayError); ok {
+					return err
+				}
+				lc.UI.Warn(fmt.Sprintf("failed to fetch logs: %s", err))
+			}
+		case <-lc.interrupt:
+			return nil
+		}
+	}
+}
+
+// logsDisplayError is returned by fetch when a page of log entries cannot be displayed, as opposed to fetched
+type logsDisplayError struct {
+	error
+}
+
+// fetch follows the pagination cursor to retrieve every log entry matching the query, passing each page to
+// display as soon as it arrives rather than holding every entry in memory
+func (lc *LogsCommand) fetch(app *models.App, query models.LogsQuery, display func([]*models.LogEntry) error) error {
+	stitchClient, err := lc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	for {
+		page, err := stitchClient.Logs(app.GroupID, app.ID, query)
+		if err != nil {
+			return err
+		}
+
+		if err := display(page.Logs); err != nil {
+			return logsDisplayError{err}
+		}
+
+		if page.NextEndDate == nil || len(page.Logs) == 0 {
+			return nil
+		}
+		query.EndDate = *page.NextEndDate
+		query.Skip = page.NextSkip
+	}
+}
+
+func (lc *LogsCommand) output(entries []*models.LogEntry) error {
+	for _, entry := range entries {
+		if lc.flagFormat == logsFormatJSON {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			lc.UI.Output(string(data))
+			continue
+		}
+
+		lc.UI.Output(formatLogEntry(entry))
+	}
+
+	return nil
+}
+
+// formatLogEntry renders a log entry as a summary line followed by its indented messages
+func formatLogEntry(entry *models.LogEntry) string {
+	status := "ok"
+	if entry.Error != "" {
+		status = "error: " + entry.Error
+	}
+
+	parts := []string{entry.Started.UTC().Format(logsTimeFormat), entry.Type}
+	if name := logEntryName(entry); name != "" {
+		parts = append(parts, name)
+	}
+	parts = append(parts, fmt.Sprintf("(%s)", entry.Completed.Sub(entry.Started)), status)
+
+	lines := []string{strings.Join(parts, " ")}
+	for _, message := range entry.Messages {
+		text, ok := message.(string)
+		if !ok {
+			data, err := json.Marshal(message)
+			if err != nil {
+				continue
+			}
+			text = string(data)
+		}
+		lines = append(lines, "    "+text)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// logEntryName returns the name of what produced a log entry, depending on its type
+func logEntryName(entry *models.LogEntry) string {
+	switch {
+	case entry.FunctionName != "":
+		return entry.FunctionName
+	case entry.EventSubscriptionName != "":
+		return entry.EventSubscriptionName
+	case entry.WebhookName != "":
+		return entry.WebhookName
+	case entry.AuthProviderType != "":
+		return entry.AuthProviderType
+	case entry.RequestURL != "":
+		return strings.TrimSpace(entry.RequestMethod + " " + entry.RequestURL)
+	}
+
+	return ""
+}
+
+// parseLogsSince parses --since as either a duration before now or an RFC 3339 timestamp
+func parseLogsSince(since string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(since); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q: must be a duration such as 30m or an RFC 3339 timestamp", logsFlagSince, since)
+	}
+
+	return timestamp, nil
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func newMockLogsStitchClient(logsFn func(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error)) *u.MockStitchClient {
+	stitchClient := u.NewMockAppStitchClient()
+	stitchClient.LogsFn = logsFn
+
+	return stitchClient
+}
+
+func newLogEntry(id, functionName string, started time.Time) *models.LogEntry {
+	return &models.LogEntry{
+		ID:           id,
+		Type:         "FUNCTION",
+		FunctionName: functionName,
+		Started:      started,
+		Completed:    started.Add(12 * time.Millisecond),
+	}
+}
+
+func TestLogsCommand(t *testing.T) {
+	started := time.Date(2018, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	t.Run("should follow the pagination cursor and print each page as it arrives", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewLogsCommandFactory)
+		logsCommand := cmd.(*LogsCommand)
+		logsCommand.storage = u.NewEmptyStorage()
+		logsCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		nextEndDate := started
+		var queries []models.LogsQuery
+		logsCommand.stitchClient = newMockLogsStitchClient(func(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error) {
+			queries = append(queries, query)
+			if len(queries) == 1 {
+				entry := newLogEntry("2", "function_b", started.Add(time.Minute))
+				entry.Error = "TypeError: x is undefined"
+				entry.Messages = []interface{}{"computing", map[string]interface{}{"x": 1}}
+				return &models.LogsPage{Logs: []*models.LogEntry{entry}, NextEndDate: &nextEndDate, NextSkip: 1}, nil
+			}
+			u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "function_b")
+			return &models.LogsPage{Logs: []*models.LogEntry{newLogEntry("1", "function_a", started)}}, nil
+		})
+
+		exitCode := logsCommand.Run([]string{"--app-id=my-app-abcdef", "--type=function", "--errors-only", "--since=2018-01-02T00:00:00Z"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `2018-01-02T15:05:05.000Z FUNCTION function_b (12ms) error: TypeError: x is undefined
+    computing
+    {"x":1}
+2018-01-02T15:04:05.000Z FUNCTION function_a (12ms) ok
+`)
+
+		u.So(t, queries, gc.ShouldHaveLength, 2)
+		u.So(t, queries[0].Type, gc.ShouldEqual, "FUNCTION")
+		u.So(t, queries[0].ErrorsOnly, gc.ShouldBeTrue)
+		u.So(t, queries[0].StartDate, gc.ShouldResemble, time.Date(2018, 1, 2, 0, 0, 0, 0, time.UTC))
+		u.So(t, queries[0].EndDate.IsZero(), gc.ShouldBeTrue)
+		u.So(t, queries[1].EndDate, gc.ShouldResemble, nextEndDate)
+		u.So(t, queries[1].Skip, gc.ShouldEqual, 1)
+	})
+
+	t.Run("should print logs as JSON lines", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewLogsCommandFactory)
+		logsCommand := cmd.(*LogsCommand)
+		logsCommand.storage = u.NewEmptyStorage()
+		logsCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		logsCommand.stitchClient = newMockLogsStitchClient(func(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error) {
+			return &models.LogsPage{Logs: []*models.LogEntry{newLogEntry("1", "function_a", started)}}, nil
+		})
+
+		exitCode := logsCommand.Run([]string{"--app-id=my-app-abcdef", "--format=json"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual,
+			`{"_id":"1","type":"FUNCTION","started":"2018-01-02T15:04:05Z","completed":"2018-01-02T15:04:05.012Z","function_name":"function_a"}`+"\n")
+	})
+
+	t.Run("should print new logs once each while following", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewLogsCommandFactory)
+		logsCommand := cmd.(*LogsCommand)
+		logsCommand.storage = u.NewEmptyStorage()
+		logsCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		logsCommand.pollInterval = 10 * time.Millisecond
+		logsCommand.interrupt = make(chan os.Signal, 1)
+
+		var queries []models.LogsQuery
+		logsCommand.stitchClient = newMockLogsStitchClient(func(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error) {
+			queries = append(queries, query)
+			if len(queries) == 1 {
+				return &models.LogsPage{Logs: []*models.LogEntry{newLogEntry("1", "function_a", started)}}, nil
+			}
+
+			select {
+			case logsCommand.interrupt <- os.Interrupt:
+			default:
+			}
+
+			var page models.LogsPage
+			for _, entry := range []*models.LogEntry{
+				newLogEntry("2", "function_b", started.Add(time.Second)),
+				newLogEntry("1", "function_a", started),
+			} {
+				if !entry.Started.Before(query.StartDate) {
+					page.Logs = append(page.Logs, entry)
+				}
+			}
+			return &page, nil
+		})
+
+		exitCode := logsCommand.Run([]string{"--app-id=my-app-abcdef", "--since=2018-01-02T00:00:00Z", "--follow"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `2018-01-02T15:04:05.000Z FUNCTION function_a (12ms) ok
+2018-01-02T15:04:06.000Z FUNCTION function_b (12ms) ok
+`)
+		u.So(t, len(queries), gc.ShouldBeGreaterThanOrEqualTo, 2)
+		u.So(t, queries[1].StartDate, gc.ShouldResemble, started)
+	})
+
+	t.Run("should reject an unknown log type", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewLogsCommandFactory)
+		logsCommand := cmd.(*LogsCommand)
+		logsCommand.storage = u.NewEmptyStorage()
+
+		exitCode := logsCommand.Run([]string{"--app-id=my-app-abcdef", "--type=push"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `invalid --type "push"`)
+	})
+
+	t.Run("should reject an invalid --since", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewLogsCommandFactory)
+		logsCommand := cmd.(*LogsCommand)
+		logsCommand.storage = u.NewEmptyStorage()
+
+		exitCode := logsCommand.Run([]string{"--app-id=my-app-abcdef", "--since=yesterday"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `invalid --since "yesterday"`)
+	})
+}
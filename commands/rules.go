@@ -0,0 +1,409 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	rulesEvalFlagService   = "service"
+	rulesEvalFlagNamespace = "namespace"
+	rulesEvalFlagAction    = "action"
+	rulesEvalFlagUser      = "user"
+	rulesEvalFlagDoc       = "doc"
+	rulesEvalFlagPrevDoc   = "prev-doc"
+	rulesEvalFlagFixtures  = "fixtures"
+)
+
+var (
+	errRulesServiceRequired   = fmt.Errorf("a service name (--%s=[string]) must be supplied", rulesEvalFlagService)
+	errRulesNamespaceRequired = fmt.Errorf("a namespace (--%s=[database.collection]) must be supplied", rulesEvalFlagNamespace)
+	errRulesDocRequired       = fmt.Errorf("a document (--%s=[JSON]) must be supplied", rulesEvalFlagDoc)
+)
+
+// NewRulesLintCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewRulesLintCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("rules lint", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &RulesLintCommand{appCommand: ac}, nil
+	}
+}
+
+// RulesLintCommand is used to check the service rules of a local app directory before importing it
+type RulesLintCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (rlc *RulesLintCommand) Synopsis() string {
+	return `Check the service rules of an app for mistakes.`
+}
+
+// Help returns long-form help information for this command
+func (rlc *RulesLintCommand) Help() string {
+	return `Check the rules within services/*/rules of the local app directory without contacting Stitch.
+
+Namespaces are checked against the type of their service, expressions are checked for unknown
+expansions (e.g. %%usr.id or %true) and malformed operators (e.g. %or without an array), and roles
+that grant write access to every document for every user are flagged as warnings.
+
+The command exits with a non-zero status if any errors are found.
+
+OPTIONS:` +
+		rlc.appCommand.Help()
+}
+
+// Run executes the command
+func (rlc *RulesLintCommand) Run(args []string) int {
+	rlc.NewFlagSet()
+
+	if err := rlc.BaseCommand.run(args); err != nil {
+		rlc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := rlc.lint(); err != nil {
+		rlc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (rlc *RulesLintCommand) lint() error {
+	appPath, err := rlc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	issues, err := utils.LintRules(appPath)
+	if err != nil {
+		return err
+	}
+
+	var errorCount, warningCount int
+	for _, issue := range issues {
+		if path, err := filepath.Rel(appPath, issue.Path); err == nil {
+			issue.Path = path
+		}
+
+		if issue.Severity == utils.RuleIssueError {
+			errorCount++
+			rlc.UI.Error(issue.String())
+		} else {
+			warningCount++
+			rlc.UI.Warn(issue.String())
+		}
+	}
+
+	if errorCount != 0 {
+		return fmt.Errorf("found %d error(s) and %d warning(s) in the rules of %s", errorCount, warningCount, appPath)
+	}
+
+	rlc.UI.Info(fmt.Sprintf("No errors found in the rules of %s (%d warning(s))", appPath, warningCount))
+	return nil
+}
+
+// NewRulesEvalCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewRulesEvalCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("rules eval", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &RulesEvalCommand{appCommand: ac}, nil
+	}
+}
+
+// RulesEvalCommand is used to evaluate the rules of a MongoDB namespace of a local app directory
+// against a user and a document
+type RulesEvalCommand struct {
+	*appCommand
+
+	flagService   string
+	flagNamespace string
+	flagAction    string
+	flagUser      string
+	flagDoc       string
+	flagPrevDoc   string
+	flagFixtures  string
+}
+
+// Synopsis returns a one-liner description for this command
+func (rec *RulesEvalCommand) Synopsis() string {
+	return `Evaluate the rules of a MongoDB namespace against a user and a document.`
+}
+
+// Help returns long-form help information for this command
+func (rec *RulesEvalCommand) Help() string {
+	return `Evaluate the rules of a MongoDB namespace from the local app directory without contacting Stitch,
+and print which role applied and which field permissions allowed or denied the action.
+
+Roles are tried in order and the first one whose apply_when matches decides. Expansions resolve
+against the supplied user (%%user), document (%%root, %%this), previous document (%%prevRoot, %%prev)
+and the values of the app directory (%%values). Functions called with %function run locally, as with
+"functions test".
+
+Usage: stitch-cli rules eval --service [string] --namespace [string] --doc [JSON] [options]
+
+OPTIONS:
+  --service [string]
+	The name of the MongoDB service whose rules to evaluate.
+
+  --namespace [string]
+	The namespace to evaluate, as database.collection.
+
+  --action [string] (default: read)
+	The action to evaluate, one of read, write, insert or delete.
+
+  --user [JSON]
+	The user performing the action, e.g. '{"id": "5a1f...", "data": {"email": "..."}}'. Prefix with @ to read
+	it from a file.
+
+  --doc [JSON]
+	The document being accessed; for writes, the document as it would be after the write. Prefix with @ to
+	read it from a file.
+
+  --prev-doc [JSON]
+	The document before a write. Prefix with @ to read it from a file.
+
+  --fixtures [string]
+	A path to a JSON file backing context.services for functions called by the rules, as with "functions test".
+` +
+		rec.appCommand.Help()
+}
+
+// Run executes the command
+func (rec *RulesEvalCommand) Run(args []string) int {
+	set := rec.NewFlagSet()
+
+	set.StringVar(&rec.flagService, rulesEvalFlagService, "", "")
+	set.StringVar(&rec.flagNamespace, rulesEvalFlagNamespace, "", "")
+	set.StringVar(&rec.flagAction, rulesEvalFlagAction, utils.RuleActionRead, "")
+	set.StringVar(&rec.flagUser, rulesEvalFlagUser, "", "")
+	set.StringVar(&rec.flagDoc, rulesEvalFlagDoc, "", "")
+	set.StringVar(&rec.flagPrevDoc, rulesEvalFlagPrevDoc, "", "")
+	set.StringVar(&rec.flagFixtures, rulesEvalFlagFixtures, "", "")
+
+	if err := rec.BaseCommand.run(args); err != nil {
+		rec.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := rec.eval(); err != nil {
+		rec.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (rec *RulesEvalCommand) eval() error {
+	if rec.flagService == "" {
+		return errRulesServiceRequired
+	}
+	if rec.flagNamespace == "" {
+		return errRulesNamespaceRequired
+	}
+	if rec.flagDoc == "" {
+		return errRulesDocRequired
+	}
+
+	request := utils.RuleRequest{Action: rec.flagAction}
+
+	var err error
+	if request.Doc, err = readRulesDocument(rulesEvalFlagDoc, rec.flagDoc); err != nil {
+		return err
+	}
+	if request.User, err = readRulesDocument(rulesEvalFlagUser, rec.flagUser); err != nil {
+		return err
+	}
+	if request.PrevDoc, err = readRulesDocument(rulesEvalFlagPrevDoc, rec.flagPrevDoc); err != nil {
+		return err
+	}
+
+	appPath, err := rec.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	service, err := utils.ReadLocalService(appPath, rec.flagService)
+	if err != nil {
+		return err
+	}
+
+	rule, err := utils.FindNamespaceRule(service, rec.flagNamespace)
+	if err != nil {
+		return err
+	}
+
+	values, err := utils.ReadLocalValues(appPath)
+	if err != nil {
+		return err
+	}
+
+	request.Values = map[string]interface{}{}
+	for _, value := range values {
+		request.Values[value.Name()] = value.Value()
+	}
+
+	var runner *utils.FunctionRunner
+	request.CallFunction = func(name string, args []interface{}) (interface{}, error) {
+		if runner == nil {
+			if runner, err = rec.newFunctionRunner(appPath); err != nil {
+				return nil, err
+			}
+		}
+		return callRuleFunction(runner, name, args)
+	}
+
+	evaluation, err := utils.EvaluateRule(rule, request)
+	if err != nil {
+		return err
+	}
+
+	rec.output(evaluation)
+	return nil
+}
+
+func (rec *RulesEvalCommand) newFunctionRunner(appPath string) (*utils.FunctionRunner, error) {
+	fixtures := map[string]interface{}{}
+	if rec.flagFixtures != "" {
+		path, err := homedir.Expand(rec.flagFixtures)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := utils.ReadAndUnmarshalInto(json.Unmarshal, path, &fixtures); err != nil {
+			return nil, err
+		}
+	}
+
+	app, err := utils.UnmarshalFromDir(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.NewFunctionRunner(app, fixtures)
+}
+
+func (rec *RulesEvalCommand) output(evaluation *utils.RuleEvaluation) {
+	if evaluation.Role == "" {
+		rec.UI.Output(fmt.Sprintf("No role of %s applies to this user and document: %s denied", rec.flagNamespace, rec.flagAction))
+		return
+	}
+
+	lines := []string{
+		fmt.Sprintf("Role: %s", evaluation.Role),
+		fmt.Sprintf("Action: %s (%s)", rec.flagAction, evaluation.Source),
+	}
+
+	if len(evaluation.Fields) != 0 {
+		var buf bytes.Buffer
+		w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "FIELD\tRESULT\tPERMISSION")
+		for _, field := range evaluation.Fields {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", field.Field, allowedStatus(field.Allowed), field.Source)
+		}
+		w.Flush()
+
+		lines = append(lines, strings.TrimSuffix(buf.String(), "\n"))
+	}
+
+	lines = append(lines, fmt.Sprintf("Result: %s", rulesEvalResult(rec.flagAction, evaluation)))
+	rec.UI.Output(strings.Join(lines, "\n"))
+}
+
+// rulesEvalResult summarizes an evaluation. Reads of documents with some denied fields still succeed
+// with those fields omitted, while writes are rejected as a whole
+func rulesEvalResult(action string, evaluation *utils.RuleEvaluation) string {
+	if evaluation.Allowed || action != utils.RuleActionRead {
+		return allowedStatus(evaluation.Allowed)
+	}
+
+	var allowedCount int
+	for _, field := range evaluation.Fields {
+		if field.Allowed {
+			allowedCount++
+		}
+	}
+
+	if allowedCount == 0 {
+		return allowedStatus(false)
+	}
+
+	return fmt.Sprintf("partially allowed (%d of %d field(s))", allowedCount, len(evaluation.Fields))
+}
+
+func allowedStatus(allowed bool) string {
+	if allowed {
+		return "allowed"
+	}
+	return "denied"
+}
+
+// readRulesDocument parses the JSON document supplied to a flag, returning nil if it is empty
+func readRulesDocument(flag, value string) (map[string]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(value, "@") {
+		path, err := homedir.Expand(value[1:])
+		if err != nil {
+			return nil, err
+		}
+		value = "@" + path
+	}
+
+	doc, err := utils.ReadJSONDocument(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse --%s: %s", flag, err)
+	}
+
+	return doc, nil
+}
+
+// callRuleFunction runs a function called by a rule and decodes its result
+func callRuleFunction(runner *utils.FunctionRunner, name string, args []interface{}) (interface{}, error) {
+	functionArgs := make([]json.RawMessage, 0, len(args))
+	for _, arg := range args {
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return nil, err
+		}
+		functionArgs = append(functionArgs, data)
+	}
+
+	result, err := runner.Run(name, functionArgs, defaultFunctionTestTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run function %q: %s", name, err)
+	}
+
+	if len(result.Result) == 0 {
+		return nil, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(result.Result, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"testing"
+
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestRulesLintCommand(t *testing.T) {
+	t.Run("should pass rules without errors", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewRulesLintCommandFactory)
+		lintCommand := cmd.(*RulesLintCommand)
+		lintCommand.storage = u.NewEmptyStorage()
+
+		exitCode := lintCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "No errors found in the rules of ../testdata/full_app (0 warning(s))")
+	})
+
+	t.Run("should report errors and warnings relative to the app directory", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewRulesLintCommandFactory)
+		lintCommand := cmd.(*RulesLintCommand)
+		lintCommand.storage = u.NewEmptyStorage()
+
+		exitCode := lintCommand.Run([]string{"--path=../testdata/rules_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldEqual, `services/http/rules/github.json: actions[1]: error: unknown action "fetch" for http services
+services/http/rules/github.json: when: error: unknown expansion "%%requests.ip"
+services/mongodb-atlas/rules/blog.comments.json: roles[0]: warning: role "everyone" grants write access to every document of blog.comments to every user
+services/mongodb-atlas/rules/blog.comments.json: roles[1].apply_when: error: unknown field "role" of %%user in "%%user.role", must be one of id, type, data, identities or custom_data
+services/mongodb-atlas/rules/blog.comments.json: roles[1].read: error: "%true" is not an expansion, did you mean "%%true"?
+services/mongodb-atlas/rules/blog.comments.json: roles[1].write.%and: error: %and expects a non-empty array of expressions
+services/mongodb-atlas/rules/copy.json: error: namespace blog.posts is also defined in blog.posts.json
+found 6 error(s) and 1 warning(s) in the rules of ../testdata/rules_app
+`)
+	})
+}
+
+func TestRulesEvalCommand(t *testing.T) {
+	run := func(args ...string) (int, *cli.MockUi) {
+		cmd, mockUI := setUpAppCommand(NewRulesEvalCommandFactory)
+		evalCommand := cmd.(*RulesEvalCommand)
+		evalCommand.storage = u.NewEmptyStorage()
+
+		args = append([]string{"--path=../testdata/rules_app", "--service=mongodb-atlas", "--namespace=blog.posts"}, args...)
+		return evalCommand.Run(args), mockUI
+	}
+
+	t.Run("should require a service, namespace and document", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewRulesEvalCommandFactory)
+		evalCommand := cmd.(*RulesEvalCommand)
+		evalCommand.storage = u.NewEmptyStorage()
+
+		exitCode := evalCommand.Run([]string{"--path=../testdata/rules_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errRulesServiceRequired.Error())
+
+		exitCode, mockUI = run()
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errRulesDocRequired.Error())
+	})
+
+	t.Run("should print the field permissions of the role that applies", func(t *testing.T) {
+		exitCode, mockUI := run(`--user={"id": "u1", "data": {"role": "viewer"}}`, `--doc={"owner_id": "u1", "title": "Hello", "internal_notes": "draft"}`)
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `Role: owner
+Action: read (field permissions)
+FIELD           RESULT   PERMISSION
+internal_notes  denied   fields.internal_notes
+owner_id        allowed  additional_fields
+title           allowed  fields.title
+Result: partially allowed (2 of 3 field(s))
+`)
+	})
+
+	t.Run("should evaluate document-level permissions", func(t *testing.T) {
+		exitCode, mockUI := run("--action=write", `--user={"id": "u2"}`, `--doc={"owner_id": "u1", "published": true}`)
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `Role: reader
+Action: write (field permissions)
+FIELD      RESULT  PERMISSION
+owner_id   denied  no permission
+published  denied  no permission
+Result: denied
+`)
+
+		exitCode, mockUI = run("--action=delete", `--user={"id": "u1"}`, `--doc={"owner_id": "u1"}`)
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "Role: owner\nAction: delete (delete)\nResult: allowed\n")
+	})
+
+	t.Run("should fail for invalid documents and unknown namespaces", func(t *testing.T) {
+		exitCode, mockUI := run(`--doc={"owner_id"`)
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "failed to parse --doc")
+
+		exitCode, mockUI = run("--namespace=blog.drafts", `--doc={}`)
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "no rule found for namespace blog.drafts")
+	})
+}
@@ -0,0 +1,357 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+const secretsFlagStdin = "stdin"
+
+var (
+	errSecretNameRequired  = errors.New("a secret name must be supplied")
+	errSecretValueRequired = errors.New("the value of a secret must not be empty")
+)
+
+// NewSecretsListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewSecretsListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("secrets list", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &SecretsListCommand{appCommand: ac}, nil
+	}
+}
+
+// SecretsListCommand is used to list the secrets of a deployed app along with what references them
+type SecretsListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (slc *SecretsListCommand) Synopsis() string {
+	return `List the secrets of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (slc *SecretsListCommand) Help() string {
+	return `List the names of the secrets of a deployed app and the services and auth providers that reference them.
+Secret values are never displayed.
+
+OPTIONS:` +
+		slc.appCommand.Help()
+}
+
+// Run executes the command
+func (slc *SecretsListCommand) Run(args []string) int {
+	slc.NewFlagSet()
+
+	if err := slc.BaseCommand.run(args); err != nil {
+		slc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := slc.list(); err != nil {
+		slc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (slc *SecretsListCommand) list() error {
+	app, err := slc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := slc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := stitchClient.Secrets(app.GroupID, app.ID)
+	if err != nil {
+		return err
+	}
+
+	references, err := fetchSecretReferences(slc.BaseCommand, app)
+	if err != nil {
+		slc.UI.Warn(fmt.Sprintf("failed to determine which services and auth providers use each secret: %s", err))
+	}
+
+	for _, secret := range secrets {
+		if references == nil {
+			slc.UI.Info(secret.Name)
+			continue
+		}
+
+		refs := references[secret.Name]
+		if len(refs) == 0 {
+			slc.UI.Info(fmt.Sprintf("%s (unused)", secret.Name))
+			continue
+		}
+
+		slc.UI.Info(fmt.Sprintf("%s (used by %s)", secret.Name, strings.Join(refs, ", ")))
+	}
+
+	return nil
+}
+
+// NewSecretsSetCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewSecretsSetCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("secrets set", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &SecretsSetCommand{appCommand: ac, stdin: os.Stdin}, nil
+	}
+}
+
+// SecretsSetCommand is used to create or update a secret of a deployed app
+type SecretsSetCommand struct {
+	*appCommand
+
+	stdin io.Reader
+
+	flagStdin bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (ssc *SecretsSetCommand) Synopsis() string {
+	return `Create or update a secret of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (ssc *SecretsSetCommand) Help() string {
+	return `Create or update a secret of a deployed app. The value is prompted for without being echoed,
+or read from standard input with --stdin, and is never written to disk.
+
+Usage: stitch-cli secrets set <name> [options]
+
+OPTIONS:
+  --stdin
+	Read the value of the secret from standard input instead of prompting for it. A single trailing newline is removed.
+` +
+		ssc.appCommand.Help()
+}
+
+// Run executes the command
+func (ssc *SecretsSetCommand) Run(args []string) int {
+	set := ssc.NewFlagSet()
+
+	set.BoolVar(&ssc.flagStdin, secretsFlagStdin, false, "")
+
+	if err := ssc.BaseCommand.run(args); err != nil {
+		ssc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := ssc.set(); err != nil {
+		ssc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (ssc *SecretsSetCommand) set() error {
+	if err := ssc.checkPositionalArgs(1, 1, errSecretNameRequired); err != nil {
+		return err
+	}
+	name := ssc.positionalArgs[0]
+
+	app, err := ssc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	value, err := ssc.readValue(name)
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := ssc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := stitchClient.Secrets(app.GroupID, app.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		if secret.Name != name {
+			continue
+		}
+
+		if err := stitchClient.UpdateSecret(app.GroupID, app.ID, &models.Secret{ID: secret.ID, Name: name, Value: value}); err != nil {
+			return err
+		}
+
+		ssc.UI.Info(fmt.Sprintf("Updated secret '%s' in '%s'", name, app.ClientAppID))
+		return nil
+	}
+
+	if _, err := stitchClient.CreateSecret(app.GroupID, app.ID, &models.Secret{Name: name, Value: value}); err != nil {
+		return err
+	}
+
+	ssc.UI.Info(fmt.Sprintf("Created secret '%s' in '%s'", name, app.ClientAppID))
+	return nil
+}
+
+func (ssc *SecretsSetCommand) readValue(name string) (string, error) {
+	var value string
+	if ssc.flagStdin {
+		data, err := ioutil.ReadAll(ssc.stdin)
+		if err != nil {
+			return "", err
+		}
+
+		value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	} else {
+		answer, err := ssc.UI.AskSecret(fmt.Sprintf("Value for secret '%s':", name))
+		if err != nil {
+			return "", err
+		}
+
+		value = answer
+	}
+
+	if value == "" {
+		return "", errSecretValueRequired
+	}
+
+	return value, nil
+}
+
+// NewSecretsRemoveCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewSecretsRemoveCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("secrets rm", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &SecretsRemoveCommand{appCommand: ac}, nil
+	}
+}
+
+// SecretsRemoveCommand is used to delete a secret of a deployed app
+type SecretsRemoveCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (src *SecretsRemoveCommand) Synopsis() string {
+	return `Delete a secret of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (src *SecretsRemoveCommand) Help() string {
+	return `Delete a secret of a deployed app.
+
+Usage: stitch-cli secrets rm <name> [options]
+
+OPTIONS:` +
+		src.appCommand.Help()
+}
+
+// Run executes the command
+func (src *SecretsRemoveCommand) Run(args []string) int {
+	src.NewFlagSet()
+
+	if err := src.BaseCommand.run(args); err != nil {
+		src.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := src.remove(); err != nil {
+		src.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (src *SecretsRemoveCommand) remove() error {
+	if err := src.checkPositionalArgs(1, 1, errSecretNameRequired); err != nil {
+		return err
+	}
+	name := src.positionalArgs[0]
+
+	app, err := src.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := src.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := stitchClient.Secrets(app.GroupID, app.ID)
+	if err != nil {
+		return err
+	}
+
+	var secret *models.Secret
+	for _, s := range secrets {
+		if s.Name == name {
+			secret = s
+		}
+	}
+
+	if secret == nil {
+		return fmt.Errorf("secret %q not found in '%s'", name, app.ClientAppID)
+	}
+
+	if references, err := fetchSecretReferences(src.BaseCommand, app); err == nil && len(references[name]) != 0 {
+		src.UI.Warn(fmt.Sprintf("Secret '%s' is used by %s", name, strings.Join(references[name], ", ")))
+	}
+
+	confirm, err := src.AskYesNo(fmt.Sprintf("Delete secret '%s' from '%s'?", name, app.ClientAppID))
+	if err != nil || !confirm {
+		return err
+	}
+
+	if err := stitchClient.DeleteSecret(app.GroupID, app.ID, secret.ID); err != nil {
+		return err
+	}
+
+	src.UI.Info(fmt.Sprintf("Deleted secret '%s' from '%s'", name, app.ClientAppID))
+	return nil
+}
+
+// fetchSecretReferences exports the deployed app to find which services and auth providers reference each secret
+func fetchSecretReferences(c *BaseCommand, app *models.App) (map[string][]string, error) {
+	stitchClient, err := c.StitchClient()
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := stitchClient.Export(app.GroupID, app.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return utils.SecretReferences(body)
+}
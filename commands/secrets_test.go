@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func newMockSecretsStitchClient(secrets []*models.Secret) *u.MockStitchClient {
+	stitchClient := u.NewMockAppStitchClient()
+	stitchClient.SecretsFn = func(groupID, appID string) ([]*models.Secret, error) {
+		return secrets, nil
+	}
+	stitchClient.ExportFn = func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+		exported := u.NewZip(map[string]string{
+			"services/svc/config.json":            `{"name": "svc", "type": "twilio", "secret_config": {"auth_token": "twilio_token"}}`,
+			"auth_providers/oauth2-google.json":   `{"name": "oauth2-google", "secret_config": {"clientSecret": "google_secret"}}`,
+			"auth_providers/oauth2-facebook.json": `{"name": "oauth2-facebook", "secret_config": {"clientSecret": "google_secret"}}`,
+		})
+		return "", u.NewResponseBody(bytes.NewReader(exported)), nil
+	}
+
+	return stitchClient
+}
+
+func TestSecretsListCommand(t *testing.T) {
+	t.Run("should list secrets and what references them", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsListCommandFactory)
+		listCommand := cmd.(*SecretsListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+		listCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		listCommand.stitchClient = newMockSecretsStitchClient([]*models.Secret{
+			{ID: "1", Name: "google_secret"},
+			{ID: "2", Name: "twilio_token"},
+			{ID: "3", Name: "leftover"},
+		})
+
+		exitCode := listCommand.Run([]string{"--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, strings.Join([]string{
+			"google_secret (used by auth provider 'oauth2-facebook' (clientSecret), auth provider 'oauth2-google' (clientSecret))",
+			"twilio_token (used by service 'svc' (auth_token))",
+			"leftover (unused)",
+			"",
+		}, "\n"))
+	})
+
+	t.Run("should still list secret names if the app cannot be exported", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsListCommandFactory)
+		listCommand := cmd.(*SecretsListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+		listCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		stitchClient := newMockSecretsStitchClient([]*models.Secret{{ID: "1", Name: "twilio_token"}})
+		stitchClient.ExportFn = func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
+			return "", nil, errors.New("oh noes")
+		}
+		listCommand.stitchClient = stitchClient
+
+		exitCode := listCommand.Run([]string{"--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "oh noes")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "twilio_token\n")
+	})
+}
+
+func TestSecretsSetCommand(t *testing.T) {
+	t.Run("should require a secret name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsSetCommandFactory)
+		setCommand := cmd.(*SecretsSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+
+		exitCode := setCommand.Run([]string{"--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errSecretNameRequired.Error())
+	})
+
+	t.Run("should create a secret with a prompted value without echoing it", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsSetCommandFactory)
+		setCommand := cmd.(*SecretsSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+		setCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		mockUI.InputReader = strings.NewReader("sup3r-s3cret\n")
+
+		var created *models.Secret
+		stitchClient := newMockSecretsStitchClient([]*models.Secret{})
+		stitchClient.CreateSecretFn = func(groupID, appID string, secret *models.Secret) (*models.Secret, error) {
+			created = secret
+			return &models.Secret{ID: "secret-id", Name: secret.Name}, nil
+		}
+		setCommand.stitchClient = stitchClient
+
+		exitCode := setCommand.Run([]string{"api_key", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, created, gc.ShouldResemble, &models.Secret{Name: "api_key", Value: "sup3r-s3cret"})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldNotContainSubstring, "sup3r-s3cret")
+	})
+
+	t.Run("should update a secret with a value read from stdin", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsSetCommandFactory)
+		setCommand := cmd.(*SecretsSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+		setCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		setCommand.stdin = strings.NewReader("from-stdin\n")
+
+		var updated *models.Secret
+		stitchClient := newMockSecretsStitchClient([]*models.Secret{{ID: "secret-id", Name: "api_key"}})
+		stitchClient.UpdateSecretFn = func(groupID, appID string, secret *models.Secret) error {
+			updated = secret
+			return nil
+		}
+		setCommand.stitchClient = stitchClient
+
+		exitCode := setCommand.Run([]string{"api_key", "--app-id=my-app-abcdef", "--stdin"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, updated, gc.ShouldResemble, &models.Secret{ID: "secret-id", Name: "api_key", Value: "from-stdin"})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "Updated secret 'api_key' in 'my-app-abcdef'\n")
+	})
+
+	t.Run("should reject an empty value", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsSetCommandFactory)
+		setCommand := cmd.(*SecretsSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+		setCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		setCommand.stdin = strings.NewReader("\n")
+		setCommand.stitchClient = newMockSecretsStitchClient([]*models.Secret{})
+
+		exitCode := setCommand.Run([]string{"api_key", "--app-id=my-app-abcdef", "--stdin"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errSecretValueRequired.Error())
+	})
+}
+
+func TestSecretsRemoveCommand(t *testing.T) {
+	t.Run("should warn about references and delete the secret once confirmed", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsRemoveCommandFactory)
+		removeCommand := cmd.(*SecretsRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		removeCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		mockUI.InputReader = strings.NewReader("y\n")
+
+		var deletedID string
+		stitchClient := newMockSecretsStitchClient([]*models.Secret{{ID: "secret-id", Name: "twilio_token"}})
+		stitchClient.DeleteSecretFn = func(groupID, appID, secretID string) error {
+			deletedID = secretID
+			return nil
+		}
+		removeCommand.stitchClient = stitchClient
+
+		exitCode := removeCommand.Run([]string{"twilio_token", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "Secret 'twilio_token' is used by service 'svc' (auth_token)")
+		u.So(t, deletedID, gc.ShouldEqual, "secret-id")
+	})
+
+	t.Run("should fail for an unknown secret", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewSecretsRemoveCommandFactory)
+		removeCommand := cmd.(*SecretsRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		removeCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		removeCommand.stitchClient = newMockSecretsStitchClient([]*models.Secret{})
+
+		exitCode := removeCommand.Run([]string{"nope", "--app-id=my-app-abcdef", "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `secret "nope" not found in 'my-app-abcdef'`)
+	})
+}
@@ -0,0 +1,324 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+// servicesSecretsSection is the section of secrets.json holding the secrets of services
+const servicesSecretsSection = "services"
+
+var (
+	errServiceNameRequired = errors.New("a service name must be supplied")
+	errServiceTypeRequired = fmt.Errorf("a service type and name must be supplied, the type being one of: %s", strings.Join(utils.ServiceTypes(), ", "))
+)
+
+// NewServicesListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewServicesListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("services list", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &ServicesListCommand{appCommand: ac}, nil
+	}
+}
+
+// ServicesListCommand is used to list the services of a local app directory
+type ServicesListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (slc *ServicesListCommand) Synopsis() string {
+	return `List the services of an app.`
+}
+
+// Help returns long-form help information for this command
+func (slc *ServicesListCommand) Help() string {
+	return `List the services of the local app directory along with their type and how many rules and
+incoming webhooks they have.
+
+OPTIONS:` +
+		slc.appCommand.Help()
+}
+
+// Run executes the command
+func (slc *ServicesListCommand) Run(args []string) int {
+	slc.NewFlagSet()
+
+	if err := slc.BaseCommand.run(args); err != nil {
+		slc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := slc.list(); err != nil {
+		slc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (slc *ServicesListCommand) list() error {
+	appPath, err := slc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	services, err := utils.ReadLocalServices(appPath)
+	if err != nil {
+		return err
+	}
+
+	if len(services) == 0 {
+		slc.UI.Info("No services found")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tRULES\tWEBHOOKS")
+	for _, service := range services {
+		ruleFiles, err := service.RuleFiles()
+		if err != nil {
+			return err
+		}
+
+		webhooks, err := service.Webhooks()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", service.Name(), service.Type(), len(ruleFiles), len(webhooks))
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	slc.UI.Output(strings.TrimSuffix(buf.String(), "\n"))
+
+	return nil
+}
+
+// NewServicesAddCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewServicesAddCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("services add", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &ServicesAddCommand{appCommand: ac}, nil
+	}
+}
+
+// ServicesAddCommand is used to scaffold a new service in a local app directory
+type ServicesAddCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (sac *ServicesAddCommand) Synopsis() string {
+	return `Add a service to an app.`
+}
+
+// Help returns long-form help information for this command
+func (sac *ServicesAddCommand) Help() string {
+	return `Add a service of the given type to the local app directory, prompting for its settings.
+
+The service's config is written to services/<name>/config.json alongside empty rules and
+incoming_webhooks directories. Secrets such as API keys are written to secrets.json rather than to the
+service's config, so they can be kept out of version control.
+
+Usage: stitch-cli services add <type> <name> [options]
+
+Supported types: ` + strings.Join(utils.ServiceTypes(), ", ") + `
+
+OPTIONS:` +
+		sac.appCommand.Help()
+}
+
+// Run executes the command
+func (sac *ServicesAddCommand) Run(args []string) int {
+	sac.NewFlagSet()
+
+	if err := sac.BaseCommand.run(args); err != nil {
+		sac.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := sac.add(); err != nil {
+		sac.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (sac *ServicesAddCommand) add() error {
+	if err := sac.checkPositionalArgs(2, 2, errServiceTypeRequired); err != nil {
+		return err
+	}
+	serviceType, name := sac.positionalArgs[0], sac.positionalArgs[1]
+
+	template, ok := utils.ServiceTemplates[serviceType]
+	if !ok {
+		return fmt.Errorf("unsupported service type %q, must be one of: %s", serviceType, strings.Join(utils.ServiceTypes(), ", "))
+	}
+
+	appPath, err := sac.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	if _, err := utils.ReadLocalService(appPath, name); err == nil {
+		return fmt.Errorf("a service named %q already exists in %s", name, utils.ServicesDirectory(appPath))
+	}
+
+	fields := map[string]string{}
+	for _, field := range template.Fields {
+		value, err := sac.Ask(fmt.Sprintf("%s (%s)", field.Description, field.Name), field.Default)
+		if err != nil {
+			return err
+		}
+		fields[field.Name] = value
+	}
+
+	secrets := map[string]string{}
+	for _, field := range template.SecretFields {
+		value, err := sac.UI.AskSecret(fmt.Sprintf("%s (%s):", field.Description, field.Name))
+		if err != nil {
+			return err
+		}
+		secrets[field.Name] = value
+	}
+
+	service, err := template.NewLocalService(appPath, name, fields)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(service.Dir); err == nil {
+		return fmt.Errorf("%s already exists", service.Dir)
+	}
+
+	if err := utils.WriteLocalService(service); err != nil {
+		return err
+	}
+
+	sac.UI.Info(fmt.Sprintf("Added %s service '%s' in %s", service.Type(), service.Name(), service.Dir))
+
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	if err := utils.WriteLocalSecrets(appPath, servicesSecretsSection, service.Name(), secrets); err != nil {
+		return err
+	}
+
+	sac.UI.Info(fmt.Sprintf("Stored the secrets of service '%s' in %s", service.Name(), utils.SecretsFilePath(appPath)))
+	return nil
+}
+
+// NewServicesRemoveCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewServicesRemoveCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("services rm", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &ServicesRemoveCommand{appCommand: ac}, nil
+	}
+}
+
+// ServicesRemoveCommand is used to remove a service from a local app directory
+type ServicesRemoveCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (src *ServicesRemoveCommand) Synopsis() string {
+	return `Remove a service from an app.`
+}
+
+// Help returns long-form help information for this command
+func (src *ServicesRemoveCommand) Help() string {
+	return `Remove a service from the local app directory along with its rules, incoming webhooks and secrets.
+The service is deleted from Stitch on the next import in replace mode.
+
+Usage: stitch-cli services rm <name> [options]
+
+OPTIONS:` +
+		src.appCommand.Help()
+}
+
+// Run executes the command
+func (src *ServicesRemoveCommand) Run(args []string) int {
+	src.NewFlagSet()
+
+	if err := src.BaseCommand.run(args); err != nil {
+		src.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := src.remove(); err != nil {
+		src.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (src *ServicesRemoveCommand) remove() error {
+	if err := src.checkPositionalArgs(1, 1, errServiceNameRequired); err != nil {
+		return err
+	}
+	name := src.positionalArgs[0]
+
+	appPath, err := src.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	service, err := utils.ReadLocalService(appPath, name)
+	if err != nil {
+		return err
+	}
+
+	relPath, err := filepath.Rel(appPath, service.Dir)
+	if err != nil {
+		relPath = service.Dir
+	}
+
+	confirm, err := src.AskYesNo(fmt.Sprintf("Remove service '%s' and everything within %s?", name, relPath))
+	if err != nil || !confirm {
+		return err
+	}
+
+	if err := utils.RemoveLocalService(service); err != nil {
+		return err
+	}
+
+	if err := utils.RemoveLocalSecrets(appPath, servicesSecretsSection, name); err != nil {
+		return err
+	}
+
+	src.UI.Info(fmt.Sprintf("Removed service '%s'", name))
+	return nil
+}
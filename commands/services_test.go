@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServicesListCommand(t *testing.T) {
+	t.Run("should list local services", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewServicesListCommandFactory)
+		listCommand := cmd.(*ServicesListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/webhooks_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `NAME    TYPE    RULES  WEBHOOKS
+github  github  0      1
+http    http    0      3
+`)
+	})
+
+	t.Run("should report when there are no services", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewServicesListCommandFactory)
+		listCommand := cmd.(*ServicesListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "No services found\n")
+	})
+}
+
+func TestServicesAddCommand(t *testing.T) {
+	t.Run("should require a type and a name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewServicesAddCommandFactory)
+		addCommand := cmd.(*ServicesAddCommand)
+		addCommand.storage = u.NewEmptyStorage()
+
+		exitCode := addCommand.Run([]string{"twilio", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errServiceTypeRequired.Error())
+	})
+
+	t.Run("should reject unsupported types, invalid names and existing services", func(t *testing.T) {
+		for _, tc := range []struct {
+			args     []string
+			expected string
+		}{
+			{[]string{"fax", "my-fax"}, `unsupported service type "fax"`},
+			{[]string{"http", "my service"}, `invalid service name "my service"`},
+			{[]string{"http", "service b"}, `a service named "service b" already exists`},
+		} {
+			cmd, mockUI := setUpAppCommand(NewServicesAddCommandFactory)
+			addCommand := cmd.(*ServicesAddCommand)
+			addCommand.storage = u.NewEmptyStorage()
+
+			exitCode := addCommand.Run(append(tc.args, "--path=../testdata/full_app"))
+			u.So(t, exitCode, gc.ShouldEqual, 1)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, tc.expected)
+		}
+	})
+
+	t.Run("should scaffold a service and store its secrets in secrets.json", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewServicesAddCommandFactory)
+		addCommand := cmd.(*ServicesAddCommand)
+		addCommand.storage = u.NewEmptyStorage()
+		mockUI.InputReader = strings.NewReader("AKIAEXAMPLE\nsuper-secret\n")
+
+		exitCode := addCommand.Run([]string{"aws", "my-aws", "--path=" + appDir, "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		service, err := utils.ReadLocalService(appDir, "my-aws")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, service.Dir, gc.ShouldEqual, filepath.Join(appDir, "services", "my-aws"))
+		u.So(t, service.Type(), gc.ShouldEqual, "aws")
+		u.So(t, service.Config["config"], gc.ShouldResemble, map[string]interface{}{"accessKeyId": "AKIAEXAMPLE", "region": "us-east-1"})
+
+		for _, dir := range []string{"rules", "incoming_webhooks"} {
+			info, err := os.Stat(filepath.Join(service.Dir, dir))
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, info.IsDir(), gc.ShouldBeTrue)
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(appDir, "secrets.json"))
+		u.So(t, err, gc.ShouldBeNil)
+
+		var secrets map[string]interface{}
+		u.So(t, json.Unmarshal(data, &secrets), gc.ShouldBeNil)
+		u.So(t, secrets, gc.ShouldResemble, map[string]interface{}{
+			"services": map[string]interface{}{"my-aws": map[string]interface{}{"secretAccessKey": "super-secret"}},
+		})
+
+		app, err := utils.UnmarshalFromDir(appDir)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app["services"], gc.ShouldHaveLength, 1)
+	})
+}
+
+func TestServicesRemoveCommand(t *testing.T) {
+	t.Run("should require a service name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewServicesRemoveCommandFactory)
+		removeCommand := cmd.(*ServicesRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+
+		exitCode := removeCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errServiceNameRequired.Error())
+	})
+
+	t.Run("should remove a service and its secrets", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		template := utils.ServiceTemplates["twilio"]
+		for _, name := range []string{"sms", "calls"} {
+			service, err := template.NewLocalService(appDir, name, map[string]string{"sid": "AC123"})
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, utils.WriteLocalService(service), gc.ShouldBeNil)
+			u.So(t, utils.WriteLocalSecrets(appDir, servicesSecretsSection, name, map[string]string{"auth_token": "token"}), gc.ShouldBeNil)
+		}
+
+		cmd, mockUI := setUpAppCommand(NewServicesRemoveCommandFactory)
+		removeCommand := cmd.(*ServicesRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		mockUI.InputReader = strings.NewReader("y\n")
+
+		exitCode := removeCommand.Run([]string{"sms", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Removed service 'sms'")
+
+		_, err := os.Stat(filepath.Join(appDir, "services", "sms"))
+		u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+
+		services, err := utils.ReadLocalServices(appDir)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, services, gc.ShouldHaveLength, 1)
+
+		data, err := ioutil.ReadFile(filepath.Join(appDir, "secrets.json"))
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(data), gc.ShouldNotContainSubstring, `"sms"`)
+		u.So(t, string(data), gc.ShouldContainSubstring, `"calls"`)
+	})
+
+	t.Run("should keep the service when removal is not confirmed", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		service, err := utils.ServiceTemplates["http"].NewLocalService(appDir, "api", nil)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, utils.WriteLocalService(service), gc.ShouldBeNil)
+
+		cmd, mockUI := setUpAppCommand(NewServicesRemoveCommandFactory)
+		removeCommand := cmd.(*ServicesRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		mockUI.InputReader = strings.NewReader("n\n")
+
+		exitCode := removeCommand.Run([]string{"api", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+
+		_, err = os.Stat(service.ConfigPath())
+		u.So(t, err, gc.ShouldBeNil)
+	})
+}
@@ -0,0 +1,367 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+var errTriggerNameRequired = errors.New("a trigger name must be supplied")
+
+// NewTriggersListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewTriggersListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("triggers list", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &TriggersListCommand{appCommand: ac}, nil
+	}
+}
+
+// TriggersListCommand is used to list the triggers of a Stitch App
+type TriggersListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (tlc *TriggersListCommand) Synopsis() string {
+	return `List the triggers of an app.`
+}
+
+// Help returns long-form help information for this command
+func (tlc *TriggersListCommand) Help() string {
+	return `List the triggers of an app along with their status, type and the function they call.
+
+OPTIONS:` +
+		tlc.appCommand.Help()
+}
+
+// Run executes the command
+func (tlc *TriggersListCommand) Run(args []string) int {
+	tlc.NewFlagSet()
+
+	if err := tlc.BaseCommand.run(args); err != nil {
+		tlc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := tlc.list(); err != nil {
+		tlc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (tlc *TriggersListCommand) list() error {
+	var triggers []*models.Trigger
+
+	if tlc.flagRemote {
+		app, err := tlc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		stitchClient, err := tlc.StitchClient()
+		if err != nil {
+			return err
+		}
+
+		if triggers, err = stitchClient.Triggers(app.GroupID, app.ID); err != nil {
+			return err
+		}
+	} else {
+		appPath, err := tlc.appDirectory()
+		if err != nil {
+			return err
+		}
+
+		localTriggers, err := utils.ReadLocalTriggers(appPath)
+		if err != nil {
+			return err
+		}
+
+		for _, trigger := range localTriggers {
+			triggers = append(triggers, &models.Trigger{
+				Name:         trigger.Name(),
+				Type:         trigger.Type(),
+				FunctionName: trigger.FunctionName(),
+				Disabled:     trigger.Disabled(),
+			})
+		}
+	}
+
+	if len(triggers) == 0 {
+		tlc.UI.Info("No triggers found")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tSTATUS\tFUNCTION")
+	for _, trigger := range triggers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", trigger.Name, trigger.Type, enabledStatus(trigger.Disabled), trigger.FunctionName)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	tlc.UI.Output(strings.TrimSuffix(buf.String(), "\n"))
+
+	return nil
+}
+
+// NewTriggersEnableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewTriggersEnableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newTriggersToggleCommandFactory("triggers enable", false, ui)
+}
+
+// NewTriggersDisableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewTriggersDisableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newTriggersToggleCommandFactory("triggers disable", true, ui)
+}
+
+func newTriggersToggleCommandFactory(name string, disable bool, ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand(name, ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &TriggersToggleCommand{appCommand: ac, disable: disable}, nil
+	}
+}
+
+// TriggersToggleCommand is used to enable or disable a trigger
+type TriggersToggleCommand struct {
+	*appCommand
+
+	disable bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (ttc *TriggersToggleCommand) Synopsis() string {
+	if ttc.disable {
+		return `Disable a trigger.`
+	}
+
+	return `Enable a trigger.`
+}
+
+// Help returns long-form help information for this command
+func (ttc *TriggersToggleCommand) Help() string {
+	description, usage := "Enable", "enable"
+	if ttc.disable {
+		description, usage = "Disable", "disable"
+	}
+
+	return fmt.Sprintf(`%s a trigger in the local app directory, or on the deployed app with --remote.
+
+Usage: stitch-cli triggers %s <name> [options]
+
+OPTIONS:`, description, usage) +
+		ttc.appCommand.Help()
+}
+
+// Run executes the command
+func (ttc *TriggersToggleCommand) Run(args []string) int {
+	ttc.NewFlagSet()
+
+	if err := ttc.BaseCommand.run(args); err != nil {
+		ttc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := ttc.toggle(); err != nil {
+		ttc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (ttc *TriggersToggleCommand) toggle() error {
+	if err := ttc.checkPositionalArgs(1, 1, errTriggerNameRequired); err != nil {
+		return err
+	}
+	name := ttc.positionalArgs[0]
+
+	status := enabledStatus(ttc.disable)
+
+	if ttc.flagRemote {
+		app, err := ttc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		triggerID, err := findRemoteTriggerID(ttc.BaseCommand, app, name)
+		if err != nil {
+			return err
+		}
+
+		stitchClient, err := ttc.StitchClient()
+		if err != nil {
+			return err
+		}
+
+		trigger, err := stitchClient.Trigger(app.GroupID, app.ID, triggerID)
+		if err != nil {
+			return err
+		}
+
+		if disabled, _ := trigger["disabled"].(bool); disabled == ttc.disable {
+			ttc.UI.Info(fmt.Sprintf("Trigger '%s' is already %s in '%s'", name, status, app.ClientAppID))
+			return nil
+		}
+
+		trigger["disabled"] = ttc.disable
+		if err := stitchClient.UpdateTrigger(app.GroupID, app.ID, triggerID, trigger); err != nil {
+			return err
+		}
+
+		ttc.UI.Info(fmt.Sprintf("Trigger '%s' is now %s in '%s'", name, status, app.ClientAppID))
+		return nil
+	}
+
+	appPath, err := ttc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	trigger, err := utils.ReadLocalTrigger(appPath, name)
+	if err != nil {
+		return err
+	}
+
+	if trigger.Disabled() == ttc.disable {
+		ttc.UI.Info(fmt.Sprintf("Trigger '%s' is already %s", name, status))
+		return nil
+	}
+
+	trigger.Config["disabled"] = ttc.disable
+	if err := utils.WriteLocalTrigger(trigger); err != nil {
+		return err
+	}
+
+	ttc.UI.Info(fmt.Sprintf("Trigger '%s' is now %s in %s", name, status, trigger.Path))
+	return nil
+}
+
+// NewTriggersResumeCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewTriggersResumeCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("triggers resume", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &TriggersResumeCommand{appCommand: ac}, nil
+	}
+}
+
+// TriggersResumeCommand is used to restart a suspended trigger of a deployed app
+type TriggersResumeCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (trc *TriggersResumeCommand) Synopsis() string {
+	return `Resume a suspended trigger of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (trc *TriggersResumeCommand) Help() string {
+	return `Resume a trigger of a deployed app that was suspended after a failure.
+
+Usage: stitch-cli triggers resume <name> [options]
+
+OPTIONS:` +
+		trc.appCommand.Help()
+}
+
+// Run executes the command
+func (trc *TriggersResumeCommand) Run(args []string) int {
+	trc.NewFlagSet()
+
+	if err := trc.BaseCommand.run(args); err != nil {
+		trc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := trc.resume(); err != nil {
+		trc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (trc *TriggersResumeCommand) resume() error {
+	if err := trc.checkPositionalArgs(1, 1, errTriggerNameRequired); err != nil {
+		return err
+	}
+	name := trc.positionalArgs[0]
+
+	app, err := trc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	triggerID, err := findRemoteTriggerID(trc.BaseCommand, app, name)
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := trc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	if err := stitchClient.ResumeTrigger(app.GroupID, app.ID, triggerID); err != nil {
+		return err
+	}
+
+	trc.UI.Info(fmt.Sprintf("Resumed trigger '%s' in '%s'", name, app.ClientAppID))
+	return nil
+}
+
+// findRemoteTriggerID looks up the ID of a deployed trigger by name
+func findRemoteTriggerID(c *BaseCommand, app *models.App, name string) (string, error) {
+	stitchClient, err := c.StitchClient()
+	if err != nil {
+		return "", err
+	}
+
+	triggers, err := stitchClient.Triggers(app.GroupID, app.ID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, trigger := range triggers {
+		if trigger.Name == name {
+			return trigger.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("trigger %q not found in '%s'", name, app.ClientAppID)
+}
+
+// enabledStatus describes whether something that can be disabled, such as a trigger, is enabled
+func enabledStatus(disabled bool) string {
+	if disabled {
+		return "disabled"
+	}
+
+	return "enabled"
+}
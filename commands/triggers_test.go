@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func newMockTriggersStitchClient(triggers []*models.Trigger) *u.MockStitchClient {
+	stitchClient := u.NewMockAppStitchClient()
+	stitchClient.TriggersFn = func(groupID, appID string) ([]*models.Trigger, error) {
+		return triggers, nil
+	}
+	stitchClient.TriggerFn = func(groupID, appID, triggerID string) (map[string]interface{}, error) {
+		for _, trigger := range triggers {
+			if trigger.ID == triggerID {
+				data, err := json.Marshal(trigger)
+				if err != nil {
+					return nil, err
+				}
+
+				var doc map[string]interface{}
+				return doc, json.Unmarshal(data, &doc)
+			}
+		}
+		return nil, fmt.Errorf("trigger %s not found", triggerID)
+	}
+
+	return stitchClient
+}
+
+func TestTriggersListCommand(t *testing.T) {
+	t.Run("should list local triggers", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewTriggersListCommandFactory)
+		listCommand := cmd.(*TriggersListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `NAME                   TYPE            STATUS   FUNCTION
+authEventSubscription  AUTHENTICATION  enabled  function_a
+dbEventSubscription    DATABASE        enabled  function_a
+`)
+	})
+
+	t.Run("should list deployed triggers", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewTriggersListCommandFactory)
+		listCommand := cmd.(*TriggersListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+		listCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		listCommand.stitchClient = newMockTriggersStitchClient([]*models.Trigger{
+			{ID: "1", Name: "onInsert", Type: "DATABASE", FunctionName: "handleInsert", Disabled: true},
+		})
+
+		exitCode := listCommand.Run([]string{"--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `NAME      TYPE      STATUS    FUNCTION
+onInsert  DATABASE  disabled  handleInsert
+`)
+	})
+}
+
+func TestTriggersToggleCommand(t *testing.T) {
+	t.Run("should require a trigger name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewTriggersDisableCommandFactory)
+		disableCommand := cmd.(*TriggersToggleCommand)
+		disableCommand.storage = u.NewEmptyStorage()
+
+		exitCode := disableCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errTriggerNameRequired.Error())
+	})
+
+	t.Run("should disable and enable a local trigger", func(t *testing.T) {
+		appDir := copyTestdataDirToTempApp(t, "triggers")
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewTriggersDisableCommandFactory)
+		disableCommand := cmd.(*TriggersToggleCommand)
+		disableCommand.storage = u.NewEmptyStorage()
+
+		exitCode := disableCommand.Run([]string{"dbEventSubscription", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		trigger, err := utils.ReadLocalTrigger(appDir, "dbEventSubscription")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, trigger.Disabled(), gc.ShouldBeTrue)
+		u.So(t, trigger.Config["config"], gc.ShouldContainKey, "operation_types")
+
+		cmd, mockUI = setUpAppCommand(NewTriggersEnableCommandFactory)
+		enableCommand := cmd.(*TriggersToggleCommand)
+		enableCommand.storage = u.NewEmptyStorage()
+
+		exitCode = enableCommand.Run([]string{"dbEventSubscription", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+
+		trigger, err = utils.ReadLocalTrigger(appDir, "dbEventSubscription")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, trigger.Disabled(), gc.ShouldBeFalse)
+	})
+
+	t.Run("should disable a deployed trigger", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewTriggersDisableCommandFactory)
+		disableCommand := cmd.(*TriggersToggleCommand)
+		disableCommand.storage = u.NewEmptyStorage()
+		disableCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var updatedID string
+		var updated map[string]interface{}
+		stitchClient := newMockTriggersStitchClient([]*models.Trigger{
+			{ID: "trigger-id", Name: "onInsert", Type: "DATABASE", Config: map[string]interface{}{"database": "db"}},
+		})
+		stitchClient.UpdateTriggerFn = func(groupID, appID, triggerID string, trigger map[string]interface{}) error {
+			updatedID = triggerID
+			updated = trigger
+			return nil
+		}
+		disableCommand.stitchClient = stitchClient
+
+		exitCode := disableCommand.Run([]string{"onInsert", "--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, updatedID, gc.ShouldEqual, "trigger-id")
+		u.So(t, updated["disabled"], gc.ShouldEqual, true)
+		u.So(t, updated["config"], gc.ShouldResemble, map[string]interface{}{"database": "db"})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "Trigger 'onInsert' is now disabled in 'my-app-abcdef'\n")
+	})
+
+	t.Run("should keep fields it does not know about when enabling a deployed trigger", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewTriggersEnableCommandFactory)
+		enableCommand := cmd.(*TriggersToggleCommand)
+		enableCommand.storage = u.NewEmptyStorage()
+		enableCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		deployed := map[string]interface{}{
+			"_id":              "trigger-id",
+			"name":             "onInsert",
+			"type":             "DATABASE",
+			"disabled":         true,
+			"config":           map[string]interface{}{"database": "db"},
+			"event_processors": map[string]interface{}{"FUNCTION": map[string]interface{}{"function_name": "handleInsert"}},
+		}
+
+		var updated map[string]interface{}
+		stitchClient := newMockTriggersStitchClient([]*models.Trigger{{ID: "trigger-id", Name: "onInsert"}})
+		stitchClient.TriggerFn = func(groupID, appID, triggerID string) (map[string]interface{}, error) {
+			return deployed, nil
+		}
+		stitchClient.UpdateTriggerFn = func(groupID, appID, triggerID string, trigger map[string]interface{}) error {
+			updated = trigger
+			return nil
+		}
+		enableCommand.stitchClient = stitchClient
+
+		exitCode := enableCommand.Run([]string{"onInsert", "--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, updated, gc.ShouldResemble, map[string]interface{}{
+			"_id":              "trigger-id",
+			"name":             "onInsert",
+			"type":             "DATABASE",
+			"disabled":         false,
+			"config":           map[string]interface{}{"database": "db"},
+			"event_processors": map[string]interface{}{"FUNCTION": map[string]interface{}{"function_name": "handleInsert"}},
+		})
+	})
+}
+
+func TestTriggersResumeCommand(t *testing.T) {
+	t.Run("should resume a deployed trigger", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewTriggersResumeCommandFactory)
+		resumeCommand := cmd.(*TriggersResumeCommand)
+		resumeCommand.storage = u.NewEmptyStorage()
+		resumeCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var resumedID string
+		stitchClient := newMockTriggersStitchClient([]*models.Trigger{{ID: "trigger-id", Name: "onInsert"}})
+		stitchClient.ResumeTriggerFn = func(groupID, appID, triggerID string) error {
+			resumedID = triggerID
+			return nil
+		}
+		resumeCommand.stitchClient = stitchClient
+
+		exitCode := resumeCommand.Run([]string{"onInsert", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, resumedID, gc.ShouldEqual, "trigger-id")
+	})
+}
@@ -0,0 +1,424 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/10gen/stitch-cli/api"
+	"github.com/10gen/stitch-cli/models"
+
+	"github.com/mitchellh/cli"
+)
+
+const (
+	usersFlagProviderType = "provider-type"
+	usersFlagStdin        = "stdin"
+)
+
+var (
+	errUserIDRequired   = errors.New("a user ID must be supplied")
+	errUserIDsRequired  = errors.New("at least one user ID must be supplied, either as arguments or through --stdin")
+	errStdinRequiresYes = errors.New("--stdin requires --yes since the confirmation cannot be read from standard input")
+)
+
+// NewUsersListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewUsersListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("users list", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &UsersListCommand{appCommand: ac}, nil
+	}
+}
+
+// UsersListCommand is used to list the users of a deployed app
+type UsersListCommand struct {
+	*appCommand
+
+	flagProviderType string
+}
+
+// Synopsis returns a one-liner description for this command
+func (ulc *UsersListCommand) Synopsis() string {
+	return `List the users of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (ulc *UsersListCommand) Help() string {
+	return `List the users of a deployed app along with the auth providers they log in with and their status.
+
+OPTIONS:
+  --provider-type [string]
+	Only list users with an identity from the given auth provider type, e.g. local-userpass or api-key.
+` +
+		ulc.appCommand.Help()
+}
+
+// Run executes the command
+func (ulc *UsersListCommand) Run(args []string) int {
+	set := ulc.NewFlagSet()
+
+	set.StringVar(&ulc.flagProviderType, usersFlagProviderType, "", "")
+
+	if err := ulc.BaseCommand.run(args); err != nil {
+		ulc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := ulc.list(); err != nil {
+		ulc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (ulc *UsersListCommand) list() error {
+	app, err := ulc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := ulc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	users, err := stitchClient.AppUsers(app.GroupID, app.ID, ulc.flagProviderType)
+	if err != nil {
+		return err
+	}
+
+	if len(users) == 0 {
+		ulc.UI.Info("No users found")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPROVIDERS\tSTATUS\tEMAIL\tLAST LOGIN")
+	for _, user := range users {
+		email, _ := user.Data["email"].(string)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", user.ID, appUserProviders(user), enabledStatus(user.Disabled), email, formatUnixTime(user.LastAuthenticationDate))
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	ulc.UI.Output(strings.TrimSuffix(buf.String(), "\n"))
+
+	return nil
+}
+
+// NewUsersShowCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewUsersShowCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("users show", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &UsersShowCommand{appCommand: ac}, nil
+	}
+}
+
+// UsersShowCommand is used to display a user of a deployed app
+type UsersShowCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (usc *UsersShowCommand) Synopsis() string {
+	return `Show a user of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (usc *UsersShowCommand) Help() string {
+	return `Show the identities, data and status of a user of a deployed app.
+
+Usage: stitch-cli users show <id> [options]
+
+OPTIONS:` +
+		usc.appCommand.Help()
+}
+
+// Run executes the command
+func (usc *UsersShowCommand) Run(args []string) int {
+	usc.NewFlagSet()
+
+	if err := usc.BaseCommand.run(args); err != nil {
+		usc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := usc.show(); err != nil {
+		usc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (usc *UsersShowCommand) show() error {
+	if err := usc.checkPositionalArgs(1, 1, errUserIDRequired); err != nil {
+		return err
+	}
+	userID := usc.positionalArgs[0]
+
+	app, err := usc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := usc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	user, err := stitchClient.AppUser(app.GroupID, app.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(user, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	usc.UI.Output(string(data))
+	return nil
+}
+
+// usersAction describes an operation applied to a batch of users
+type usersAction struct {
+	name        string
+	verb        string
+	synopsis    string
+	description string
+	past        string
+	// confirm, when set, is the format of the question asked before applying the action, given the
+	// number of users and the client app ID
+	confirm string
+	apply   func(stitchClient api.StitchClient, groupID, appID, userID string) error
+}
+
+var (
+	usersDeleteAction = usersAction{
+		name:        "delete",
+		verb:        "delete",
+		synopsis:    "Delete users of a deployed app.",
+		description: "Delete users of a deployed app along with their identities.",
+		past:        "Deleted",
+		confirm:     "Delete %d user(s) from '%s'?",
+		apply: func(stitchClient api.StitchClient, groupID, appID, userID string) error {
+			return stitchClient.DeleteAppUser(groupID, appID, userID)
+		},
+	}
+	usersEnableAction = usersAction{
+		name:        "enable",
+		verb:        "enable",
+		synopsis:    "Enable users of a deployed app.",
+		description: "Allow disabled users of a deployed app to log in again.",
+		past:        "Enabled",
+		apply: func(stitchClient api.StitchClient, groupID, appID, userID string) error {
+			return stitchClient.EnableAppUser(groupID, appID, userID)
+		},
+	}
+	usersDisableAction = usersAction{
+		name:        "disable",
+		verb:        "disable",
+		synopsis:    "Disable users of a deployed app.",
+		description: "Prevent users of a deployed app from logging in. Their existing sessions are revoked.",
+		past:        "Disabled",
+		apply: func(stitchClient api.StitchClient, groupID, appID, userID string) error {
+			return stitchClient.DisableAppUser(groupID, appID, userID)
+		},
+	}
+	usersRevokeSessionsAction = usersAction{
+		name:        "revoke-sessions",
+		verb:        "revoke the sessions of",
+		synopsis:    "Revoke the sessions of users of a deployed app.",
+		description: "Revoke every session of users of a deployed app, forcing them to log in again.",
+		past:        "Revoked the sessions of",
+		apply: func(stitchClient api.StitchClient, groupID, appID, userID string) error {
+			return stitchClient.RevokeAppUserSessions(groupID, appID, userID)
+		},
+	}
+)
+
+// NewUsersDeleteCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewUsersDeleteCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newUsersActionCommandFactory(usersDeleteAction, ui)
+}
+
+// NewUsersEnableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewUsersEnableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newUsersActionCommandFactory(usersEnableAction, ui)
+}
+
+// NewUsersDisableCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewUsersDisableCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newUsersActionCommandFactory(usersDisableAction, ui)
+}
+
+// NewUsersRevokeSessionsCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewUsersRevokeSessionsCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return newUsersActionCommandFactory(usersRevokeSessionsAction, ui)
+}
+
+func newUsersActionCommandFactory(action usersAction, ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("users "+action.name, ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.remoteOnly = true
+
+		return &UsersActionCommand{appCommand: ac, action: action, stdin: os.Stdin}, nil
+	}
+}
+
+// UsersActionCommand is used to delete, enable, disable or revoke the sessions of a batch of users of a deployed app
+type UsersActionCommand struct {
+	*appCommand
+
+	action usersAction
+	stdin  io.Reader
+
+	flagStdin bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (uac *UsersActionCommand) Synopsis() string {
+	return uac.action.synopsis
+}
+
+// Help returns long-form help information for this command
+func (uac *UsersActionCommand) Help() string {
+	stdinHelp := "Also read user IDs from standard input, separated by whitespace or newlines."
+	if uac.action.confirm != "" {
+		stdinHelp += " Requires --yes, as the\n\tconfirmation prompt cannot be answered through standard input."
+	}
+
+	return fmt.Sprintf(`%s
+
+Usage: stitch-cli users %s <id>... [options]
+
+OPTIONS:
+  --stdin
+	%s
+`, uac.action.description, uac.action.name, stdinHelp) +
+		uac.appCommand.Help()
+}
+
+// Run executes the command
+func (uac *UsersActionCommand) Run(args []string) int {
+	set := uac.NewFlagSet()
+
+	set.BoolVar(&uac.flagStdin, usersFlagStdin, false, "")
+
+	if err := uac.BaseCommand.run(args); err != nil {
+		uac.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := uac.apply(); err != nil {
+		uac.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (uac *UsersActionCommand) apply() error {
+	if uac.flagStdin && uac.action.confirm != "" && !uac.flagYes {
+		return errStdinRequiresYes
+	}
+
+	userIDs := append([]string{}, uac.positionalArgs...)
+	if uac.flagStdin {
+		scanner := bufio.NewScanner(uac.stdin)
+		scanner.Split(bufio.ScanWords)
+		for scanner.Scan() {
+			userIDs = append(userIDs, scanner.Text())
+		}
+
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	if len(userIDs) == 0 {
+		return errUserIDsRequired
+	}
+
+	app, err := uac.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	if uac.action.confirm != "" {
+		confirm, err := uac.AskYesNo(fmt.Sprintf(uac.action.confirm, len(userIDs), app.ClientAppID))
+		if err != nil || !confirm {
+			return err
+		}
+	}
+
+	stitchClient, err := uac.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, userID := range userIDs {
+		if err := uac.action.apply(stitchClient, app.GroupID, app.ID, userID); err != nil {
+			uac.UI.Error(fmt.Sprintf("failed to %s user '%s': %s", uac.action.verb, userID, err))
+			failed++
+			continue
+		}
+
+		uac.UI.Info(fmt.Sprintf("%s user '%s'", uac.action.past, userID))
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("failed to %s %d of %d user(s)", uac.action.verb, failed, len(userIDs))
+	}
+
+	return nil
+}
+
+// appUserProviders lists the distinct auth provider types a user has identities from
+func appUserProviders(user *models.AppUser) string {
+	var providers []string
+	seen := map[string]bool{}
+	for _, identity := range user.Identities {
+		if !seen[identity.ProviderType] {
+			seen[identity.ProviderType] = true
+			providers = append(providers, identity.ProviderType)
+		}
+	}
+
+	return strings.Join(providers, ",")
+}
+
+// formatUnixTime renders a timestamp in seconds since the epoch, or "never" if it is unset
+func formatUnixTime(seconds int64) string {
+	if seconds == 0 {
+		return "never"
+	}
+
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
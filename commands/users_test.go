@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUsersListCommand(t *testing.T) {
+	t.Run("should list the users of an app filtered by provider type", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersListCommandFactory)
+		listCommand := cmd.(*UsersListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+		listCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var providerType string
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.AppUsersFn = func(groupID, appID, filter string) ([]*models.AppUser, error) {
+			providerType = filter
+			return []*models.AppUser{
+				{
+					ID:                     "5a1f",
+					Identities:             []models.AppUserIdentity{{ID: "1", ProviderType: "local-userpass"}, {ID: "2", ProviderType: "api-key"}},
+					Data:                   map[string]interface{}{"email": "ada@example.com"},
+					LastAuthenticationDate: 1514905445,
+				},
+				{
+					ID:         "5a20",
+					Identities: []models.AppUserIdentity{{ID: "3", ProviderType: "local-userpass"}},
+					Disabled:   true,
+				},
+			}, nil
+		}
+		listCommand.stitchClient = stitchClient
+
+		exitCode := listCommand.Run([]string{"--app-id=my-app-abcdef", "--provider-type=local-userpass"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, providerType, gc.ShouldEqual, "local-userpass")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `ID    PROVIDERS               STATUS    EMAIL            LAST LOGIN
+5a1f  local-userpass,api-key  enabled   ada@example.com  2018-01-02T15:04:05Z
+5a20  local-userpass          disabled                   never
+`)
+	})
+}
+
+func TestUsersShowCommand(t *testing.T) {
+	t.Run("should require a user ID", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersShowCommandFactory)
+		showCommand := cmd.(*UsersShowCommand)
+		showCommand.storage = u.NewEmptyStorage()
+
+		exitCode := showCommand.Run([]string{"--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errUserIDRequired.Error())
+	})
+
+	t.Run("should show a user as JSON", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersShowCommandFactory)
+		showCommand := cmd.(*UsersShowCommand)
+		showCommand.storage = u.NewEmptyStorage()
+		showCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.AppUserFn = func(groupID, appID, userID string) (*models.AppUser, error) {
+			return &models.AppUser{ID: userID, Type: "normal"}, nil
+		}
+		showCommand.stitchClient = stitchClient
+
+		exitCode := showCommand.Run([]string{"5a1f", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `{
+    "_id": "5a1f",
+    "type": "normal",
+    "disabled": false
+}
+`)
+	})
+}
+
+func TestUsersActionCommand(t *testing.T) {
+	t.Run("should require at least one user ID", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersDisableCommandFactory)
+		actionCommand := cmd.(*UsersActionCommand)
+		actionCommand.storage = u.NewEmptyStorage()
+
+		exitCode := actionCommand.Run([]string{"--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errUserIDsRequired.Error())
+	})
+
+	t.Run("should require --yes to delete users read from stdin", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersDeleteCommandFactory)
+		deleteCommand := cmd.(*UsersActionCommand)
+		deleteCommand.storage = u.NewEmptyStorage()
+		deleteCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		deleteCommand.stdin = strings.NewReader("5a20\n")
+
+		var deleted []string
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.DeleteAppUserFn = func(groupID, appID, userID string) error {
+			deleted = append(deleted, userID)
+			return nil
+		}
+		deleteCommand.stitchClient = stitchClient
+
+		exitCode := deleteCommand.Run([]string{"--app-id=my-app-abcdef", "--stdin"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errStdinRequiresYes.Error())
+		u.So(t, deleted, gc.ShouldBeEmpty)
+	})
+
+	t.Run("should delete users read from arguments and stdin with --yes", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersDeleteCommandFactory)
+		deleteCommand := cmd.(*UsersActionCommand)
+		deleteCommand.storage = u.NewEmptyStorage()
+		deleteCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		deleteCommand.stdin = strings.NewReader("5a20\n5a21  5a22\n")
+
+		var deleted []string
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.DeleteAppUserFn = func(groupID, appID, userID string) error {
+			deleted = append(deleted, userID)
+			return nil
+		}
+		deleteCommand.stitchClient = stitchClient
+
+		exitCode := deleteCommand.Run([]string{"5a1f", "--app-id=my-app-abcdef", "--stdin", "--yes"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, deleted, gc.ShouldResemble, []string{"5a1f", "5a20", "5a21", "5a22"})
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Delete 4 user(s) from 'my-app-abcdef'?")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Deleted user '5a22'")
+	})
+
+	t.Run("should not delete users without confirmation", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersDeleteCommandFactory)
+		deleteCommand := cmd.(*UsersActionCommand)
+		deleteCommand.storage = u.NewEmptyStorage()
+		deleteCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		mockUI.InputReader = strings.NewReader("n\n")
+		deleteCommand.stitchClient = u.NewMockAppStitchClient()
+
+		exitCode := deleteCommand.Run([]string{"5a1f", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldNotContainSubstring, "Deleted")
+	})
+
+	t.Run("should keep going when some users fail and report the failures", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewUsersRevokeSessionsCommandFactory)
+		revokeCommand := cmd.(*UsersActionCommand)
+		revokeCommand.storage = u.NewEmptyStorage()
+		revokeCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.RevokeAppUserSessionsFn = func(groupID, appID, userID string) error {
+			if userID == "missing" {
+				return errors.New("user not found")
+			}
+			return nil
+		}
+		revokeCommand.stitchClient = stitchClient
+
+		exitCode := revokeCommand.Run([]string{"missing", "5a1f", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Revoked the sessions of user '5a1f'")
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "failed to revoke the sessions of user 'missing': user not found")
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "failed to revoke the sessions of 1 of 2 user(s)")
+	})
+}
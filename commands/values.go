@@ -0,0 +1,547 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+const valuesFlagPrivate = "private"
+
+var (
+	errValueNameRequired     = errors.New("a value name must be supplied")
+	errValueContentsRequired = errors.New("the contents of the value must be supplied as JSON")
+)
+
+// NewValuesListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewValuesListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("values list", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ValuesListCommand{appCommand: ac}, nil
+	}
+}
+
+// ValuesListCommand is used to list the values of a Stitch App
+type ValuesListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (vlc *ValuesListCommand) Synopsis() string {
+	return `List the values of an app.`
+}
+
+// Help returns long-form help information for this command
+func (vlc *ValuesListCommand) Help() string {
+	return `List the values of an app.
+
+OPTIONS:` +
+		vlc.appCommand.Help()
+}
+
+// Run executes the command
+func (vlc *ValuesListCommand) Run(args []string) int {
+	vlc.NewFlagSet()
+
+	if err := vlc.BaseCommand.run(args); err != nil {
+		vlc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := vlc.list(); err != nil {
+		vlc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (vlc *ValuesListCommand) list() error {
+	if vlc.flagRemote {
+		app, err := vlc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		stitchClient, err := vlc.StitchClient()
+		if err != nil {
+			return err
+		}
+
+		values, err := stitchClient.Values(app.GroupID, app.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			vlc.UI.Info(describeEntity(value.Name, value.Private))
+		}
+
+		return nil
+	}
+
+	appPath, err := vlc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	values, err := utils.ReadLocalValues(appPath)
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		vlc.UI.Info(describeEntity(value.Name(), value.Private()))
+	}
+
+	return nil
+}
+
+// NewValuesGetCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewValuesGetCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("values get", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ValuesGetCommand{appCommand: ac}, nil
+	}
+}
+
+// ValuesGetCommand is used to print the contents of a value
+type ValuesGetCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (vgc *ValuesGetCommand) Synopsis() string {
+	return `Print the contents of a value.`
+}
+
+// Help returns long-form help information for this command
+func (vgc *ValuesGetCommand) Help() string {
+	return `Print the contents of a value as JSON.
+
+Usage: stitch-cli values get <name> [options]
+
+OPTIONS:` +
+		vgc.appCommand.Help()
+}
+
+// Run executes the command
+func (vgc *ValuesGetCommand) Run(args []string) int {
+	vgc.NewFlagSet()
+
+	if err := vgc.BaseCommand.run(args); err != nil {
+		vgc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := vgc.get(); err != nil {
+		vgc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (vgc *ValuesGetCommand) get() error {
+	if err := vgc.checkPositionalArgs(1, 1, errValueNameRequired); err != nil {
+		return err
+	}
+	name := vgc.positionalArgs[0]
+
+	var contents interface{}
+	if vgc.flagRemote {
+		app, err := vgc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		value, err := fetchRemoteValue(vgc.BaseCommand, app, name)
+		if err != nil {
+			return err
+		}
+
+		contents = value.Value
+	} else {
+		appPath, err := vgc.appDirectory()
+		if err != nil {
+			return err
+		}
+
+		value, err := utils.ReadLocalValue(appPath, name)
+		if err != nil {
+			return err
+		}
+
+		contents = value.Value()
+	}
+
+	contentsJSON, err := json.MarshalIndent(contents, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	vgc.UI.Output(string(contentsJSON))
+
+	return nil
+}
+
+// NewValuesSetCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewValuesSetCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("values set", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ValuesSetCommand{appCommand: ac}, nil
+	}
+}
+
+// ValuesSetCommand is used to create or update a value
+type ValuesSetCommand struct {
+	*appCommand
+
+	flagPrivate bool
+}
+
+// Synopsis returns a one-liner description for this command
+func (vsc *ValuesSetCommand) Synopsis() string {
+	return `Create or update a value.`
+}
+
+// Help returns long-form help information for this command
+func (vsc *ValuesSetCommand) Help() string {
+	return `Create or update a value, writing it to values/<name>.json in the local app directory or to
+the deployed app with --remote.
+
+The contents must be valid JSON, so strings need to be quoted, e.g. '"on"'. If the contents are
+omitted, the current contents of an existing value are kept, which allows toggling --private alone.
+
+Usage: stitch-cli values set <name> [<json>] [options]
+
+OPTIONS:
+  --private, --private=false
+	Whether the value can only be accessed by functions and rules. Existing values keep their setting if omitted.
+` +
+		vsc.appCommand.Help()
+}
+
+// Run executes the command
+func (vsc *ValuesSetCommand) Run(args []string) int {
+	set := vsc.NewFlagSet()
+
+	set.BoolVar(&vsc.flagPrivate, valuesFlagPrivate, false, "")
+
+	if err := vsc.BaseCommand.run(args); err != nil {
+		vsc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := vsc.set(); err != nil {
+		vsc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+// privateFlagSet reports whether --private was explicitly provided
+func (vsc *ValuesSetCommand) privateFlagSet() bool {
+	isSet := false
+	vsc.Visit(func(f *flag.Flag) {
+		if f.Name == valuesFlagPrivate {
+			isSet = true
+		}
+	})
+
+	return isSet
+}
+
+func (vsc *ValuesSetCommand) set() error {
+	if err := vsc.checkPositionalArgs(1, 2, errValueNameRequired); err != nil {
+		return err
+	}
+	name := vsc.positionalArgs[0]
+
+	var contents interface{}
+	hasContents := len(vsc.positionalArgs) > 1
+	if hasContents {
+		if err := json.Unmarshal([]byte(vsc.positionalArgs[1]), &contents); err != nil {
+			return fmt.Errorf("the contents of value %q must be valid JSON: %s", name, err)
+		}
+	}
+
+	if vsc.flagRemote {
+		return vsc.setRemote(name, contents, hasContents)
+	}
+
+	if err := utils.ValidateValueName(name); err != nil {
+		return err
+	}
+
+	appPath, err := vsc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	value, err := findLocalValue(appPath, name)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		if !hasContents {
+			return errValueContentsRequired
+		}
+
+		value = &utils.LocalValue{Config: map[string]interface{}{"name": name}}
+	}
+
+	if hasContents {
+		value.Config["value"] = contents
+	}
+
+	if vsc.privateFlagSet() || value.Config["private"] == nil {
+		value.Config["private"] = vsc.flagPrivate
+	}
+
+	if err := utils.WriteLocalValue(appPath, value); err != nil {
+		return err
+	}
+
+	vsc.UI.Info(fmt.Sprintf("Wrote value '%s' to %s", name, value.Path))
+
+	return nil
+}
+
+func (vsc *ValuesSetCommand) setRemote(name string, contents interface{}, hasContents bool) error {
+	app, err := vsc.remoteApp()
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := vsc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	value, err := findRemoteValue(vsc.BaseCommand, app, name)
+	if err != nil {
+		return err
+	}
+
+	if value != nil {
+		if hasContents {
+			value.Value = contents
+		}
+
+		if vsc.privateFlagSet() {
+			value.Private = vsc.flagPrivate
+		}
+
+		if err := stitchClient.UpdateValue(app.GroupID, app.ID, value); err != nil {
+			return err
+		}
+
+		vsc.UI.Info(fmt.Sprintf("Updated value '%s' in '%s'", name, app.ClientAppID))
+		return nil
+	}
+
+	if !hasContents {
+		return errValueContentsRequired
+	}
+
+	if _, err := stitchClient.CreateValue(app.GroupID, app.ID, &models.Value{
+		Name:    name,
+		Value:   contents,
+		Private: vsc.flagPrivate,
+	}); err != nil {
+		return err
+	}
+
+	vsc.UI.Info(fmt.Sprintf("Created value '%s' in '%s'", name, app.ClientAppID))
+	return nil
+}
+
+// NewValuesRemoveCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewValuesRemoveCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("values rm", ui)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ValuesRemoveCommand{appCommand: ac}, nil
+	}
+}
+
+// ValuesRemoveCommand is used to delete a value
+type ValuesRemoveCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (vrc *ValuesRemoveCommand) Synopsis() string {
+	return `Delete a value.`
+}
+
+// Help returns long-form help information for this command
+func (vrc *ValuesRemoveCommand) Help() string {
+	return `Delete a value from the local app directory, or from the deployed app with --remote.
+
+Usage: stitch-cli values rm <name> [options]
+
+OPTIONS:` +
+		vrc.appCommand.Help()
+}
+
+// Run executes the command
+func (vrc *ValuesRemoveCommand) Run(args []string) int {
+	vrc.NewFlagSet()
+
+	if err := vrc.BaseCommand.run(args); err != nil {
+		vrc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := vrc.remove(); err != nil {
+		vrc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (vrc *ValuesRemoveCommand) remove() error {
+	if err := vrc.checkPositionalArgs(1, 1, errValueNameRequired); err != nil {
+		return err
+	}
+	name := vrc.positionalArgs[0]
+
+	if vrc.flagRemote {
+		app, err := vrc.remoteApp()
+		if err != nil {
+			return err
+		}
+
+		value, err := fetchRemoteValue(vrc.BaseCommand, app, name)
+		if err != nil {
+			return err
+		}
+
+		confirm, err := vrc.AskYesNo(fmt.Sprintf("Delete value '%s' from '%s'?", name, app.ClientAppID))
+		if err != nil || !confirm {
+			return err
+		}
+
+		stitchClient, err := vrc.StitchClient()
+		if err != nil {
+			return err
+		}
+
+		if err := stitchClient.DeleteValue(app.GroupID, app.ID, value.ID); err != nil {
+			return err
+		}
+
+		vrc.UI.Info(fmt.Sprintf("Deleted value '%s' from '%s'", name, app.ClientAppID))
+		return nil
+	}
+
+	appPath, err := vrc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	value, err := utils.ReadLocalValue(appPath, name)
+	if err != nil {
+		return err
+	}
+
+	confirm, err := vrc.AskYesNo(fmt.Sprintf("Delete value '%s' from %s?", name, value.Path))
+	if err != nil || !confirm {
+		return err
+	}
+
+	if err := os.Remove(value.Path); err != nil {
+		return err
+	}
+
+	vrc.UI.Info(fmt.Sprintf("Deleted value '%s'", name))
+	return nil
+}
+
+// fetchRemoteValue looks up a deployed value by name, along with its contents
+func fetchRemoteValue(c *BaseCommand, app *models.App, name string) (*models.Value, error) {
+	value, err := findRemoteValue(c, app, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("value %q not found in '%s'", name, app.ClientAppID)
+	}
+
+	return value, nil
+}
+
+// findRemoteValue returns the deployed value with the given name along with its contents, or nil if there
+// is none. Values are listed without their contents, which are fetched separately
+func findRemoteValue(c *BaseCommand, app *models.App, name string) (*models.Value, error) {
+	stitchClient, err := c.StitchClient()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := stitchClient.Values(app.GroupID, app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	value := findValueByName(values, name)
+	if value == nil {
+		return nil, nil
+	}
+
+	return stitchClient.Value(app.GroupID, app.ID, value.ID)
+}
+
+func findValueByName(values []*models.Value, name string) *models.Value {
+	for _, value := range values {
+		if value.Name == name {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// findLocalValue returns the local value with the given name, or nil if there is none
+func findLocalValue(appPath, name string) (*utils.LocalValue, error) {
+	values, err := utils.ReadLocalValues(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		if value.Name() == name {
+			return value, nil
+		}
+	}
+
+	return nil, nil
+}
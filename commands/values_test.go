@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+// newMockValuesStitchClient returns a MockStitchClient for an app with the given values. As with the Admin
+// API, values are listed without their contents, which must be fetched one value at a time
+func newMockValuesStitchClient(values []*models.Value) *u.MockStitchClient {
+	stitchClient := u.NewMockAppStitchClient()
+	stitchClient.ValuesFn = func(groupID, appID string) ([]*models.Value, error) {
+		listed := make([]*models.Value, 0, len(values))
+		for _, value := range values {
+			listed = append(listed, &models.Value{ID: value.ID, Name: value.Name, Private: value.Private})
+		}
+		return listed, nil
+	}
+	stitchClient.ValueFn = func(groupID, appID, valueID string) (*models.Value, error) {
+		for _, value := range values {
+			if value.ID == valueID {
+				copied := *value
+				return &copied, nil
+			}
+		}
+		return nil, fmt.Errorf("value %s not found", valueID)
+	}
+
+	return stitchClient
+}
+
+func TestValuesListCommand(t *testing.T) {
+	t.Run("should list local values", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesListCommandFactory)
+		listCommand := cmd.(*ValuesListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "a\nb (private)\n")
+	})
+
+	t.Run("should list deployed values", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesListCommandFactory)
+		listCommand := cmd.(*ValuesListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+		listCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		listCommand.stitchClient = newMockValuesStitchClient([]*models.Value{{Name: "flag", Private: true}})
+
+		exitCode := listCommand.Run([]string{"--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "flag (private)\n")
+	})
+}
+
+func TestValuesGetCommand(t *testing.T) {
+	t.Run("should require a value name", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesGetCommandFactory)
+		getCommand := cmd.(*ValuesGetCommand)
+		getCommand.storage = u.NewEmptyStorage()
+
+		exitCode := getCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errValueNameRequired.Error())
+	})
+
+	t.Run("should print the contents of a local value", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesGetCommandFactory)
+		getCommand := cmd.(*ValuesGetCommand)
+		getCommand.storage = u.NewEmptyStorage()
+
+		exitCode := getCommand.Run([]string{"a", "--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "\"AAAAAA\"\n")
+	})
+
+	t.Run("should print the contents of a deployed value", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesGetCommandFactory)
+		getCommand := cmd.(*ValuesGetCommand)
+		getCommand.storage = u.NewEmptyStorage()
+		getCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+		getCommand.stitchClient = newMockValuesStitchClient([]*models.Value{{ID: "value-id", Name: "flag", Value: true}})
+
+		exitCode := getCommand.Run([]string{"flag", "--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "true\n")
+	})
+}
+
+func TestValuesSetCommand(t *testing.T) {
+	t.Run("should reject contents that are not valid JSON", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewValuesSetCommandFactory)
+		setCommand := cmd.(*ValuesSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+
+		exitCode := setCommand.Run([]string{"flag", "on", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `the contents of value "flag" must be valid JSON`)
+	})
+
+	t.Run("should reject value names that are not valid file names", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewValuesSetCommandFactory)
+		setCommand := cmd.(*ValuesSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+
+		exitCode := setCommand.Run([]string{"../escaped", "true", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `invalid value name "../escaped"`)
+
+		_, err := os.Stat(filepath.Join(appDir, "escaped.json"))
+		u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	})
+
+	t.Run("should create a local value", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		cmd, mockUI := setUpAppCommand(NewValuesSetCommandFactory)
+		setCommand := cmd.(*ValuesSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+
+		exitCode := setCommand.Run([]string{"flag", `{"enabled": true}`, "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+
+		value, err := utils.ReadLocalValue(appDir, "flag")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, value.Path, gc.ShouldEqual, filepath.Join(appDir, "values", "flag.json"))
+		u.So(t, value.Value(), gc.ShouldResemble, map[string]interface{}{"enabled": true})
+		u.So(t, value.Private(), gc.ShouldBeFalse)
+
+		t.Run("and toggle it private while keeping its contents", func(t *testing.T) {
+			cmd, _ := setUpAppCommand(NewValuesSetCommandFactory)
+			setCommand := cmd.(*ValuesSetCommand)
+			setCommand.storage = u.NewEmptyStorage()
+
+			exitCode := setCommand.Run([]string{"flag", "--path=" + appDir, "--private"})
+			u.So(t, exitCode, gc.ShouldEqual, 0)
+
+			value, err := utils.ReadLocalValue(appDir, "flag")
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, value.Value(), gc.ShouldResemble, map[string]interface{}{"enabled": true})
+			u.So(t, value.Private(), gc.ShouldBeTrue)
+		})
+
+		t.Run("and update its contents while keeping it private", func(t *testing.T) {
+			cmd, _ := setUpAppCommand(NewValuesSetCommandFactory)
+			setCommand := cmd.(*ValuesSetCommand)
+			setCommand.storage = u.NewEmptyStorage()
+
+			exitCode := setCommand.Run([]string{"flag", "false", "--path=" + appDir})
+			u.So(t, exitCode, gc.ShouldEqual, 0)
+
+			value, err := utils.ReadLocalValue(appDir, "flag")
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, value.Value(), gc.ShouldEqual, false)
+			u.So(t, value.Private(), gc.ShouldBeTrue)
+
+			app, err := utils.UnmarshalFromDir(appDir)
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, app["values"], gc.ShouldHaveLength, 1)
+		})
+	})
+
+	t.Run("should update a deployed value", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesSetCommandFactory)
+		setCommand := cmd.(*ValuesSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+		setCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var updated *models.Value
+		stitchClient := newMockValuesStitchClient([]*models.Value{{ID: "value-id", Name: "flag", Value: false, Private: true}})
+		stitchClient.UpdateValueFn = func(groupID, appID string, value *models.Value) error {
+			updated = value
+			return nil
+		}
+		setCommand.stitchClient = stitchClient
+
+		exitCode := setCommand.Run([]string{"flag", "true", "--remote", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, updated, gc.ShouldResemble, &models.Value{ID: "value-id", Name: "flag", Value: true, Private: true})
+	})
+
+	t.Run("should keep the contents of a deployed value when only making it private", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesSetCommandFactory)
+		setCommand := cmd.(*ValuesSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+		setCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var updated *models.Value
+		stitchClient := newMockValuesStitchClient([]*models.Value{{ID: "value-id", Name: "token", Value: "s3cr3t"}})
+		stitchClient.UpdateValueFn = func(groupID, appID string, value *models.Value) error {
+			updated = value
+			return nil
+		}
+		setCommand.stitchClient = stitchClient
+
+		exitCode := setCommand.Run([]string{"token", "--remote", "--app-id=my-app-abcdef", "--private=true"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, updated, gc.ShouldResemble, &models.Value{ID: "value-id", Name: "token", Value: "s3cr3t", Private: true})
+	})
+
+	t.Run("should create a deployed value", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesSetCommandFactory)
+		setCommand := cmd.(*ValuesSetCommand)
+		setCommand.storage = u.NewEmptyStorage()
+		setCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var created *models.Value
+		stitchClient := newMockValuesStitchClient([]*models.Value{})
+		stitchClient.CreateValueFn = func(groupID, appID string, value *models.Value) (*models.Value, error) {
+			created = value
+			return value, nil
+		}
+		setCommand.stitchClient = stitchClient
+
+		exitCode := setCommand.Run([]string{"flag", `"on"`, "--remote", "--app-id=my-app-abcdef", "--private"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, created, gc.ShouldResemble, &models.Value{Name: "flag", Value: "on", Private: true})
+	})
+}
+
+func TestValuesRemoveCommand(t *testing.T) {
+	t.Run("should remove a local value once confirmed", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		err := utils.WriteLocalValue(appDir, &utils.LocalValue{Config: map[string]interface{}{"name": "flag", "value": true}})
+		u.So(t, err, gc.ShouldBeNil)
+
+		cmd, mockUI := setUpAppCommand(NewValuesRemoveCommandFactory)
+		removeCommand := cmd.(*ValuesRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		mockUI.InputReader = strings.NewReader("y\n")
+
+		exitCode := removeCommand.Run([]string{"flag", "--path=" + appDir})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+
+		_, err = os.Stat(filepath.Join(appDir, "values", "flag.json"))
+		u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	})
+
+	t.Run("should remove a deployed value", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewValuesRemoveCommandFactory)
+		removeCommand := cmd.(*ValuesRemoveCommand)
+		removeCommand.storage = u.NewEmptyStorage()
+		removeCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		var deletedID string
+		stitchClient := newMockValuesStitchClient([]*models.Value{{ID: "value-id", Name: "flag"}})
+		stitchClient.DeleteValueFn = func(groupID, appID, valueID string) error {
+			deletedID = valueID
+			return nil
+		}
+		removeCommand.stitchClient = stitchClient
+
+		exitCode := removeCommand.Run([]string{"flag", "--remote", "--app-id=my-app-abcdef", "-y"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, deletedID, gc.ShouldEqual, "value-id")
+	})
+}
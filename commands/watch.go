@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+)
+
+const (
+	watchFlagDebounce     = "debounce"
+	watchFlagPollInterval = "poll-interval"
+
+	defaultWatchDebounce     = 500 * time.Millisecond
+	defaultWatchPollInterval = time.Second
+)
+
+// NewWatchCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewWatchCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		workingDirectory, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+
+		return &WatchCommand{
+			BaseCommand: &BaseCommand{
+				Name: "watch",
+				UI:   ui,
+			},
+			workingDirectory: workingDirectory,
+			newWatcher:       utils.NewDirWatcher,
+		}, nil
+	}
+}
+
+// WatchCommand is used to continuously import a Stitch App as its local directory changes
+type WatchCommand struct {
+	*BaseCommand
+
+	workingDirectory string
+	newWatcher       func(root string, pollInterval time.Duration) (utils.DirWatcher, error)
+	interrupt        chan os.Signal
+
+	flagAppPath        string
+	flagAppID          string
+	flagDebounce       time.Duration
+	flagPollInterval   time.Duration
+	flagRedactedValues string
+}
+
+// Help returns long-form help information for this command
+func (wc *WatchCommand) Help() string {
+	return `Watch a local app directory and import it with the merge strategy whenever it changes.
+
+OPTIONS:
+  --path [string]
+	A path to the local directory containing your app. Defaults to the app directory containing the working directory.
+
+  --app-id [string]
+	The App ID for your app. Defaults to the "app_id" in the directory's stitch.json.
+
+  --debounce [duration] (default: 500ms)
+	How long to wait for changes to settle before importing.
+
+  --poll-interval [duration] (default: 1s)
+	How often to scan the directory when OS file notifications are unavailable.
+
+  --redacted-values [string]
+	A path to a filled-in copy of the "secrets.template.json" file written by "export --redact". Required when
+	the directory contains redacted placeholders, which are never imported as is. The file is re-read on
+	every import.` +
+		wc.BaseCommand.Help()
+}
+
+// Synopsis returns a one-liner description for this command
+func (wc *WatchCommand) Synopsis() string {
+	return `Continuously import a local app directory as it changes.`
+}
+
+// Run executes the command
+func (wc *WatchCommand) Run(args []string) int {
+	set := wc.NewFlagSet()
+
+	set.StringVar(&wc.flagAppPath, importFlagPath, "", "")
+	set.StringVar(&wc.flagAppID, flagAppIDName, "", "")
+	set.DurationVar(&wc.flagDebounce, watchFlagDebounce, defaultWatchDebounce, "")
+	set.DurationVar(&wc.flagPollInterval, watchFlagPollInterval, defaultWatchPollInterval, "")
+	set.StringVar(&wc.flagRedactedValues, importFlagRedacted, "", "")
+
+	if err := wc.BaseCommand.run(args); err != nil {
+		wc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := wc.watch(); err != nil {
+		wc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (wc *WatchCommand) watch() error {
+	if err := wc.requireLogin(); err != nil {
+		return err
+	}
+
+	appPath, err := resolveAppDirectory(wc.flagAppPath, wc.workingDirectory)
+	if err != nil {
+		return err
+	}
+
+	app, err := wc.resolveApp(wc.flagAppID, "", appPath)
+	if err != nil {
+		return err
+	}
+
+	// fail fast on a directory that cannot be imported as is, such as one holding redacted placeholders
+	// without --redacted-values
+	if _, err := wc.loadApp(appPath); err != nil {
+		return err
+	}
+
+	watcher, err := wc.newWatcher(appPath, wc.flagPollInterval)
+	if err != nil {
+		return fmt.Errorf("failed to watch %q: %s", appPath, err)
+	}
+	defer watcher.Close()
+
+	if wc.interrupt == nil {
+		wc.interrupt = make(chan os.Signal, 1)
+		signal.Notify(wc.interrupt, os.Interrupt)
+		defer signal.Stop(wc.interrupt)
+	}
+
+	wc.UI.Info(fmt.Sprintf("Watching %s for changes to '%s' (press Ctrl+C to stop)", appPath, app.ClientAppID))
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return nil
+			}
+			debounce = time.After(wc.flagDebounce)
+		case err := <-watcher.Errors():
+			wc.UI.Warn(fmt.Sprintf("watch error: %s", err))
+		case <-debounce:
+			debounce = nil
+			if err := wc.sync(app, appPath); err != nil {
+				wc.UI.Error(fmt.Sprintf("[%s] import failed: %s", timestamp(), err))
+			}
+		case <-wc.interrupt:
+			return nil
+		}
+	}
+}
+
+// sync imports the current contents of the app directory using the merge strategy
+func (wc *WatchCommand) sync(app *models.App, appPath string) error {
+	loadedApp, err := wc.loadApp(appPath)
+	if err != nil {
+		return err
+	}
+
+	appData, err := json.Marshal(loadedApp)
+	if err != nil {
+		return err
+	}
+
+	stitchClient, err := wc.StitchClient()
+	if err != nil {
+		return err
+	}
+
+	diffs, err := stitchClient.Diff(app.GroupID, app.ID, appData, importStrategyMerge)
+	if err != nil {
+		return fmt.Errorf("failed to diff app with currently deployed instance: %s", err)
+	}
+
+	if len(diffs) == 0 {
+		wc.UI.Info(fmt.Sprintf("[%s] no changes to import", timestamp()))
+		return nil
+	}
+
+	for _, diff := range diffs {
+		wc.UI.Info("  " + diff)
+	}
+
+	if err := stitchClient.Import(app.GroupID, app.ID, appData, importStrategyMerge); err != nil {
+		return err
+	}
+
+	wc.UI.Info(fmt.Sprintf("[%s] imported %d change(s) to '%s'", timestamp(), len(diffs), app.ClientAppID))
+
+	return nil
+}
+
+func timestamp() string {
+	return time.Now().Format("15:04:05")
+}
+
+// loadApp reads the app directory, replacing any redacted placeholders with the values passed with
+// --redacted-values so that they are never imported in place of the deployed secrets
+func (wc *WatchCommand) loadApp(appPath string) (map[string]interface{}, error) {
+	loadedApp, err := utils.UnmarshalFromDir(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(utils.RedactedKeys(loadedApp)) != 0 {
+		if err := wc.resolveRedactedValuesFromFile(loadedApp, wc.flagRedactedValues); err != nil {
+			return nil, err
+		}
+	}
+	return loadedApp, nil
+}
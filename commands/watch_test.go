@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/10gen/stitch-cli/user"
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+type mockDirWatcher struct {
+	changes chan string
+	errors  chan error
+	closed  bool
+}
+
+func (mdw *mockDirWatcher) Changes() <-chan string { return mdw.changes }
+func (mdw *mockDirWatcher) Errors() <-chan error   { return mdw.errors }
+func (mdw *mockDirWatcher) Close() error {
+	mdw.closed = true
+	return nil
+}
+
+func TestWatchCommand(t *testing.T) {
+	setup := func() (*WatchCommand, *cli.MockUi, *mockDirWatcher) {
+		mockUI := cli.NewMockUi()
+		cmd, err := NewWatchCommandFactory(mockUI)()
+		if err != nil {
+			panic(err)
+		}
+
+		watcher := &mockDirWatcher{
+			changes: make(chan string),
+			errors:  make(chan error),
+		}
+
+		watchCommand := cmd.(*WatchCommand)
+		watchCommand.storage = u.NewEmptyStorage()
+		watchCommand.interrupt = make(chan os.Signal, 1)
+		watchCommand.newWatcher = func(root string, pollInterval time.Duration) (utils.DirWatcher, error) {
+			return watcher, nil
+		}
+
+		return watchCommand, mockUI, watcher
+	}
+
+	t.Run("should require the user to be logged in", func(t *testing.T) {
+		watchCommand, mockUI, _ := setup()
+		exitCode := watchCommand.Run([]string{"--path=../testdata/full_app", "--app-id=my-app-abcdef"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, user.ErrNotLoggedIn.Error())
+	})
+
+	t.Run("should require an app id", func(t *testing.T) {
+		watchCommand, mockUI, _ := setup()
+		watchCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		exitCode := watchCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "must be supplied")
+	})
+
+	t.Run("should import on every change and keep running when an import fails", func(t *testing.T) {
+		watchCommand, mockUI, watcher := setup()
+		watchCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+
+		imported := make(chan string, 2)
+		var importCalls int
+		stitchClient := u.NewMockAppStitchClient()
+		stitchClient.DiffFn = func(groupID, appID string, appData []byte, strategy string) ([]string, error) {
+			return []string{"Modified function: function_a"}, nil
+		}
+		stitchClient.ImportFn = func(groupID, appID string, appData []byte, strategy string) error {
+			importCalls++
+			imported <- strategy
+			if importCalls == 1 {
+				return errors.New("oh noes")
+			}
+			return nil
+		}
+		watchCommand.stitchClient = stitchClient
+
+		done := make(chan int)
+		go func() {
+			done <- watchCommand.Run([]string{"--path=../testdata/full_app", "--app-id=my-app-abcdef", "--debounce=1ms"})
+		}()
+
+		watcher.changes <- "functions/function_a/source.js"
+		u.So(t, <-imported, gc.ShouldEqual, importStrategyMerge)
+
+		watcher.changes <- "functions/function_a/source.js"
+		u.So(t, <-imported, gc.ShouldEqual, importStrategyMerge)
+
+		close(watcher.changes)
+		u.So(t, <-done, gc.ShouldEqual, 0)
+
+		u.So(t, watcher.closed, gc.ShouldBeTrue)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "import failed: oh noes")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "Modified function: function_a")
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "imported 1 change(s) to 'my-app-abcdef'")
+	})
+	t.Run("should not import redacted placeholders as is", func(t *testing.T) {
+		redactedClient := func(importedData chan []byte) *u.MockStitchClient {
+			stitchClient := u.NewMockAppStitchClient()
+			stitchClient.DiffFn = func(groupID, appID string, appData []byte, strategy string) ([]string, error) {
+				return []string{"Modified value: b"}, nil
+			}
+			stitchClient.ImportFn = func(groupID, appID string, appData []byte, strategy string) error {
+				importedData <- appData
+				return nil
+			}
+
+			return stitchClient
+		}
+
+		t.Run("unless the values are provided", func(t *testing.T) {
+			watchCommand, mockUI, _ := setup()
+			watchCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+			stitchClient := redactedClient(make(chan []byte, 1))
+			watchCommand.stitchClient = stitchClient
+
+			exitCode := watchCommand.Run([]string{"--path=../testdata/redacted_app", "--app-id=my-app-abcdef"})
+			u.So(t, exitCode, gc.ShouldEqual, 1)
+			u.So(t, stitchClient.ImportFnCalls, gc.ShouldBeEmpty)
+			u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "app contains redacted values; provide them with --redacted-values: values/value_b.json:value")
+		})
+
+		t.Run("and import the provided values instead", func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "stitch-watch")
+			u.So(t, err, gc.ShouldBeNil)
+			defer os.RemoveAll(dir)
+
+			valuesPath := filepath.Join(dir, "secrets.json")
+			u.So(t, ioutil.WriteFile(valuesPath, []byte(`{"values/value_b.json:value": "shh"}`), 0600), gc.ShouldBeNil)
+
+			watchCommand, _, watcher := setup()
+			watchCommand.user = &user.User{AccessToken: u.GenerateValidAccessToken()}
+			importedData := make(chan []byte, 1)
+			watchCommand.stitchClient = redactedClient(importedData)
+
+			done := make(chan int)
+			go func() {
+				done <- watchCommand.Run([]string{"--path=../testdata/redacted_app", "--app-id=my-app-abcdef", "--debounce=1ms", "--redacted-values=" + valuesPath})
+			}()
+
+			watcher.changes <- "values/value_b.json"
+			appData := string(<-importedData)
+			u.So(t, appData, gc.ShouldContainSubstring, `"value":"shh"`)
+			u.So(t, appData, gc.ShouldNotContainSubstring, utils.RedactedPlaceholderPrefix)
+
+			close(watcher.changes)
+			u.So(t, <-done, gc.ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/10gen/stitch-cli/api"
+	"github.com/10gen/stitch-cli/models"
+	"github.com/10gen/stitch-cli/utils"
+
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	webhooksFlagBody   = "body"
+	webhooksFlagQuery  = "query"
+	webhooksFlagMethod = "method"
+	webhooksFlagSecret = "secret"
+)
+
+var errWebhookNameRequired = errors.New("an incoming webhook must be supplied as <service>/<webhook>")
+
+// NewWebhooksListCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewWebhooksListCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("webhooks list", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &WebhooksListCommand{appCommand: ac}, nil
+	}
+}
+
+// WebhooksListCommand is used to list the incoming webhooks of a local app directory
+type WebhooksListCommand struct {
+	*appCommand
+}
+
+// Synopsis returns a one-liner description for this command
+func (wlc *WebhooksListCommand) Synopsis() string {
+	return `List the incoming webhooks of an app.`
+}
+
+// Help returns long-form help information for this command
+func (wlc *WebhooksListCommand) Help() string {
+	return `List the incoming webhooks of every service of the local app directory along with the HTTP method
+they accept, how their requests are validated and their public URL.
+
+OPTIONS:
+  --app-id [string]
+	The App ID used to build the public URLs. Defaults to the "app_id" in the directory's stitch.json.
+` +
+		wlc.appCommand.Help()
+}
+
+// Run executes the command
+func (wlc *WebhooksListCommand) Run(args []string) int {
+	set := wlc.NewFlagSet()
+
+	set.StringVar(&wlc.flagAppID, flagAppIDName, "", "")
+
+	if err := wlc.BaseCommand.run(args); err != nil {
+		wlc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := wlc.list(); err != nil {
+		wlc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (wlc *WebhooksListCommand) list() error {
+	appPath, err := wlc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	clientAppID, err := webhooksClientAppID(wlc.flagAppID, appPath)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := utils.ReadLocalWebhooks(appPath)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		wlc.UI.Info("No incoming webhooks found")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tNAME\tMETHOD\tVALIDATION\tURL")
+	for _, webhook := range webhooks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			webhook.Service.Name(),
+			webhook.Name(),
+			webhook.HTTPMethod(),
+			webhook.ValidationMethod(),
+			wlc.flagBaseURL+api.IncomingWebhookPath(clientAppID, webhook.Service.Name(), webhook.Name()),
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	wlc.UI.Output(strings.TrimSuffix(buf.String(), "\n"))
+
+	return nil
+}
+
+// NewWebhooksCallCommandFactory returns a new cli.CommandFactory given a cli.Ui
+func NewWebhooksCallCommandFactory(ui cli.Ui) cli.CommandFactory {
+	return func() (cli.Command, error) {
+		ac, err := newAppCommand("webhooks call", ui)
+		if err != nil {
+			return nil, err
+		}
+		ac.localOnly = true
+
+		return &WebhooksCallCommand{appCommand: ac}, nil
+	}
+}
+
+// WebhooksCallCommand is used to send a request to an incoming webhook of a deployed app, validated
+// the way its local config requires
+type WebhooksCallCommand struct {
+	*appCommand
+
+	flagBody   string
+	flagQuery  string
+	flagMethod string
+	flagSecret string
+}
+
+// Synopsis returns a one-liner description for this command
+func (wcc *WebhooksCallCommand) Synopsis() string {
+	return `Send a request to an incoming webhook of a deployed app.`
+}
+
+// Help returns long-form help information for this command
+func (wcc *WebhooksCallCommand) Help() string {
+	return `Send a request to an incoming webhook of the deployed app and print its response.
+
+The request is validated the way the webhook's config in the local app directory requires: the secret
+is passed as the "secret" query parameter for SECRET_AS_QUERY_PARAM, and the body is signed with the
+secret for VERIFY_PAYLOAD (HMAC-SHA256 in X-Hook-Signature, or HMAC-SHA1 in X-Hub-Signature for GitHub).
+
+The command exits with a non-zero status if the webhook does not respond with a 2xx status.
+
+Usage: stitch-cli webhooks call <service>/<webhook> [options]
+
+OPTIONS:
+  --body [string]
+	The body of the request. Prefix with @ to read it from a file.
+
+  --query [string]
+	The query string of the request, e.g. 'page=2&sort=asc'.
+
+  --method [string]
+	The HTTP method of the request. Defaults to the method the webhook accepts, or POST if it accepts any.
+
+  --secret [string]
+	The secret to validate the request with. Defaults to the secret in the webhook's config.
+
+  --app-id [string]
+	The App ID of the deployed app. Defaults to the "app_id" in the directory's stitch.json.
+` +
+		wcc.appCommand.Help()
+}
+
+// Run executes the command
+func (wcc *WebhooksCallCommand) Run(args []string) int {
+	set := wcc.NewFlagSet()
+
+	set.StringVar(&wcc.flagAppID, flagAppIDName, "", "")
+	set.StringVar(&wcc.flagBody, webhooksFlagBody, "", "")
+	set.StringVar(&wcc.flagQuery, webhooksFlagQuery, "", "")
+	set.StringVar(&wcc.flagMethod, webhooksFlagMethod, "", "")
+	set.StringVar(&wcc.flagSecret, webhooksFlagSecret, "", "")
+
+	if err := wcc.BaseCommand.run(args); err != nil {
+		wcc.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := wcc.call(); err != nil {
+		wcc.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func (wcc *WebhooksCallCommand) call() error {
+	if err := wcc.checkPositionalArgs(1, 1, errWebhookNameRequired); err != nil {
+		return err
+	}
+
+	separator := strings.LastIndex(wcc.positionalArgs[0], "/")
+	if separator <= 0 || separator == len(wcc.positionalArgs[0])-1 {
+		return errWebhookNameRequired
+	}
+	serviceName, webhookName := wcc.positionalArgs[0][:separator], wcc.positionalArgs[0][separator+1:]
+
+	appPath, err := wcc.appDirectory()
+	if err != nil {
+		return err
+	}
+
+	clientAppID, err := webhooksClientAppID(wcc.flagAppID, appPath)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := utils.ReadLocalWebhook(appPath, serviceName, webhookName)
+	if err != nil {
+		return err
+	}
+
+	body, err := readWebhookBody(wcc.flagBody)
+	if err != nil {
+		return err
+	}
+
+	query, err := url.ParseQuery(wcc.flagQuery)
+	if err != nil {
+		return fmt.Errorf("failed to parse --%s: %s", webhooksFlagQuery, err)
+	}
+
+	method := strings.ToUpper(wcc.flagMethod)
+	if method == "" {
+		if method = webhook.HTTPMethod(); method == "ANY" {
+			method = http.MethodPost
+		}
+	}
+
+	secret := wcc.flagSecret
+	if secret == "" {
+		secret = webhook.Secret()
+	}
+
+	header := http.Header{}
+	if len(body) != 0 {
+		header.Set("Content-Type", "application/json")
+	}
+
+	switch validation := webhook.ValidationMethod(); validation {
+	case utils.WebhookValidationNone:
+	case utils.WebhookValidationQueryParam, utils.WebhookValidationVerifyPayload:
+		if secret == "" {
+			return fmt.Errorf("incoming webhook %q is validated with %s but has no secret: supply one with --%s", webhookName, validation, webhooksFlagSecret)
+		}
+
+		if strings.HasPrefix(secret, utils.RedactedPlaceholderPrefix) {
+			return fmt.Errorf("the secret of incoming webhook %q was redacted on export: supply it with --%s", webhookName, webhooksFlagSecret)
+		}
+
+		if validation == utils.WebhookValidationQueryParam {
+			query.Set(utils.WebhookSecretQueryParam, secret)
+		} else {
+			header.Set(webhook.SignatureHeader(body, secret))
+		}
+	default:
+		return fmt.Errorf("unknown validation method %q for incoming webhook %q", validation, webhookName)
+	}
+
+	path := api.IncomingWebhookPath(clientAppID, serviceName, webhookName)
+	if len(query) != 0 {
+		path += "?" + query.Encode()
+	}
+
+	client, err := wcc.Client()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.ExecuteRequest(method, path, api.RequestOptions{Body: bytes.NewReader(body), Header: header})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	wcc.UI.Info(res.Status)
+
+	var indented bytes.Buffer
+	if json.Indent(&indented, resBody, "", "    ") == nil {
+		resBody = indented.Bytes()
+	}
+	if len(resBody) != 0 {
+		wcc.UI.Output(string(resBody))
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("incoming webhook %q responded with %s", webhookName, res.Status)
+	}
+
+	return nil
+}
+
+// webhooksClientAppID returns the client App ID that the public URLs of the incoming webhooks are built from
+func webhooksClientAppID(clientAppID, appPath string) (string, error) {
+	if clientAppID != "" {
+		return clientAppID, nil
+	}
+
+	appInstanceData := models.AppInstanceData{}
+	if err := appInstanceData.UnmarshalFile(appPath); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if appInstanceData.AppID() == "" {
+		return "", fmt.Errorf("an App ID (--%s=[string]) must be supplied or present in %s", flagAppIDName, models.AppConfigFileName)
+	}
+
+	return appInstanceData.AppID(), nil
+}
+
+// readWebhookBody returns the body supplied to --body, reading it from a file when prefixed with @
+func readWebhookBody(body string) ([]byte, error) {
+	if !strings.HasPrefix(body, "@") {
+		return []byte(body), nil
+	}
+
+	path, err := homedir.Expand(body[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadFile(path)
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestWebhooksListCommand(t *testing.T) {
+	t.Run("should list the incoming webhooks of every service", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewWebhooksListCommandFactory)
+		listCommand := cmd.(*WebhooksListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/webhooks_app", "--base-url=https://stitch.example.com"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, `SERVICE  NAME    METHOD  VALIDATION             URL
+github   push    POST    VERIFY_PAYLOAD         https://stitch.example.com/api/client/v2.0/app/webhooks-app-abcde/service/github/incoming_webhook/push
+http     open    ANY     NO_VALIDATION          https://stitch.example.com/api/client/v2.0/app/webhooks-app-abcde/service/http/incoming_webhook/open
+http     query   GET     SECRET_AS_QUERY_PARAM  https://stitch.example.com/api/client/v2.0/app/webhooks-app-abcde/service/http/incoming_webhook/query
+http     signed  POST    VERIFY_PAYLOAD         https://stitch.example.com/api/client/v2.0/app/webhooks-app-abcde/service/http/incoming_webhook/signed
+`)
+	})
+
+	t.Run("should fall back to query param validation for services with a secret", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewWebhooksListCommandFactory)
+		listCommand := cmd.(*WebhooksListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/full_app", "--app-id=full-app-abcde"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldContainSubstring, "service a  webhook0  ANY     SECRET_AS_QUERY_PARAM  https://stitch.mongodb.com/api/client/v2.0/app/full-app-abcde/service/service%20a/incoming_webhook/webhook0")
+	})
+
+	t.Run("should require an App ID", func(t *testing.T) {
+		cmd, mockUI := setUpAppCommand(NewWebhooksListCommandFactory)
+		listCommand := cmd.(*WebhooksListCommand)
+		listCommand.storage = u.NewEmptyStorage()
+
+		exitCode := listCommand.Run([]string{"--path=../testdata/full_app"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, "an App ID (--app-id=[string]) must be supplied or present in stitch.json")
+	})
+}
+
+func TestWebhooksCallCommand(t *testing.T) {
+	setup := func(status int, body string) (*WebhooksCallCommand, *cli.MockUi, *u.MockClient) {
+		cmd, mockUI := setUpAppCommand(NewWebhooksCallCommandFactory)
+		callCommand := cmd.(*WebhooksCallCommand)
+		callCommand.storage = u.NewEmptyStorage()
+
+		mockClient := u.NewMockClient([]*http.Response{
+			{
+				StatusCode: status,
+				Status:     http.StatusText(status),
+				Body:       &u.ResponseBody{Buffer: bytes.NewBufferString(body)},
+			},
+		})
+		callCommand.client = mockClient
+
+		return callCommand, mockUI, mockClient
+	}
+
+	sign := func(hashFn func() []byte) string { return hex.EncodeToString(hashFn()) }
+
+	t.Run("should sign the body of webhooks that verify their payload", func(t *testing.T) {
+		callCommand, mockUI, mockClient := setup(http.StatusOK, `{"ok":true}`)
+
+		exitCode := callCommand.Run([]string{"--path=../testdata/webhooks_app", "http/signed", `--body={"a":1}`})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockUI.OutputWriter.String(), gc.ShouldEqual, "OK\n{\n    \"ok\": true\n}\n")
+
+		u.So(t, mockClient.RequestData, gc.ShouldHaveLength, 1)
+		request := mockClient.RequestData[0]
+		u.So(t, request.Method, gc.ShouldEqual, http.MethodPost)
+		u.So(t, request.Path, gc.ShouldEqual, "/api/client/v2.0/app/webhooks-app-abcde/service/http/incoming_webhook/signed")
+
+		expected := sign(func() []byte {
+			mac := hmac.New(sha256.New, []byte("s3cret"))
+			mac.Write([]byte(`{"a":1}`))
+			return mac.Sum(nil)
+		})
+		u.So(t, request.Options.Header.Get("X-Hook-Signature"), gc.ShouldEqual, "sha256="+expected)
+
+		sent, err := ioutil.ReadAll(request.Options.Body)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(sent), gc.ShouldEqual, `{"a":1}`)
+	})
+
+	t.Run("should sign GitHub webhooks with the supplied secret", func(t *testing.T) {
+		callCommand, _, mockClient := setup(http.StatusOK, "")
+
+		exitCode := callCommand.Run([]string{"--path=../testdata/webhooks_app", "github/push", "--body={}", "--secret=other"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+
+		expected := sign(func() []byte {
+			mac := hmac.New(sha1.New, []byte("other"))
+			mac.Write([]byte("{}"))
+			return mac.Sum(nil)
+		})
+		u.So(t, mockClient.RequestData[0].Options.Header.Get("X-Hub-Signature"), gc.ShouldEqual, "sha1="+expected)
+	})
+
+	t.Run("should pass the secret as a query parameter", func(t *testing.T) {
+		callCommand, _, mockClient := setup(http.StatusOK, "")
+
+		exitCode := callCommand.Run([]string{"--path=../testdata/webhooks_app", "http/query", "--query=page=2"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockClient.RequestData[0].Method, gc.ShouldEqual, http.MethodGet)
+		u.So(t, mockClient.RequestData[0].Path, gc.ShouldEqual, "/api/client/v2.0/app/webhooks-app-abcde/service/http/incoming_webhook/query?page=2&secret=qs")
+	})
+
+	t.Run("should require the secret of webhooks exported with --redact", func(t *testing.T) {
+		appDir := newTempAppDirectory(t)
+		defer os.RemoveAll(appDir)
+
+		webhookDir := filepath.Join(appDir, "services", "http", "incoming_webhooks", "signed")
+		u.So(t, os.MkdirAll(webhookDir, 0700), gc.ShouldBeNil)
+		u.So(t, ioutil.WriteFile(filepath.Join(appDir, "services", "http", "config.json"), []byte(`{"name": "http", "type": "http", "config": {}}`), 0600), gc.ShouldBeNil)
+		u.So(t, ioutil.WriteFile(filepath.Join(webhookDir, "config.json"), []byte(`{
+			"name": "signed",
+			"options": {"httpMethod": "POST", "validationMethod": "VERIFY_PAYLOAD", "secret": "`+utils.RedactedPlaceholderPrefix+`services/http/incoming_webhooks/signed/config.json:options.secret"}
+		}`), 0600), gc.ShouldBeNil)
+		u.So(t, ioutil.WriteFile(filepath.Join(webhookDir, "source.js"), []byte("exports = function() {};"), 0600), gc.ShouldBeNil)
+
+		callCommand, mockUI, mockClient := setup(http.StatusOK, "")
+
+		exitCode := callCommand.Run([]string{"--path=" + appDir, "http/signed", "--body={}"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `the secret of incoming webhook "signed" was redacted on export: supply it with --secret`)
+		u.So(t, mockClient.RequestData, gc.ShouldBeEmpty)
+
+		callCommand, mockUI, mockClient = setup(http.StatusOK, "")
+
+		exitCode = callCommand.Run([]string{"--path=" + appDir, "http/signed", "--body={}", "--secret=s3cret"})
+		u.So(t, exitCode, gc.ShouldEqual, 0)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldBeEmpty)
+		u.So(t, mockClient.RequestData, gc.ShouldHaveLength, 1)
+	})
+
+	t.Run("should fail when the webhook does not respond with a 2xx status", func(t *testing.T) {
+		callCommand, mockUI, mockClient := setup(http.StatusBadRequest, `{"error":"bad"}`)
+
+		exitCode := callCommand.Run([]string{"--path=../testdata/webhooks_app", "http/open", "--method=put"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockClient.RequestData[0].Method, gc.ShouldEqual, http.MethodPut)
+		u.So(t, mockClient.RequestData[0].Options.Header.Get("X-Hook-Signature"), gc.ShouldBeEmpty)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `incoming webhook "open" responded with Bad Request`)
+	})
+
+	t.Run("should fail for unknown webhooks", func(t *testing.T) {
+		callCommand, mockUI, _ := setup(http.StatusOK, "")
+
+		exitCode := callCommand.Run([]string{"--path=../testdata/webhooks_app", "push"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, errWebhookNameRequired.Error())
+
+		callCommand, mockUI, _ = setup(http.StatusOK, "")
+
+		exitCode = callCommand.Run([]string{"--path=../testdata/webhooks_app", "http/missing"})
+		u.So(t, exitCode, gc.ShouldEqual, 1)
+		u.So(t, mockUI.ErrorWriter.String(), gc.ShouldContainSubstring, `incoming webhook "missing" not found`)
+	})
+}
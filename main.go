@@ -27,6 +27,64 @@ func main() {
 		"logout": commands.NewLogoutCommandFactory(ui),
 		"export": commands.NewExportCommandFactory(ui),
 		"import": commands.NewImportCommandFactory(ui),
+		"watch":  commands.NewWatchCommandFactory(ui),
+		"init":   commands.NewInitCommandFactory(ui),
+		"backup": commands.NewBackupCommandFactory(ui),
+		"clone":  commands.NewCloneCommandFactory(ui),
+		"drift":  commands.NewDriftCommandFactory(ui),
+
+		"functions list": commands.NewFunctionsListCommandFactory(ui),
+		"functions show": commands.NewFunctionsShowCommandFactory(ui),
+		"functions new":  commands.NewFunctionsNewCommandFactory(ui),
+		"functions rm":   commands.NewFunctionsRemoveCommandFactory(ui),
+		"functions run":  commands.NewFunctionsRunCommandFactory(ui),
+		"functions test": commands.NewFunctionsTestCommandFactory(ui),
+
+		"values list": commands.NewValuesListCommandFactory(ui),
+		"values get":  commands.NewValuesGetCommandFactory(ui),
+		"values set":  commands.NewValuesSetCommandFactory(ui),
+		"values rm":   commands.NewValuesRemoveCommandFactory(ui),
+
+		"secrets list": commands.NewSecretsListCommandFactory(ui),
+		"secrets set":  commands.NewSecretsSetCommandFactory(ui),
+		"secrets rm":   commands.NewSecretsRemoveCommandFactory(ui),
+
+		"triggers list":    commands.NewTriggersListCommandFactory(ui),
+		"triggers enable":  commands.NewTriggersEnableCommandFactory(ui),
+		"triggers disable": commands.NewTriggersDisableCommandFactory(ui),
+		"triggers resume":  commands.NewTriggersResumeCommandFactory(ui),
+
+		"logs": commands.NewLogsCommandFactory(ui),
+
+		"users list":            commands.NewUsersListCommandFactory(ui),
+		"users show":            commands.NewUsersShowCommandFactory(ui),
+		"users delete":          commands.NewUsersDeleteCommandFactory(ui),
+		"users enable":          commands.NewUsersEnableCommandFactory(ui),
+		"users disable":         commands.NewUsersDisableCommandFactory(ui),
+		"users revoke-sessions": commands.NewUsersRevokeSessionsCommandFactory(ui),
+
+		"auth-providers list":    commands.NewAuthProvidersListCommandFactory(ui),
+		"auth-providers enable":  commands.NewAuthProvidersEnableCommandFactory(ui),
+		"auth-providers disable": commands.NewAuthProvidersDisableCommandFactory(ui),
+		"auth-providers add":     commands.NewAuthProvidersAddCommandFactory(ui),
+
+		"api-keys list":    commands.NewAPIKeysListCommandFactory(ui),
+		"api-keys create":  commands.NewAPIKeysCreateCommandFactory(ui),
+		"api-keys enable":  commands.NewAPIKeysEnableCommandFactory(ui),
+		"api-keys disable": commands.NewAPIKeysDisableCommandFactory(ui),
+		"api-keys delete":  commands.NewAPIKeysDeleteCommandFactory(ui),
+
+		"rules lint": commands.NewRulesLintCommandFactory(ui),
+		"rules eval": commands.NewRulesEvalCommandFactory(ui),
+
+		"webhooks list": commands.NewWebhooksListCommandFactory(ui),
+		"webhooks call": commands.NewWebhooksCallCommandFactory(ui),
+
+		"services list": commands.NewServicesListCommandFactory(ui),
+		"services add":  commands.NewServicesAddCommandFactory(ui),
+		"services rm":   commands.NewServicesRemoveCommandFactory(ui),
+
+		"clusters link": commands.NewClustersLinkCommandFactory(ui),
 	}
 
 	exitStatus, err := c.Run()
@@ -0,0 +1,9 @@
+package models
+
+// APIKey represents an app API key of the api-key auth provider. Key is only returned when the key is created
+type APIKey struct {
+	ID       string `json:"_id"`
+	Name     string `json:"name"`
+	Key      string `json:"key,omitempty"`
+	Disabled bool   `json:"disabled"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// AuthProvider represents an auth provider of a Stitch app as returned by the Admin API
+type AuthProvider struct {
+	ID       string `json:"_id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+}
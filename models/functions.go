@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Function represents a Stitch function as returned by the Admin API
+type Function struct {
+	ID          string                 `json:"_id,omitempty"`
+	Name        string                 `json:"name"`
+	Source      string                 `json:"source,omitempty"`
+	Private     bool                   `json:"private"`
+	CanEvaluate map[string]interface{} `json:"can_evaluate,omitempty"`
+
+	// Config holds the rest of the function's local config.json, such as "run_as_system", which is sent
+	// along with the fields above when the function is created or updated
+	Config map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes the fields of the function over the rest of its config. The "_id" of the config is
+// left out since it may refer to a function of another app
+func (f Function) MarshalJSON() ([]byte, error) {
+	type function Function
+	data, err := json.Marshal(function(f))
+	if err != nil || len(f.Config) == 0 {
+		return data, err
+	}
+
+	fields := map[string]interface{}{}
+	for key, value := range f.Config {
+		if key != "_id" {
+			fields[key] = value
+		}
+	}
+
+	known := map[string]interface{}{}
+	if err := json.Unmarshal(data, &known); err != nil {
+		return nil, err
+	}
+	for key, value := range known {
+		fields[key] = value
+	}
+
+	return json.Marshal(fields)
+}
+
+// FunctionExecution is the outcome of executing a function through the Admin API
+type FunctionExecution struct {
+	Result    json.RawMessage         `json:"result,omitempty"`
+	Logs      []string                `json:"logs,omitempty"`
+	ErrorLogs []string                `json:"error_logs,omitempty"`
+	Error     *FunctionExecutionError `json:"error,omitempty"`
+}
+
+// FunctionExecutionError describes an error thrown by an executed function
+type FunctionExecutionError struct {
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error returns a string representation of the error
+func (fee *FunctionExecutionError) Error() string {
+	if fee.Name == "" {
+		return fee.Message
+	}
+
+	return fmt.Sprintf("%s: %s", fee.Name, fee.Message)
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// LogEntry represents an entry of an app's logs as returned by the Admin API
+type LogEntry struct {
+	ID                    string        `json:"_id"`
+	Type                  string        `json:"type"`
+	Started               time.Time     `json:"started"`
+	Completed             time.Time     `json:"completed"`
+	Error                 string        `json:"error,omitempty"`
+	ErrorCode             string        `json:"error_code,omitempty"`
+	Messages              []interface{} `json:"messages,omitempty"`
+	FunctionName          string        `json:"function_name,omitempty"`
+	EventSubscriptionName string        `json:"event_subscription_name,omitempty"`
+	WebhookName           string        `json:"webhook_name,omitempty"`
+	ServiceName           string        `json:"service_name,omitempty"`
+	AuthProviderType      string        `json:"auth_provider_type,omitempty"`
+	RequestURL            string        `json:"request_url,omitempty"`
+	RequestMethod         string        `json:"request_method,omitempty"`
+	UserID                string        `json:"user_id,omitempty"`
+}
+
+// LogsQuery filters the logs returned by the Admin API. EndDate and Skip form the cursor of the page to fetch
+type LogsQuery struct {
+	Type       string
+	ErrorsOnly bool
+	StartDate  time.Time
+	EndDate    time.Time
+	Skip       int
+	Limit      int
+}
+
+// LogsPage is a page of log entries, newest first. NextEndDate is set when more entries are available and,
+// along with NextSkip, is the cursor of the following page
+type LogsPage struct {
+	Logs        []*LogEntry `json:"logs"`
+	NextEndDate *time.Time  `json:"nextEndDate,omitempty"`
+	NextSkip    int         `json:"nextSkip,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Secret represents a Stitch secret. Its value is write-only and never returned by the Admin API
+type Secret struct {
+	ID    string `json:"_id,omitempty"`
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// Trigger represents a Stitch trigger as returned by the Admin API
+type Trigger struct {
+	ID           string                 `json:"_id,omitempty"`
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	FunctionID   string                 `json:"function_id,omitempty"`
+	FunctionName string                 `json:"function_name,omitempty"`
+	Config       map[string]interface{} `json:"config,omitempty"`
+	Disabled     bool                   `json:"disabled"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// AppUser represents a user of a Stitch app, i.e. someone who logs in through one of its auth providers
+type AppUser struct {
+	ID                     string                 `json:"_id"`
+	Type                   string                 `json:"type,omitempty"`
+	Identities             []AppUserIdentity      `json:"identities,omitempty"`
+	Data                   map[string]interface{} `json:"data,omitempty"`
+	Disabled               bool                   `json:"disabled"`
+	CreationDate           int64                  `json:"creation_date,omitempty"`
+	LastAuthenticationDate int64                  `json:"last_authentication_date,omitempty"`
+}
+
+// AppUserIdentity links an AppUser to one of the auth providers they log in with
+type AppUserIdentity struct {
+	ID           string `json:"id"`
+	ProviderType string `json:"provider_type"`
+	ProviderID   string `json:"provider_id,omitempty"`
+}
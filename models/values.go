@@ -0,0 +1,9 @@
+package models
+
+// Value represents a Stitch value as returned by the Admin API
+type Value struct {
+	ID      string      `json:"_id,omitempty"`
+	Name    string      `json:"name"`
+	Value   interface{} `json:"value"`
+	Private bool        `json:"private"`
+}
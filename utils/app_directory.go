@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// AppConfigVersion is the config_version written to the stitch.json of newly initialized app directories
+const AppConfigVersion = 20180301
+
+// AppEntityDirectories are the directories of an app directory holding its entities
+var AppEntityDirectories = []string{authProvidersName, functionsName, servicesName, triggersName, valuesName}
+
+// EnsureEmptyDirectory returns an error if the directory at path exists and has any contents
+func EnsureEmptyDirectory(path string) error {
+	entries, err := ioutil.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(entries) != 0 {
+		return fmt.Errorf("directory %q already exists and is not empty", path)
+	}
+
+	return nil
+}
+
+// InitAppDirectory writes a stitch.json for an app with the given name to appPath, along with empty
+// entity directories
+func InitAppDirectory(appPath, name string) error {
+	for _, dir := range AppEntityDirectories {
+		if err := os.MkdirAll(filepath.Join(appPath, dir), 0700); err != nil {
+			return fmt.Errorf("failed to create directory %q: %s", filepath.Join(appPath, dir), err)
+		}
+	}
+
+	return WriteJSONFile(filepath.Join(appPath, appConfigName+jsonExt), map[string]interface{}{
+		"config_version": AppConfigVersion,
+		"name":           name,
+		"security": map[string]interface{}{
+			"allowed_request_origins": []interface{}{},
+		},
+	})
+}
+
+// CopyAppDirectory copies the app directory at src to dest, creating any missing entity directories.
+// Version control metadata is not copied
+func CopyAppDirectory(src, dest string) error {
+	if _, err := os.Stat(filepath.Join(src, appConfigName+jsonExt)); os.IsNotExist(err) {
+		return fmt.Errorf("%s is not an app directory: %s not found", src, appConfigName+jsonExt)
+	}
+
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dest, rel), 0700)
+		}
+
+		return copyFile(path, filepath.Join(dest, rel), info.Mode())
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range AppEntityDirectories {
+		if err := os.MkdirAll(filepath.Join(dest, dir), 0700); err != nil {
+			return fmt.Errorf("failed to create directory %q: %s", filepath.Join(dest, dir), err)
+		}
+	}
+
+	return nil
+}
+
+func copyFile(src, dest string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create file %q: %s", dest, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to copy file %q: %s", src, err)
+	}
+
+	return nil
+}
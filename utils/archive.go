@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	zipMagic  = []byte("PK")
+	gzipMagic = []byte{0x1f, 0x8b}
+
+	tarGzExts = []string{".tar.gz", ".tgz"}
+)
+
+// UnmarshalFromArchive unmarshals a Stitch app from the zip or tar.gz archive at the given path into a
+// map[string]interface{}, without unpacking it to disk. The app may be at the root of the archive or within
+// its single top-level directory
+func UnmarshalFromArchive(archivePath string) (map[string]interface{}, error) {
+	b, err := ioutil.ReadFile(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var fs FileSystem
+	switch {
+	case bytes.HasPrefix(b, zipMagic):
+		fs, err = readZipArchive(b)
+	case bytes.HasPrefix(b, gzipMagic):
+		fs, err = readTarGzArchive(b)
+	default:
+		return nil, fmt.Errorf("failed to read archive %q: must be a zip or tar.gz file", archivePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %q: %s", archivePath, err)
+	}
+
+	return UnmarshalFromFileSystem(fs, archiveAppRoot(fs))
+}
+
+// WriteArchive saves the exported zip data to the file at dest. The zip is re-packed as a tar.gz archive if
+// dest ends in ".tar.gz" or ".tgz", and saved as is otherwise
+func WriteArchive(dest string, zipData io.Reader, overwrite bool) error {
+	if _, err := os.Stat(dest); !overwrite && err == nil {
+		return fmt.Errorf("failed to create archive %q: file already exists", dest)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create archive %q: %s", dest, err)
+	}
+
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive %q: %s", dest, err)
+	}
+	defer f.Close()
+
+	if !isTarGzPath(dest) {
+		if _, err := io.Copy(f, zipData); err != nil {
+			return fmt.Errorf("failed to write archive %q: %s", dest, err)
+		}
+		return nil
+	}
+
+	if err := repackZipAsTarGz(f, zipData); err != nil {
+		return fmt.Errorf("failed to write archive %q: %s", dest, err)
+	}
+	return nil
+}
+
+func isTarGzPath(name string) bool {
+	for _, ext := range tarGzExts {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func repackZipAsTarGz(w io.Writer, zipData io.Reader) error {
+	b, err := ioutil.ReadAll(zipData)
+	if err != nil {
+		return err
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, zipFile := range r.File {
+		header := &tar.Header{
+			Name:    zipFile.Name,
+			Mode:    int64(zipFile.Mode().Perm()),
+			ModTime: zipFile.Modified,
+		}
+
+		var contents []byte
+		if zipFile.FileInfo().IsDir() {
+			header.Typeflag = tar.TypeDir
+		} else {
+			if contents, err = readZipFile(zipFile); err != nil {
+				return err
+			}
+			header.Typeflag = tar.TypeReg
+			header.Size = int64(len(contents))
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(contents); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func readZipArchive(b []byte) (*ZipFileSystem, error) {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewZipFileSystem(r), nil
+}
+
+func readTarGzArchive(b []byte) (*MemFileSystem, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	fs := NewMemFileSystem()
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			fs.MkdirAll(header.Name, os.ModePerm)
+		case tar.TypeReg, tar.TypeRegA:
+			contents, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			fs.WriteFile(header.Name, contents, os.FileMode(header.Mode).Perm())
+		}
+	}
+
+	return fs, nil
+}
+
+// archiveAppRoot returns the directory of fs holding the app: its root, unless the app config is only
+// found within its single top-level directory
+func archiveAppRoot(fs FileSystem) string {
+	if _, err := fs.Stat(appConfigName + jsonExt); err == nil {
+		return "."
+	}
+
+	infos, err := fs.ReadDir(".")
+	if err != nil || len(infos) != 1 || !infos[0].IsDir() {
+		return "."
+	}
+
+	if _, err := fs.Stat(path.Join(infos[0].Name(), appConfigName+jsonExt)); err != nil {
+		return "."
+	}
+	return infos[0].Name()
+}
@@ -0,0 +1,139 @@
+package utils_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+// nestFiles returns the given files moved into the directory dir
+func nestFiles(files map[string]string, dir string) map[string]string {
+	nested := make(map[string]string, len(files))
+	for name, contents := range files {
+		nested[dir+"/"+name] = contents
+	}
+	return nested
+}
+
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, name := range names {
+		u.So(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}), gc.ShouldBeNil)
+		_, err := tw.Write([]byte(files[name]))
+		u.So(t, err, gc.ShouldBeNil)
+	}
+	u.So(t, tw.Close(), gc.ShouldBeNil)
+	u.So(t, gw.Close(), gc.ShouldBeNil)
+	return buf.Bytes()
+}
+
+func writeTempFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	u.So(t, ioutil.WriteFile(path, data, 0600), gc.ShouldBeNil)
+	return path
+}
+
+func TestUnmarshalFromArchive(t *testing.T) {
+	expected, err := utils.UnmarshalFromDir("../testdata/full_app")
+	u.So(t, err, gc.ShouldBeNil)
+
+	dir, err := ioutil.TempDir("", "stitch-archive")
+	u.So(t, err, gc.ShouldBeNil)
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		description string
+		name        string
+		data        []byte
+	}{
+		{
+			description: "should load an app from a zip archive",
+			name:        "app.zip",
+			data:        u.NewZip(u.ReadDirectoryFiles("../testdata/full_app")),
+		},
+		{
+			description: "should load an app from a tar.gz archive",
+			name:        "app.tar.gz",
+			data:        buildTarGz(t, u.ReadDirectoryFiles("../testdata/full_app")),
+		},
+		{
+			description: "should load an app from the single top-level directory of an archive",
+			name:        "nested.tgz",
+			data:        buildTarGz(t, nestFiles(u.ReadDirectoryFiles("../testdata/full_app"), "full-app")),
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			app, err := utils.UnmarshalFromArchive(writeTempFile(t, dir, tc.name, tc.data))
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, app, gc.ShouldResemble, expected)
+		})
+	}
+
+	t.Run("should fail on files that are not archives", func(t *testing.T) {
+		_, err := utils.UnmarshalFromArchive(writeTempFile(t, dir, "app.txt", []byte("not an archive")))
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, "must be a zip or tar.gz file")
+	})
+
+	t.Run("should fail on archives without an app", func(t *testing.T) {
+		_, err := utils.UnmarshalFromArchive(writeTempFile(t, dir, "empty.zip", u.NewZip(map[string]string{"README": "hi"})))
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, "stitch.json")
+	})
+}
+
+func TestWriteArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stitch-archive")
+	u.So(t, err, gc.ShouldBeNil)
+	defer os.RemoveAll(dir)
+
+	exported := u.NewZip(u.ReadDirectoryFiles("../testdata/full_app"))
+	expected, err := utils.UnmarshalFromDir("../testdata/full_app")
+	u.So(t, err, gc.ShouldBeNil)
+
+	t.Run("should save zip data as is", func(t *testing.T) {
+		dest := filepath.Join(dir, "artifacts", "app.zip")
+		u.So(t, utils.WriteArchive(dest, bytes.NewReader(exported), false), gc.ShouldBeNil)
+
+		written, err := ioutil.ReadFile(dest)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, written, gc.ShouldResemble, exported)
+	})
+
+	t.Run("should re-pack zip data as a tar.gz archive", func(t *testing.T) {
+		dest := filepath.Join(dir, "app.tar.gz")
+		u.So(t, utils.WriteArchive(dest, bytes.NewReader(exported), false), gc.ShouldBeNil)
+
+		_, err := zip.OpenReader(dest)
+		u.So(t, err, gc.ShouldNotBeNil)
+
+		app, err := utils.UnmarshalFromArchive(dest)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app, gc.ShouldResemble, expected)
+	})
+
+	t.Run("should not overwrite an existing file", func(t *testing.T) {
+		dest := writeTempFile(t, dir, "existing.zip", []byte("existing"))
+		err := utils.WriteArchive(dest, bytes.NewReader(exported), false)
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, "already exists")
+	})
+}
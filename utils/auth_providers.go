@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LocalAuthProvider is an auth provider stored within an app directory as auth_providers/<name>.json
+type LocalAuthProvider struct {
+	Path   string
+	Config map[string]interface{}
+}
+
+// Name returns the name of the auth provider
+func (lap *LocalAuthProvider) Name() string {
+	name, _ := lap.Config["name"].(string)
+	return name
+}
+
+// Type returns the type of the auth provider, such as anon-user or oauth2-google
+func (lap *LocalAuthProvider) Type() string {
+	providerType, _ := lap.Config["type"].(string)
+	return providerType
+}
+
+// Disabled returns whether the auth provider is disabled
+func (lap *LocalAuthProvider) Disabled() bool {
+	disabled, _ := lap.Config["disabled"].(bool)
+	return disabled
+}
+
+// TemplateField is a setting of an auth provider or service that has to be supplied when adding it
+type TemplateField struct {
+	Name        string
+	Description string
+	Default     string
+}
+
+// AuthProviderTemplate describes how to scaffold the config of an auth provider type. Fields are stored
+// within the config of the provider while SecretFields are stored in secrets.json
+type AuthProviderTemplate struct {
+	Type         string
+	Config       map[string]interface{}
+	Fields       []TemplateField
+	SecretFields []TemplateField
+	OAuth        bool
+}
+
+// AuthProviderTemplates lists the auth provider types that can be scaffolded, keyed by type
+var AuthProviderTemplates = map[string]AuthProviderTemplate{
+	"anon-user": {
+		Type: "anon-user",
+	},
+	"api-key": {
+		Type: "api-key",
+	},
+	"local-userpass": {
+		Type: "local-userpass",
+		Config: map[string]interface{}{
+			"autoConfirm":          false,
+			"confirmEmailSubject":  "Confirm your email address",
+			"resetPasswordSubject": "Reset your password",
+		},
+		Fields: []TemplateField{
+			{Name: "emailConfirmationUrl", Description: "URL users are sent to in order to confirm their email address"},
+			{Name: "resetPasswordUrl", Description: "URL users are sent to in order to reset their password"},
+		},
+	},
+	"custom-token": {
+		Type: "custom-token",
+		Config: map[string]interface{}{
+			"signingAlgorithm": "HS256",
+		},
+		SecretFields: []TemplateField{
+			{Name: "signingKey", Description: "Key used to verify the signature of tokens"},
+		},
+	},
+	"oauth2-google": {
+		Type: "oauth2-google",
+		Fields: []TemplateField{
+			{Name: "clientId", Description: "Google OAuth client ID"},
+		},
+		SecretFields: []TemplateField{
+			{Name: "clientSecret", Description: "Google OAuth client secret"},
+		},
+		OAuth: true,
+	},
+	"oauth2-facebook": {
+		Type: "oauth2-facebook",
+		Fields: []TemplateField{
+			{Name: "clientId", Description: "Facebook app ID"},
+		},
+		SecretFields: []TemplateField{
+			{Name: "clientSecret", Description: "Facebook app secret"},
+		},
+		OAuth: true,
+	},
+}
+
+// AuthProviderTypes returns the auth provider types that can be scaffolded, sorted
+func AuthProviderTypes() []string {
+	types := make([]string, 0, len(AuthProviderTemplates))
+	for providerType := range AuthProviderTemplates {
+		types = append(types, providerType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// NewLocalAuthProvider builds an auth provider of the template's type within the app directory at appPath,
+// with the given values of the template's fields
+func (apt AuthProviderTemplate) NewLocalAuthProvider(appPath string, fields map[string]string) *LocalAuthProvider {
+	config := map[string]interface{}{}
+	for key, value := range apt.Config {
+		config[key] = value
+	}
+	for key, value := range fields {
+		config[key] = value
+	}
+
+	doc := map[string]interface{}{
+		"name":     apt.Type,
+		"type":     apt.Type,
+		"config":   config,
+		"disabled": false,
+	}
+
+	if apt.OAuth {
+		doc["redirect_uris"] = []string{}
+		doc["domain_restrictions"] = []string{}
+		doc["metadata_fields"] = []interface{}{}
+	}
+
+	return &LocalAuthProvider{
+		Path:   filepath.Join(AuthProvidersDirectory(appPath), apt.Type+jsonExt),
+		Config: doc,
+	}
+}
+
+// AuthProvidersDirectory returns the path of the auth providers directory within the app directory at appPath
+func AuthProvidersDirectory(appPath string) string {
+	return filepath.Join(appPath, authProvidersName)
+}
+
+// ReadLocalAuthProviders loads every auth provider within the app directory at appPath, sorted by name
+func ReadLocalAuthProviders(appPath string) ([]*LocalAuthProvider, error) {
+	return ReadLocalAuthProvidersFromFileSystem(OSFileSystem{}, appPath)
+}
+
+// ReadLocalAuthProvidersFromFileSystem loads every auth provider within the app directory at appPath of fs,
+// sorted by name
+func ReadLocalAuthProvidersFromFileSystem(fs FileSystem, appPath string) ([]*LocalAuthProvider, error) {
+	configs, err := readJSONConfigs(fs, AuthProvidersDirectory(appPath))
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]*LocalAuthProvider, 0, len(configs))
+	for path, config := range configs {
+		providers = append(providers, &LocalAuthProvider{Path: path, Config: config})
+	}
+
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name() < providers[j].Name() })
+
+	return providers, nil
+}
+
+// ReadLocalAuthProvider loads the auth provider with the given name from the app directory at appPath
+func ReadLocalAuthProvider(appPath, name string) (*LocalAuthProvider, error) {
+	providers, err := ReadLocalAuthProviders(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, provider := range providers {
+		if provider.Name() == name {
+			return provider, nil
+		}
+	}
+
+	return nil, fmt.Errorf("auth provider %q not found in %s", name, AuthProvidersDirectory(appPath))
+}
+
+// WriteLocalAuthProvider writes the config of the auth provider back to its file
+func WriteLocalAuthProvider(provider *LocalAuthProvider) error {
+	return WriteJSONFile(provider.Path, provider.Config)
+}
+
+// SecretsFilePath returns the path of the secrets file within the app directory at appPath
+func SecretsFilePath(appPath string) string {
+	return filepath.Join(appPath, secretsName+jsonExt)
+}
+
+// WriteLocalSecrets merges the given secrets into secrets.json of the app directory at appPath under
+// <section>.<name>, e.g. auth_providers.oauth2-google.clientSecret, creating the file if needed
+func WriteLocalSecrets(appPath, section, name string, secrets map[string]string) error {
+	path := SecretsFilePath(appPath)
+
+	doc := map[string]interface{}{}
+	if _, err := os.Stat(path); err == nil {
+		if err := readAndUnmarshalJSONInto(path, &doc); err != nil {
+			return err
+		}
+	}
+
+	sectionSecrets, _ := doc[section].(map[string]interface{})
+	if sectionSecrets == nil {
+		sectionSecrets = map[string]interface{}{}
+		doc[section] = sectionSecrets
+	}
+
+	entry, _ := sectionSecrets[name].(map[string]interface{})
+	if entry == nil {
+		entry = map[string]interface{}{}
+		sectionSecrets[name] = entry
+	}
+
+	for key, value := range secrets {
+		entry[key] = value
+	}
+
+	return WriteJSONFile(path, doc)
+}
+
+// RemoveLocalSecrets removes the secrets stored under <section>.<name> from secrets.json of the app
+// directory at appPath, if any
+func RemoveLocalSecrets(appPath, section, name string) error {
+	path := SecretsFilePath(appPath)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	doc := map[string]interface{}{}
+	if err := readAndUnmarshalJSONInto(path, &doc); err != nil {
+		return err
+	}
+
+	sectionSecrets, _ := doc[section].(map[string]interface{})
+	if _, ok := sectionSecrets[name]; !ok {
+		return nil
+	}
+	delete(sectionSecrets, name)
+
+	return WriteJSONFile(path, doc)
+}
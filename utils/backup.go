@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// BackupManifestFileName is the name of the file describing the apps within a backup directory
+	BackupManifestFileName = "manifest.json"
+
+	// BackupTimestampFormat is the format of the names of backup directories
+	BackupTimestampFormat = "20060102T150405Z"
+)
+
+// BackupManifest describes the apps exported within a backup directory
+type BackupManifest struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Apps      []BackupAppRecord `json:"apps"`
+}
+
+// BackupAppRecord describes a single exported app within a backup. Checksum is the hex-encoded SHA-256
+// of the exported zip
+type BackupAppRecord struct {
+	GroupID     string `json:"group_id"`
+	AppID       string `json:"app_id"`
+	ClientAppID string `json:"client_app_id"`
+	Name        string `json:"name"`
+	Path        string `json:"path,omitempty"`
+	Checksum    string `json:"sha256,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Checksum returns the hex-encoded SHA-256 of data
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// BackupDirectoryName returns the name of the directory of a backup created at t
+func BackupDirectoryName(t time.Time) string {
+	return t.UTC().Format(BackupTimestampFormat)
+}
+
+// ListBackups returns the paths of the backup directories within dest, oldest first. Only directories
+// named after a timestamp and containing a manifest are considered backups
+func ListBackups(dest string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dest)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if _, err := time.Parse(BackupTimestampFormat, entry.Name()); err != nil {
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(dest, entry.Name(), BackupManifestFileName)); err != nil {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dest, name)
+	}
+	return paths, nil
+}
+
+// PruneBackups removes the backups within dest beyond the keep most recent ones, as well as those created
+// more than maxAge before now. A keep or maxAge of zero disables the respective rule. It returns the paths
+// of the removed backups
+func PruneBackups(dest string, keep int, maxAge time.Duration, now time.Time) ([]string, error) {
+	backups, err := ListBackups(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for i, path := range backups {
+		expired := false
+		if keep > 0 && i < len(backups)-keep {
+			expired = true
+		}
+
+		if maxAge > 0 {
+			createdAt, err := time.Parse(BackupTimestampFormat, filepath.Base(path))
+			if err == nil && now.Sub(createdAt) > maxAge {
+				expired = true
+			}
+		}
+
+		if !expired {
+			continue
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,90 @@
+package utils
+
+const atlasServiceType = "mongodb-atlas"
+
+var entityIDFields = []string{"id", "_id"}
+
+// StripEntityIDs removes the IDs of the entities within the provided app data so that it can be imported
+// into another app, where entities are matched by name instead
+func StripEntityIDs(app map[string]interface{}) {
+	for _, section := range []string{valuesName, authProvidersName, triggersName} {
+		for _, entity := range entityList(app[section]) {
+			stripIDs(entity)
+		}
+	}
+
+	for _, function := range entityList(app[functionsName]) {
+		stripIDs(entityMap(function[configName]))
+	}
+
+	for _, service := range entityList(app[servicesName]) {
+		stripIDs(entityMap(service[configName]))
+
+		for _, rule := range entityList(service[rulesName]) {
+			stripIDs(rule)
+		}
+
+		for _, webhook := range entityList(service[incomingWebhooksName]) {
+			stripIDs(entityMap(webhook[configName]))
+		}
+	}
+}
+
+// ClusterNames returns the names of the clusters linked by the mongodb-atlas services within the provided
+// app data, keyed by service name
+func ClusterNames(app map[string]interface{}) map[string]string {
+	clusters := map[string]string{}
+	for _, service := range entityList(app[servicesName]) {
+		config := entityMap(service[configName])
+		if config == nil || config["type"] != atlasServiceType {
+			continue
+		}
+
+		name, _ := config["name"].(string)
+		if cluster, ok := entityMap(config[configName])["clusterName"].(string); ok {
+			clusters[name] = cluster
+		}
+	}
+	return clusters
+}
+
+// RemapClusterNames points the mongodb-atlas services within the provided app data at other clusters.
+// clusters maps the name of each cluster to replace to the name of its replacement
+func RemapClusterNames(app map[string]interface{}, clusters map[string]string) {
+	for _, service := range entityList(app[servicesName]) {
+		config := entityMap(service[configName])
+		if config == nil || config["type"] != atlasServiceType {
+			continue
+		}
+
+		serviceConfig := entityMap(config[configName])
+		if cluster, ok := serviceConfig["clusterName"].(string); ok {
+			if replacement, ok := clusters[cluster]; ok {
+				serviceConfig["clusterName"] = replacement
+			}
+		}
+	}
+}
+
+func stripIDs(entity map[string]interface{}) {
+	for _, field := range entityIDFields {
+		delete(entity, field)
+	}
+}
+
+func entityList(node interface{}) []map[string]interface{} {
+	items, _ := node.([]interface{})
+
+	entities := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if entity := entityMap(item); entity != nil {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+func entityMap(node interface{}) map[string]interface{} {
+	entity, _ := node.(map[string]interface{})
+	return entity
+}
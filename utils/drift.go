@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Drift statuses describe how an entity of the deployed app differs from the local app directory
+const (
+	DriftAdded   = "added"
+	DriftRemoved = "removed"
+	DriftChanged = "changed"
+)
+
+// Kinds of app entities compared by a drift report, in report order
+const (
+	DriftKindApp             = "app"
+	DriftKindValue           = "value"
+	DriftKindAuthProvider    = "auth_provider"
+	DriftKindFunction        = "function"
+	DriftKindTrigger         = "trigger"
+	DriftKindService         = "service"
+	DriftKindRule            = "rule"
+	DriftKindIncomingWebhook = "incoming_webhook"
+)
+
+var (
+	driftKindOrder = map[string]int{
+		DriftKindApp:             0,
+		DriftKindValue:           1,
+		DriftKindAuthProvider:    2,
+		DriftKindFunction:        3,
+		DriftKindTrigger:         4,
+		DriftKindService:         5,
+		DriftKindRule:            6,
+		DriftKindIncomingWebhook: 7,
+	}
+
+	// driftIgnoredFields are not compared since they differ between apps and are absent from new entities
+	driftIgnoredFields = map[string]bool{"id": true, "_id": true, "app_id": true}
+
+	jsonPathIdentifierPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// FieldDrift is a single difference within an entity, identified by its JSON path. A nil Local or Deployed
+// means the field is absent on that side. The values of fields that a redacted export would strip, such as
+// private values and secret-like service config fields, are withheld and the field is marked Redacted
+type FieldDrift struct {
+	Path     string      `json:"path"`
+	Local    interface{} `json:"local,omitempty"`
+	Deployed interface{} `json:"deployed,omitempty"`
+	Redacted bool        `json:"redacted,omitempty"`
+}
+
+// EntityDrift describes how an entity of the deployed app differs from the local app directory. Added
+// entities only exist in the deployed app and removed ones only exist locally
+type EntityDrift struct {
+	Kind   string       `json:"kind"`
+	Name   string       `json:"name"`
+	Status string       `json:"status"`
+	Fields []FieldDrift `json:"fields,omitempty"`
+}
+
+// DriftReport lists the entities of a deployed app that differ from its local app directory
+type DriftReport struct {
+	Entities []EntityDrift `json:"entities"`
+}
+
+// HasDrift returns whether any entity differs
+func (dr *DriftReport) HasDrift() bool {
+	return len(dr.Entities) != 0
+}
+
+// driftMask describes whether the values of differing fields may be reported
+type driftMask int
+
+const (
+	// driftMaskNone reports the values of differing fields
+	driftMaskNone driftMask = iota
+	// driftMaskSecretLike withholds the values of fields with secret-like names and everything beneath them
+	driftMaskSecretLike
+	// driftMaskAll withholds the values of every differing field
+	driftMaskAll
+)
+
+type driftEntity struct {
+	kind string
+	name string
+	doc  interface{}
+
+	// masks holds the masks applying beneath JSON paths of the entity, matching what RedactAppZip strips
+	masks map[string]driftMask
+}
+
+// CompareApps builds a drift report between the app data of a local app directory and of the deployed
+// app, as loaded by UnmarshalFromDir. Secrets are not compared since they are never exported
+func CompareApps(local, deployed map[string]interface{}) *DriftReport {
+	localEntities := driftEntities(local)
+	deployedEntities := driftEntities(deployed)
+
+	keys := map[string]driftEntity{}
+	for key, entity := range localEntities {
+		keys[key] = entity
+	}
+	for key, entity := range deployedEntities {
+		keys[key] = entity
+	}
+
+	report := &DriftReport{Entities: []EntityDrift{}}
+	for key, entity := range keys {
+		localEntity, inLocal := localEntities[key]
+		deployedEntity, inDeployed := deployedEntities[key]
+
+		drift := EntityDrift{Kind: entity.kind, Name: entity.name}
+		switch {
+		case !inLocal:
+			drift.Status = DriftAdded
+		case !inDeployed:
+			drift.Status = DriftRemoved
+		default:
+			masks := map[string]driftMask{}
+			for _, entityMasks := range []map[string]driftMask{localEntity.masks, deployedEntity.masks} {
+				for path, mask := range entityMasks {
+					if mask > masks[path] {
+						masks[path] = mask
+					}
+				}
+			}
+			drift.Fields = compareJSON("$", localEntity.doc, deployedEntity.doc, driftMaskNone, masks)
+			if len(drift.Fields) == 0 {
+				continue
+			}
+			drift.Status = DriftChanged
+		}
+
+		report.Entities = append(report.Entities, drift)
+	}
+
+	sort.Slice(report.Entities, func(i, j int) bool {
+		a, b := report.Entities[i], report.Entities[j]
+		if a.Kind != b.Kind {
+			return driftKindOrder[a.Kind] < driftKindOrder[b.Kind]
+		}
+		return a.Name < b.Name
+	})
+
+	return report
+}
+
+// driftEntities returns the entities of the app data keyed by kind and name
+func driftEntities(app map[string]interface{}) map[string]driftEntity {
+	entities := map[string]driftEntity{}
+	add := func(kind, name string, doc interface{}, masks map[string]driftMask) {
+		entities[kind+"/"+name] = driftEntity{kind, name, doc, masks}
+	}
+
+	settings := map[string]interface{}{}
+	for key, value := range app {
+		switch key {
+		case valuesName, authProvidersName, functionsName, triggersName, servicesName, secretsName:
+			continue
+		}
+		settings[key] = value
+	}
+	add(DriftKindApp, appConfigName+jsonExt, settings, nil)
+
+	for _, value := range entityList(app[valuesName]) {
+		var masks map[string]driftMask
+		if private, _ := value["private"].(bool); private {
+			masks = map[string]driftMask{"$.value": driftMaskAll}
+		}
+		add(DriftKindValue, entityName(value), value, masks)
+	}
+
+	for _, provider := range entityList(app[authProvidersName]) {
+		add(DriftKindAuthProvider, entityName(provider), provider, nil)
+	}
+
+	for _, function := range entityList(app[functionsName]) {
+		add(DriftKindFunction, entityName(entityMap(function[configName])), function, nil)
+	}
+
+	for _, trigger := range entityList(app[triggersName]) {
+		add(DriftKindTrigger, entityName(trigger), trigger, nil)
+	}
+
+	for _, service := range entityList(app[servicesName]) {
+		serviceName := entityName(entityMap(service[configName]))
+		add(DriftKindService, serviceName, service[configName], map[string]driftMask{"$.config": driftMaskSecretLike})
+
+		for _, rule := range entityList(service[rulesName]) {
+			name, ok := rule["namespace"].(string)
+			if !ok {
+				name = entityName(rule)
+			}
+			add(DriftKindRule, serviceName+"/"+name, rule, nil)
+		}
+
+		for _, webhook := range entityList(service[incomingWebhooksName]) {
+			add(
+				DriftKindIncomingWebhook,
+				serviceName+"/"+entityName(entityMap(webhook[configName])),
+				webhook,
+				map[string]driftMask{"$.config.options": driftMaskSecretLike},
+			)
+		}
+	}
+
+	return entities
+}
+
+func entityName(entity map[string]interface{}) string {
+	name, _ := entity["name"].(string)
+	return name
+}
+
+// compareJSON returns the differences between two JSON documents, with paths relative to path. mask applies
+// to the documents and masks to the fields beneath them, by path. Local redaction placeholders are not
+// compared since the value they stand for is unknown
+func compareJSON(path string, local, deployed interface{}, mask driftMask, masks map[string]driftMask) []FieldDrift {
+	if pathMask := masks[path]; pathMask > mask {
+		mask = pathMask
+	}
+
+	if s, ok := local.(string); ok && strings.HasPrefix(s, RedactedPlaceholderPrefix) {
+		return nil
+	}
+
+	localMap, localIsMap := local.(map[string]interface{})
+	deployedMap, deployedIsMap := deployed.(map[string]interface{})
+	if localIsMap && deployedIsMap {
+		keys := map[string]struct{}{}
+		for key := range localMap {
+			keys[key] = struct{}{}
+		}
+		for key := range deployedMap {
+			keys[key] = struct{}{}
+		}
+
+		fields := []FieldDrift{}
+		for _, key := range sortedSet(keys) {
+			if driftIgnoredFields[key] {
+				continue
+			}
+			childMask := mask
+			if mask == driftMaskSecretLike && isSecretLikeFieldName(key) {
+				childMask = driftMaskAll
+			}
+			fields = append(fields, compareJSON(jsonPathKey(path, key), localMap[key], deployedMap[key], childMask, masks)...)
+		}
+		return fields
+	}
+
+	localList, localIsList := local.([]interface{})
+	deployedList, deployedIsList := deployed.([]interface{})
+	if localIsList && deployedIsList {
+		fields := []FieldDrift{}
+		for i := 0; i < len(localList) || i < len(deployedList); i++ {
+			var localItem, deployedItem interface{}
+			if i < len(localList) {
+				localItem = localList[i]
+			}
+			if i < len(deployedList) {
+				deployedItem = deployedList[i]
+			}
+			fields = append(fields, compareJSON(fmt.Sprintf("%s[%d]", path, i), localItem, deployedItem, mask, masks)...)
+		}
+		return fields
+	}
+
+	if reflect.DeepEqual(local, deployed) {
+		return nil
+	}
+
+	if mask == driftMaskAll {
+		return []FieldDrift{{Path: path, Redacted: true}}
+	}
+	return []FieldDrift{{Path: path, Local: local, Deployed: deployed}}
+}
+
+func jsonPathKey(path, key string) string {
+	if jsonPathIdentifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+
+	quoted, _ := json.Marshal(key)
+	return fmt.Sprintf("%s[%s]", path, quoted)
+}
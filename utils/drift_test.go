@@ -0,0 +1,138 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompareApps(t *testing.T) {
+	t.Run("should report no drift between identical apps", func(t *testing.T) {
+		local, err := utils.UnmarshalFromDir("../testdata/full_app")
+		u.So(t, err, gc.ShouldBeNil)
+		deployed, err := utils.UnmarshalFromDir("../testdata/full_app")
+		u.So(t, err, gc.ShouldBeNil)
+
+		report := utils.CompareApps(local, deployed)
+		u.So(t, report.HasDrift(), gc.ShouldBeFalse)
+		u.So(t, report.Entities, gc.ShouldBeEmpty)
+	})
+
+	t.Run("should report added, removed and changed entities with the paths of changed fields", func(t *testing.T) {
+		local := map[string]interface{}{
+			"name":     "my-app",
+			"security": map[string]interface{}{"allowed_request_origins": []interface{}{"http://a.com"}},
+			"values": []interface{}{
+				map[string]interface{}{"id": "1", "name": "a", "value": "x"},
+				map[string]interface{}{"name": "b", "value": "y"},
+			},
+			"services": []interface{}{
+				map[string]interface{}{
+					"config": map[string]interface{}{"name": "mongodb-atlas", "type": "mongodb-atlas", "config": map[string]interface{}{"clusterName": "Cluster0"}},
+					"rules": []interface{}{
+						map[string]interface{}{"namespace": "db.items", "roles": []interface{}{map[string]interface{}{"name": "owner"}}},
+					},
+				},
+			},
+		}
+		deployed := map[string]interface{}{
+			"app_id":   "my-app-abcde",
+			"name":     "my-app",
+			"security": map[string]interface{}{"allowed_request_origins": []interface{}{"http://a.com", "http://b.com"}},
+			"values": []interface{}{
+				map[string]interface{}{"id": "2", "name": "a", "value": "x"},
+				map[string]interface{}{"name": "c", "value": "z"},
+			},
+			"services": []interface{}{
+				map[string]interface{}{
+					"config": map[string]interface{}{"name": "mongodb-atlas", "type": "mongodb-atlas", "config": map[string]interface{}{"clusterName": "Cluster1"}},
+					"rules": []interface{}{
+						map[string]interface{}{"namespace": "db.items", "roles": []interface{}{map[string]interface{}{"name": "owner", "apply when": true}}},
+					},
+				},
+			},
+		}
+
+		report := utils.CompareApps(local, deployed)
+		u.So(t, report.HasDrift(), gc.ShouldBeTrue)
+		u.So(t, report.Entities, gc.ShouldResemble, []utils.EntityDrift{
+			{
+				Kind:   utils.DriftKindApp,
+				Name:   "stitch.json",
+				Status: utils.DriftChanged,
+				Fields: []utils.FieldDrift{{Path: "$.security.allowed_request_origins[1]", Deployed: "http://b.com"}},
+			},
+			{Kind: utils.DriftKindValue, Name: "b", Status: utils.DriftRemoved},
+			{Kind: utils.DriftKindValue, Name: "c", Status: utils.DriftAdded},
+			{
+				Kind:   utils.DriftKindService,
+				Name:   "mongodb-atlas",
+				Status: utils.DriftChanged,
+				Fields: []utils.FieldDrift{{Path: "$.config.clusterName", Local: "Cluster0", Deployed: "Cluster1"}},
+			},
+			{
+				Kind:   utils.DriftKindRule,
+				Name:   "mongodb-atlas/db.items",
+				Status: utils.DriftChanged,
+				Fields: []utils.FieldDrift{{Path: `$.roles[0]["apply when"]`, Deployed: true}},
+			},
+		})
+	})
+	t.Run("should withhold the values of fields a redacted export would strip", func(t *testing.T) {
+		service := func(secretAccessKey, region, hookSecret string) map[string]interface{} {
+			return map[string]interface{}{
+				"config": map[string]interface{}{
+					"name": "svc",
+					"type": "aws",
+					"config": map[string]interface{}{
+						"region":          region,
+						"secretAccessKey": secretAccessKey,
+						"users":           []interface{}{map[string]interface{}{"password": secretAccessKey}},
+					},
+				},
+				"incoming_webhooks": []interface{}{
+					map[string]interface{}{
+						"config": map[string]interface{}{"name": "hook", "options": map[string]interface{}{"secret": hookSecret}},
+					},
+				},
+			}
+		}
+
+		local := map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{"name": "private", "value": "local secret", "private": true},
+				map[string]interface{}{"name": "redacted", "value": utils.RedactedPlaceholderPrefix + "values/redacted.json:value", "private": true},
+			},
+			"services": []interface{}{service("local key", "us-east-1", utils.RedactedPlaceholderPrefix+"hook")},
+		}
+		deployed := map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{"name": "private", "value": "deployed secret", "private": true},
+				map[string]interface{}{"name": "redacted", "value": "deployed secret", "private": true},
+			},
+			"services": []interface{}{service("deployed key", "us-west-2", "deployed hook secret")},
+		}
+
+		report := utils.CompareApps(local, deployed)
+		u.So(t, report.Entities, gc.ShouldResemble, []utils.EntityDrift{
+			{
+				Kind:   utils.DriftKindValue,
+				Name:   "private",
+				Status: utils.DriftChanged,
+				Fields: []utils.FieldDrift{{Path: "$.value", Redacted: true}},
+			},
+			{
+				Kind:   utils.DriftKindService,
+				Name:   "svc",
+				Status: utils.DriftChanged,
+				Fields: []utils.FieldDrift{
+					{Path: "$.config.region", Local: "us-east-1", Deployed: "us-west-2"},
+					{Path: "$.config.secretAccessKey", Redacted: true},
+					{Path: "$.config.users[0].password", Redacted: true},
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileSystem is the read-only view of files that app directories are unmarshalled from
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]os.FileInfo, error)
+	Stat(name string) (os.FileInfo, error)
+}
+
+// WritableFileSystem is a FileSystem that app directories can be written to
+type WritableFileSystem interface {
+	FileSystem
+	MkdirAll(name string, perm os.FileMode) error
+	WriteFile(name string, data []byte, perm os.FileMode) error
+}
+
+// OSFileSystem reads from and writes to the local disk
+type OSFileSystem struct{}
+
+// ReadFile reads the file at name
+func (OSFileSystem) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+// ReadDir lists the directory at name, sorted by file name
+func (OSFileSystem) ReadDir(name string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(name)
+}
+
+// Stat describes the file at name
+func (OSFileSystem) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// MkdirAll creates the directory at name along with any missing parents
+func (OSFileSystem) MkdirAll(name string, perm os.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+// WriteFile writes data to the file at name, creating it with perm if it is missing
+func (OSFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return ioutil.WriteFile(name, data, perm)
+}
+
+// MemFileSystem holds files in memory. Names are slash-separated and relative to its root, "."
+type MemFileSystem struct {
+	fileTree
+	data map[string][]byte
+}
+
+// NewMemFileSystem returns an empty MemFileSystem
+func NewMemFileSystem() *MemFileSystem {
+	return &MemFileSystem{fileTree: newFileTree(), data: map[string][]byte{}}
+}
+
+// ReadFile reads the file at name
+func (fs *MemFileSystem) ReadFile(name string) ([]byte, error) {
+	data, ok := fs.data[memPath(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return data, nil
+}
+
+// MkdirAll creates the directory at name along with any missing parents
+func (fs *MemFileSystem) MkdirAll(name string, perm os.FileMode) error {
+	fs.addDir(name)
+	return nil
+}
+
+// WriteFile writes data to the file at name along with any missing parent directories
+func (fs *MemFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	fs.addFile(name, int64(len(data)))
+	fs.data[memPath(name)] = data
+	return nil
+}
+
+// ZipFileSystem reads the files of a zip archive as they are needed, without unpacking it
+type ZipFileSystem struct {
+	fileTree
+	entries map[string]*zip.File
+}
+
+// NewZipFileSystem returns a ZipFileSystem reading from r
+func NewZipFileSystem(r *zip.Reader) *ZipFileSystem {
+	fs := &ZipFileSystem{fileTree: newFileTree(), entries: map[string]*zip.File{}}
+	for _, zipFile := range r.File {
+		if zipFile.FileInfo().IsDir() {
+			fs.addDir(zipFile.Name)
+			continue
+		}
+
+		fs.addFile(zipFile.Name, int64(zipFile.UncompressedSize64))
+		fs.entries[memPath(zipFile.Name)] = zipFile
+	}
+	return fs
+}
+
+// ReadFile reads the file at name
+func (fs *ZipFileSystem) ReadFile(name string) ([]byte, error) {
+	zipFile, ok := fs.entries[memPath(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return readZipFile(zipFile)
+}
+
+// fileTree indexes the names and sizes of files held outside of the local disk, implementing ReadDir and
+// Stat for them
+type fileTree struct {
+	sizes map[string]int64
+	dirs  map[string]bool
+}
+
+func newFileTree() fileTree {
+	return fileTree{
+		sizes: map[string]int64{},
+		dirs:  map[string]bool{".": true},
+	}
+}
+
+// addFile adds a file along with any missing parent directories
+func (ft fileTree) addFile(name string, size int64) {
+	name = memPath(name)
+	ft.sizes[name] = size
+	ft.addDir(path.Dir(name))
+}
+
+// addDir adds a directory along with any missing parent directories
+func (ft fileTree) addDir(name string) {
+	for name = memPath(name); !ft.dirs[name]; name = path.Dir(name) {
+		ft.dirs[name] = true
+	}
+}
+
+// ReadDir lists the directory at name, sorted by file name
+func (ft fileTree) ReadDir(name string) ([]os.FileInfo, error) {
+	cleaned := memPath(name)
+	if !ft.dirs[cleaned] {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+
+	infos := []os.FileInfo{}
+	for file, size := range ft.sizes {
+		if path.Dir(file) == cleaned {
+			infos = append(infos, memFileInfo{name: path.Base(file), size: size})
+		}
+	}
+	for dir := range ft.dirs {
+		if dir != cleaned && path.Dir(dir) == cleaned {
+			infos = append(infos, memFileInfo{name: path.Base(dir), isDir: true})
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// Stat describes the file at name
+func (ft fileTree) Stat(name string) (os.FileInfo, error) {
+	cleaned := memPath(name)
+	if size, ok := ft.sizes[cleaned]; ok {
+		return memFileInfo{name: path.Base(cleaned), size: size}, nil
+	}
+	if ft.dirs[cleaned] {
+		return memFileInfo{name: path.Base(cleaned), isDir: true}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+func memPath(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+type memFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi memFileInfo) IsDir() bool        { return fi.isDir }
+func (fi memFileInfo) Sys() interface{}   { return nil }
+
+func (fi memFileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
@@ -0,0 +1,182 @@
+package utils_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+// failingFileSystem fails the calls made on the names listed in failures
+type failingFileSystem struct {
+	utils.FileSystem
+	failures map[string]error
+}
+
+func (fs failingFileSystem) ReadDir(name string) ([]os.FileInfo, error) {
+	if err, ok := fs.failures[name]; ok {
+		return nil, err
+	}
+	return fs.FileSystem.ReadDir(name)
+}
+
+func (fs failingFileSystem) Stat(name string) (os.FileInfo, error) {
+	if err, ok := fs.failures[name]; ok {
+		return nil, err
+	}
+	return fs.FileSystem.Stat(name)
+}
+
+func TestMemFileSystem(t *testing.T) {
+	fs := utils.NewMemFileSystem()
+	u.So(t, fs.WriteFile("functions/my_func/source.js", []byte("exports = 1;"), 0644), gc.ShouldBeNil)
+	u.So(t, fs.MkdirAll("values", os.ModePerm), gc.ShouldBeNil)
+
+	data, err := fs.ReadFile("functions/my_func/source.js")
+	u.So(t, err, gc.ShouldBeNil)
+	u.So(t, string(data), gc.ShouldEqual, "exports = 1;")
+
+	infos, err := fs.ReadDir(".")
+	u.So(t, err, gc.ShouldBeNil)
+	u.So(t, infos, gc.ShouldHaveLength, 2)
+	u.So(t, infos[0].Name(), gc.ShouldEqual, "functions")
+	u.So(t, infos[0].IsDir(), gc.ShouldBeTrue)
+	u.So(t, infos[1].Name(), gc.ShouldEqual, "values")
+
+	info, err := fs.Stat("functions/my_func/source.js")
+	u.So(t, err, gc.ShouldBeNil)
+	u.So(t, info.IsDir(), gc.ShouldBeFalse)
+	u.So(t, info.Size(), gc.ShouldEqual, 12)
+
+	_, err = fs.ReadFile("functions/other/source.js")
+	u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	_, err = fs.ReadDir("services")
+	u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+	_, err = fs.Stat("services")
+	u.So(t, os.IsNotExist(err), gc.ShouldBeTrue)
+}
+
+func TestUnmarshalFromFileSystem(t *testing.T) {
+	expected, err := utils.UnmarshalFromDir("../testdata/full_app")
+	u.So(t, err, gc.ShouldBeNil)
+
+	exported := u.NewZip(u.ReadDirectoryFiles("../testdata/full_app"))
+
+	t.Run("should load an app unpacked into memory", func(t *testing.T) {
+		fs := utils.NewMemFileSystem()
+		u.So(t, utils.WriteZipToFileSystem(fs, "my-app", bytes.NewReader(exported), false), gc.ShouldBeNil)
+
+		app, err := utils.UnmarshalFromFileSystem(fs, "my-app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app, gc.ShouldResemble, expected)
+
+		err = utils.WriteZipToFileSystem(fs, "my-app", bytes.NewReader(exported), false)
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, "directory already exists")
+	})
+
+	t.Run("should load an app from a zip archive", func(t *testing.T) {
+		r, err := zip.NewReader(bytes.NewReader(exported), int64(len(exported)))
+		u.So(t, err, gc.ShouldBeNil)
+
+		app, err := utils.UnmarshalFromFileSystem(utils.NewZipFileSystem(r), ".")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app, gc.ShouldResemble, expected)
+	})
+
+	t.Run("should load an app without any entity directories", func(t *testing.T) {
+		fs := utils.NewMemFileSystem()
+		u.So(t, fs.WriteFile("stitch.json", []byte(`{"name": "empty-app"}`), 0644), gc.ShouldBeNil)
+
+		app, err := utils.UnmarshalFromFileSystem(fs, ".")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app["name"], gc.ShouldEqual, "empty-app")
+		u.So(t, app["services"], gc.ShouldBeEmpty)
+	})
+
+	t.Run("should propagate errors listing and describing directories", func(t *testing.T) {
+		fs := utils.NewMemFileSystem()
+		u.So(t, utils.WriteZipToFileSystem(fs, ".", bytes.NewReader(exported), true), gc.ShouldBeNil)
+
+		for _, name := range []string{"values", "functions", "functions/function_a", "services"} {
+			failure := errors.New("permission denied: " + name)
+			_, err := utils.UnmarshalFromFileSystem(failingFileSystem{fs, map[string]error{name: failure}}, ".")
+			u.So(t, err, gc.ShouldEqual, failure)
+		}
+	})
+}
+
+func TestReadLocalEntitiesFromFileSystem(t *testing.T) {
+	fs := utils.NewMemFileSystem()
+	exported := u.NewZip(u.ReadDirectoryFiles("../testdata/full_app"))
+	u.So(t, utils.WriteZipToFileSystem(fs, "my-app", bytes.NewReader(exported), false), gc.ShouldBeNil)
+
+	t.Run("should read the entities of an app directory in memory", func(t *testing.T) {
+		values, err := utils.ReadLocalValuesFromFileSystem(fs, "my-app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, values, gc.ShouldHaveLength, 2)
+		u.So(t, values[0].Name(), gc.ShouldEqual, "a")
+		u.So(t, values[0].Value(), gc.ShouldEqual, "AAAAAA")
+
+		expectedFunctions, err := utils.ReadLocalFunctions("../testdata/full_app")
+		u.So(t, err, gc.ShouldBeNil)
+		functions, err := utils.ReadLocalFunctionsFromFileSystem(fs, "my-app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, functions, gc.ShouldHaveLength, len(expectedFunctions))
+		for i, fn := range functions {
+			u.So(t, fn.Name, gc.ShouldEqual, expectedFunctions[i].Name)
+			u.So(t, fn.Source, gc.ShouldEqual, expectedFunctions[i].Source)
+		}
+
+		expectedServices, err := utils.ReadLocalServices("../testdata/full_app")
+		u.So(t, err, gc.ShouldBeNil)
+		services, err := utils.ReadLocalServicesFromFileSystem(fs, "my-app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, services, gc.ShouldHaveLength, len(expectedServices))
+		for i, service := range services {
+			u.So(t, service.Config, gc.ShouldResemble, expectedServices[i].Config)
+
+			expectedRuleFiles, err := expectedServices[i].RuleFiles()
+			u.So(t, err, gc.ShouldBeNil)
+			ruleFiles, err := service.RuleFiles()
+			u.So(t, err, gc.ShouldBeNil)
+			u.So(t, ruleFiles, gc.ShouldHaveLength, len(expectedRuleFiles))
+		}
+
+		expectedWebhooks, err := utils.ReadLocalWebhooks("../testdata/full_app")
+		u.So(t, err, gc.ShouldBeNil)
+		webhooks, err := utils.ReadLocalWebhooksFromFileSystem(fs, "my-app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, webhooks, gc.ShouldHaveLength, len(expectedWebhooks))
+		for i, webhook := range webhooks {
+			u.So(t, webhook.Config, gc.ShouldResemble, expectedWebhooks[i].Config)
+		}
+
+		expectedIssues, err := utils.LintRules("../testdata/full_app")
+		u.So(t, err, gc.ShouldBeNil)
+		issues, err := utils.LintRulesFromFileSystem(fs, "my-app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, issues, gc.ShouldHaveLength, len(expectedIssues))
+	})
+
+	t.Run("should propagate errors listing entity directories", func(t *testing.T) {
+		for _, name := range []string{"my-app/values", "my-app/functions", "my-app/services", "my-app/services/service_a/rules"} {
+			failure := errors.New("permission denied: " + name)
+			failing := failingFileSystem{fs, map[string]error{name: failure}}
+
+			_, valuesErr := utils.ReadLocalValuesFromFileSystem(failing, "my-app")
+			_, functionsErr := utils.ReadLocalFunctionsFromFileSystem(failing, "my-app")
+			_, lintErr := utils.LintRulesFromFileSystem(failing, "my-app")
+			u.So(t, []error{valuesErr, functionsErr, lintErr}, gc.ShouldContain, failure)
+		}
+
+		failure := errors.New("permission denied")
+		_, err := utils.ReadLocalWebhooksFromFileSystem(failingFileSystem{fs, map[string]error{"my-app/services/service_a/incoming_webhooks": failure}}, "my-app")
+		u.So(t, err, gc.ShouldEqual, failure)
+	})
+}
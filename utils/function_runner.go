@@ -0,0 +1,313 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// contextPrelude builds the `context` global made available to functions run locally. Services are
+// stubbed from fixtures that map each method name to the value it returns; like the methods of deployed
+// services, the stubs return promises. MongoDB collections are looked up under a "<database>.<collection>"
+// key of the service's fixture
+const contextPrelude = `((values, services, execute, log) => {
+  const copy = (value) => value === undefined ? undefined : JSON.parse(JSON.stringify(value));
+
+  const stubMethods = (fixture, keep) => {
+    const stub = {};
+    for (const method of Object.keys(fixture).filter(keep)) {
+      stub[method] = () => {
+        const result = copy(fixture[method]);
+        if (method === "find" || method === "aggregate") {
+          return { toArray: () => Promise.resolve(result) };
+        }
+        return Promise.resolve(result);
+      };
+    }
+    return stub;
+  };
+
+  const stubService = (name, fixture) => {
+    const service = stubMethods(fixture, (key) => !key.includes("."));
+    service.db = (database) => ({
+      collection: (collection) => {
+        const namespace = database + "." + collection;
+        if (!Object.prototype.hasOwnProperty.call(fixture, namespace)) {
+          throw new Error("no fixture for collection \"" + namespace + "\" of service \"" + name + "\"");
+        }
+        return stubMethods(fixture[namespace], () => true);
+      }
+    });
+    return service;
+  };
+
+  globalThis.console = { log, info: log, warn: log, error: log };
+
+  globalThis.context = {
+    user: { id: "", type: "system", data: {}, custom_data: {} },
+    values: {
+      get: (name) => copy(values[name])
+    },
+    services: {
+      get: (name) => {
+        if (!Object.prototype.hasOwnProperty.call(services, name)) {
+          throw new Error("no fixture for service \"" + name + "\"");
+        }
+        return stubService(name, services[name]);
+      }
+    },
+    functions: {
+      execute: (name, ...args) => execute(name, args)
+    }
+  };
+})`
+
+// errFunctionTimedOut is used to halt the JavaScript runtime once a function exceeds its timeout
+var errFunctionTimedOut = errors.New("function timed out")
+
+// LocalFunctionResult is the outcome of running a function locally
+type LocalFunctionResult struct {
+	// Result is the JSON representation of the value returned by the function, or nil if it returned undefined
+	Result json.RawMessage
+	Logs   []string
+}
+
+// FunctionRunner runs the functions of an app loaded with UnmarshalFromDir in an embedded ES6 JavaScript
+// engine, without any network access
+type FunctionRunner struct {
+	functions map[string]string
+	values    map[string]interface{}
+	services  map[string]interface{}
+
+	vm      *goja.Runtime
+	exports map[string]goja.Callable
+	logs    []string
+}
+
+// NewFunctionRunner returns a FunctionRunner for the given app. The services map provides the
+// fixtures that back context.services, keyed by service name
+func NewFunctionRunner(app map[string]interface{}, services map[string]interface{}) (*FunctionRunner, error) {
+	fr := &FunctionRunner{
+		functions: map[string]string{},
+		values:    map[string]interface{}{},
+		services:  services,
+	}
+
+	if fr.services == nil {
+		fr.services = map[string]interface{}{}
+	}
+
+	functions, _ := app[functionsName].([]interface{})
+	for _, fn := range functions {
+		directory, _ := fn.(map[string]interface{})
+		config, _ := directory[configName].(map[string]interface{})
+		name, _ := config["name"].(string)
+		source, _ := directory[sourceName].(string)
+		if name == "" {
+			return nil, errors.New("found a function without a name")
+		}
+
+		fr.functions[name] = source
+	}
+
+	values, _ := app[valuesName].([]interface{})
+	for _, v := range values {
+		value, _ := v.(map[string]interface{})
+		name, _ := value["name"].(string)
+		if name == "" {
+			return nil, errors.New("found a value without a name")
+		}
+
+		fr.values[name] = value["value"]
+	}
+
+	return fr, nil
+}
+
+// Run executes the named function with the given JSON arguments, halting it once timeout elapses. When the
+// function returns a promise, such as an async function does, its settled value is the result
+func (fr *FunctionRunner) Run(name string, args []json.RawMessage, timeout time.Duration) (*LocalFunctionResult, error) {
+	if err := fr.reset(); err != nil {
+		return nil, err
+	}
+
+	jsArgs := make([]goja.Value, 0, len(args))
+	for _, arg := range args {
+		value, err := fr.parseJSON(string(arg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse argument %s: %s", arg, err)
+		}
+		jsArgs = append(jsArgs, value)
+	}
+
+	vm := fr.vm
+	timer := time.AfterFunc(timeout, func() { vm.Interrupt(errFunctionTimedOut) })
+	defer timer.Stop()
+
+	value, err := fr.call(name, jsArgs)
+	if err == nil {
+		value, err = settle(name, value)
+	}
+
+	result := &LocalFunctionResult{Logs: fr.logs}
+	if interrupted, ok := err.(*goja.InterruptedError); ok && interrupted.Value() == errFunctionTimedOut {
+		return result, fmt.Errorf("did not complete within %s", timeout)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if goja.IsUndefined(value) {
+		return result, nil
+	}
+
+	encoded, err := fr.stringifyJSON(value)
+	if err != nil {
+		return result, err
+	}
+
+	if !goja.IsUndefined(encoded) {
+		result.Result = json.RawMessage(encoded.String())
+	}
+
+	return result, nil
+}
+
+// settle returns the value a promise was fulfilled with, or the error it was rejected with. Other values
+// are returned as is. Since no event loop runs, promises are settled once the function returns or not at all
+func settle(name string, value goja.Value) (goja.Value, error) {
+	promise, ok := value.Export().(*goja.Promise)
+	if !ok {
+		return value, nil
+	}
+
+	switch promise.State() {
+	case goja.PromiseStateFulfilled:
+		return promise.Result(), nil
+	case goja.PromiseStateRejected:
+		return nil, fmt.Errorf("function %q rejected with %s", name, promise.Result())
+	default:
+		return nil, fmt.Errorf("function %q returned a promise that never settled", name)
+	}
+}
+
+// reset creates a fresh runtime so that state does not leak between runs
+func (fr *FunctionRunner) reset() error {
+	fr.vm = goja.New()
+	fr.exports = map[string]goja.Callable{}
+	fr.logs = []string{}
+
+	prelude, err := fr.vm.RunString(contextPrelude)
+	if err != nil {
+		return err
+	}
+
+	values, err := fr.toJSValue(fr.values)
+	if err != nil {
+		return err
+	}
+
+	services, err := fr.toJSValue(fr.services)
+	if err != nil {
+		return err
+	}
+
+	build, _ := goja.AssertFunction(prelude)
+	_, err = build(goja.Undefined(), values, services, fr.vm.ToValue(fr.execute), fr.vm.ToValue(fr.log))
+	return err
+}
+
+// call loads the named function, evaluating its source the first time it is used, and calls it
+func (fr *FunctionRunner) call(name string, args []goja.Value) (goja.Value, error) {
+	fn, ok := fr.exports[name]
+	if !ok {
+		source, ok := fr.functions[name]
+		if !ok {
+			return goja.Undefined(), fmt.Errorf("function %q not found", name)
+		}
+
+		exports, err := fr.vm.RunScript(name+".js", "(() => {\nlet exports;\n"+source+"\nreturn exports;\n})()")
+		if err != nil {
+			return goja.Undefined(), err
+		}
+
+		if fn, ok = goja.AssertFunction(exports); !ok {
+			return goja.Undefined(), fmt.Errorf("function %q does not assign a function to exports", name)
+		}
+
+		fr.exports[name] = fn
+	}
+
+	return fn(goja.Undefined(), args...)
+}
+
+// execute backs context.functions.execute, rethrowing errors into the calling function
+func (fr *FunctionRunner) execute(call goja.FunctionCall) goja.Value {
+	name := call.Argument(0).String()
+
+	var args []goja.Value
+	if argList, ok := call.Argument(1).(*goja.Object); ok {
+		for _, key := range argList.Keys() {
+			args = append(args, argList.Get(key))
+		}
+	}
+
+	value, err := fr.call(name, args)
+	switch err := err.(type) {
+	case nil:
+		return value
+	case *goja.Exception:
+		panic(err.Value())
+	case *goja.InterruptedError, *goja.StackOverflowError:
+		// uncatchable errors halt the calling function too
+		panic(err)
+	default:
+		functionError := fr.vm.NewGoError(err)
+		functionError.Set("name", "FunctionError")
+		panic(functionError)
+	}
+}
+
+// log backs console.log and friends
+func (fr *FunctionRunner) log(call goja.FunctionCall) goja.Value {
+	parts := make([]string, 0, len(call.Arguments))
+	for _, arg := range call.Arguments {
+		if _, ok := arg.(*goja.Object); ok {
+			if encoded, err := fr.stringifyJSON(arg); err == nil && !goja.IsUndefined(encoded) {
+				parts = append(parts, encoded.String())
+				continue
+			}
+		}
+		parts = append(parts, arg.String())
+	}
+
+	fr.logs = append(fr.logs, strings.Join(parts, " "))
+
+	return goja.Undefined()
+}
+
+func (fr *FunctionRunner) toJSValue(data interface{}) (goja.Value, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return goja.Undefined(), err
+	}
+
+	return fr.parseJSON(string(encoded))
+}
+
+func (fr *FunctionRunner) parseJSON(data string) (goja.Value, error) {
+	return fr.callJSON("parse", fr.vm.ToValue(data))
+}
+
+func (fr *FunctionRunner) stringifyJSON(value goja.Value) (goja.Value, error) {
+	return fr.callJSON("stringify", value)
+}
+
+func (fr *FunctionRunner) callJSON(method string, arg goja.Value) (goja.Value, error) {
+	fn, _ := goja.AssertFunction(fr.vm.Get("JSON").ToObject(fr.vm).Get(method))
+	return fn(goja.Undefined(), arg)
+}
@@ -0,0 +1,135 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func localFunction(name, source string) map[string]interface{} {
+	return map[string]interface{}{
+		"config": map[string]interface{}{"name": name},
+		"source": source,
+	}
+}
+
+func TestFunctionRunner(t *testing.T) {
+	app := map[string]interface{}{
+		"functions": []interface{}{
+			localFunction("add", `exports = (a, b) => { console.log("adding", a, b); return a + b; };`),
+			localFunction("greet", `exports = function(name) {
+  const greeting = context.values.get("greeting");
+  return { message: `+"`${greeting}, ${name}`"+`, sum: context.functions.execute("add", 1, 2) };
+};`),
+			localFunction("lookup", `exports = async (id) => {
+  const users = context.services.get("mongodb-atlas").db("app").collection("users");
+  const [one, all] = await Promise.all([users.findOne({ _id: id }), users.find({}).toArray()]);
+  return { one, all };
+};`),
+			localFunction("then", `exports = () => context.services.get("mongodb-atlas").db("app").collection("users")
+  .findOne({}).then(({ name }) => name.toUpperCase());`),
+			localFunction("broken", `exports = function() { return missing.field; };`),
+			localFunction("rejected", `exports = async () => { throw new Error("nope"); };`),
+			localFunction("pending", `exports = () => new Promise(() => {});`),
+			localFunction("forever", `exports = () => { for (;;) {} };`),
+			localFunction("foreverAsync", `exports = async () => { let i = await 0; while (true) { i++; } };`),
+			localFunction("nothing", `exports = function() {};`),
+			localFunction("notAFunction", `const x = 1;`),
+		},
+		"values": []interface{}{
+			map[string]interface{}{"name": "greeting", "value": "Hello"},
+		},
+	}
+
+	services := map[string]interface{}{
+		"mongodb-atlas": map[string]interface{}{
+			"app.users": map[string]interface{}{
+				"findOne": map[string]interface{}{"name": "alice"},
+				"find":    []interface{}{map[string]interface{}{"name": "alice"}, map[string]interface{}{"name": "bob"}},
+			},
+		},
+	}
+
+	runner, err := utils.NewFunctionRunner(app, services)
+	u.So(t, err, gc.ShouldBeNil)
+
+	t.Run("should run a function with arguments and capture its logs", func(t *testing.T) {
+		result, err := runner.Run("add", []json.RawMessage{json.RawMessage("1"), json.RawMessage("2")}, time.Second)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(result.Result), gc.ShouldEqual, "3")
+		u.So(t, result.Logs, gc.ShouldResemble, []string{"adding 1 2"})
+	})
+
+	t.Run("should stub context.values and context.functions", func(t *testing.T) {
+		result, err := runner.Run("greet", []json.RawMessage{json.RawMessage(`"world"`)}, time.Second)
+		u.So(t, err, gc.ShouldBeNil)
+
+		var greeting map[string]interface{}
+		u.So(t, json.Unmarshal(result.Result, &greeting), gc.ShouldBeNil)
+		u.So(t, greeting, gc.ShouldResemble, map[string]interface{}{"message": "Hello, world", "sum": 3.0})
+	})
+
+	t.Run("should stub context.services from fixtures with methods returning promises", func(t *testing.T) {
+		result, err := runner.Run("lookup", []json.RawMessage{json.RawMessage(`"id"`)}, time.Second)
+		u.So(t, err, gc.ShouldBeNil)
+
+		var found map[string]interface{}
+		u.So(t, json.Unmarshal(result.Result, &found), gc.ShouldBeNil)
+		u.So(t, found["one"], gc.ShouldResemble, map[string]interface{}{"name": "alice"})
+		u.So(t, found["all"], gc.ShouldHaveLength, 2)
+
+		result, err = runner.Run("then", nil, time.Second)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, string(result.Result), gc.ShouldEqual, `"ALICE"`)
+	})
+
+	t.Run("should report a missing service fixture", func(t *testing.T) {
+		noFixtures, err := utils.NewFunctionRunner(app, nil)
+		u.So(t, err, gc.ShouldBeNil)
+
+		_, err = noFixtures.Run("lookup", nil, time.Second)
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, `no fixture for service "mongodb-atlas"`)
+	})
+
+	t.Run("should report errors thrown by the function", func(t *testing.T) {
+		_, err := runner.Run("broken", nil, time.Second)
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, "ReferenceError")
+	})
+
+	t.Run("should report rejected and unsettled promises", func(t *testing.T) {
+		_, err := runner.Run("rejected", nil, time.Second)
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, `function "rejected" rejected with Error: nope`)
+
+		_, err = runner.Run("pending", nil, time.Second)
+		u.So(t, err, gc.ShouldBeError, `function "pending" returned a promise that never settled`)
+	})
+
+	t.Run("should halt functions that exceed the timeout", func(t *testing.T) {
+		_, err := runner.Run("forever", nil, 50*time.Millisecond)
+		u.So(t, err, gc.ShouldBeError, "did not complete within 50ms")
+
+		_, err = runner.Run("foreverAsync", nil, 50*time.Millisecond)
+		u.So(t, err, gc.ShouldBeError, "did not complete within 50ms")
+	})
+
+	t.Run("should return no result for undefined", func(t *testing.T) {
+		result, err := runner.Run("nothing", nil, time.Second)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, result.Result, gc.ShouldBeNil)
+	})
+
+	t.Run("should report unknown functions and sources without exports", func(t *testing.T) {
+		_, err := runner.Run("unknown", nil, time.Second)
+		u.So(t, err, gc.ShouldBeError, `function "unknown" not found`)
+
+		_, err = runner.Run("notAFunction", nil, time.Second)
+		u.So(t, err, gc.ShouldBeError, `function "notAFunction" does not assign a function to exports`)
+	})
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// FunctionSourceTemplate is the source used when scaffolding a new function
+const FunctionSourceTemplate = `exports = function(arg){
+  /*
+    Accessing application's values:
+    var x = context.values.get("value_name");
+
+    Accessing a mongodb service:
+    var collection = context.services.get("mongodb-atlas").db("dbname").collection("coll_name");
+    var doc = collection.findOne({owner_id: context.user.id});
+
+    To call other named functions:
+    var result = context.functions.execute("function_name", arg1, arg2);
+  */
+  return arg;
+};
+`
+
+var functionNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateFunctionName returns an error if name cannot be used as the name of a function, and so as the name
+// of its directory
+func ValidateFunctionName(name string) error {
+	if !functionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid function name %q: only letters, digits, underscores and hyphens are allowed", name)
+	}
+	return nil
+}
+
+// LocalFunction is a function stored within an app directory as a config.json and source.js pair
+type LocalFunction struct {
+	Name   string
+	Dir    string
+	Config map[string]interface{}
+	Source string
+}
+
+// Private returns whether the function is marked as private in its config
+func (lf *LocalFunction) Private() bool {
+	private, _ := lf.Config["private"].(bool)
+	return private
+}
+
+// FunctionsDirectory returns the path of the functions directory within the app directory at appPath
+func FunctionsDirectory(appPath string) string {
+	return filepath.Join(appPath, functionsName)
+}
+
+// ReadLocalFunctions loads every function within the app directory at appPath, sorted by name
+func ReadLocalFunctions(appPath string) ([]*LocalFunction, error) {
+	return ReadLocalFunctionsFromFileSystem(OSFileSystem{}, appPath)
+}
+
+// ReadLocalFunctionsFromFileSystem loads every function within the app directory at appPath of fs, sorted
+// by name
+func ReadLocalFunctionsFromFileSystem(fs FileSystem, appPath string) ([]*LocalFunction, error) {
+	functionsDir := FunctionsDirectory(appPath)
+
+	fileInfos, err := fs.ReadDir(functionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*LocalFunction{}, nil
+		}
+		return nil, err
+	}
+
+	functions := []*LocalFunction{}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() {
+			continue
+		}
+
+		fn, err := readLocalFunction(fs, filepath.Join(functionsDir, fileInfo.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		functions = append(functions, fn)
+	}
+
+	sort.Slice(functions, func(i, j int) bool { return functions[i].Name < functions[j].Name })
+
+	return functions, nil
+}
+
+// ReadLocalFunction loads the function with the given name from the app directory at appPath
+func ReadLocalFunction(appPath, name string) (*LocalFunction, error) {
+	functions, err := ReadLocalFunctions(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fn := range functions {
+		if fn.Name == name {
+			return fn, nil
+		}
+	}
+
+	return nil, fmt.Errorf("function %q not found in %s", name, FunctionsDirectory(appPath))
+}
+
+// WriteLocalFunction writes the config and source of the function to its directory, defaulting to
+// functions/<name> within the app directory at appPath
+func WriteLocalFunction(appPath string, fn *LocalFunction) error {
+	if fn.Dir == "" {
+		if err := ValidateFunctionName(fn.Name); err != nil {
+			return err
+		}
+		fn.Dir = filepath.Join(FunctionsDirectory(appPath), fn.Name)
+	}
+
+	if err := WriteJSONFile(filepath.Join(fn.Dir, configName+jsonExt), fn.Config); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(fn.Dir, sourceName+jsExt), []byte(fn.Source), 0600)
+}
+
+func readLocalFunction(fs FileSystem, dir string) (*LocalFunction, error) {
+	config := map[string]interface{}{}
+	if err := readAndUnmarshalJSONFrom(fs, filepath.Join(dir, configName+jsonExt), &config); err != nil {
+		return nil, err
+	}
+
+	source, err := fs.ReadFile(filepath.Join(dir, sourceName+jsExt))
+	if err != nil {
+		return nil, err
+	}
+
+	name, _ := config["name"].(string)
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return &LocalFunction{
+		Name:   name,
+		Dir:    dir,
+		Config: config,
+		Source: string(source),
+	}, nil
+}
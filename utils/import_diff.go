@@ -0,0 +1,332 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Actions of the changes described by import diffs
+const (
+	DiffAdded    = "added"
+	DiffModified = "modified"
+	DiffRemoved  = "removed"
+)
+
+// DiffKindOther is the kind of import diffs that do not name the kind of entity they change
+const DiffKindOther = "other"
+
+const (
+	unifiedDiffContext = 3
+
+	// maxDiffLinePairs caps the lines of the changed regions of two texts, multiplied together, that are diffed
+	// line by line, bounding the memory of the table diffLines computes
+	maxDiffLinePairs = 4 << 20
+)
+
+var (
+	importDiffHeaderPattern = regexp.MustCompile(`^[+\-~]?\s*(?i:(new|added|created|removed|deleted|modified|updated|changed))\s+([a-zA-Z][a-zA-Z _]*?)\s*:\s*(.*)$`)
+
+	importDiffActions = map[string]string{
+		"new":      DiffAdded,
+		"added":    DiffAdded,
+		"created":  DiffAdded,
+		"removed":  DiffRemoved,
+		"deleted":  DiffRemoved,
+		"modified": DiffModified,
+		"updated":  DiffModified,
+		"changed":  DiffModified,
+	}
+
+	importDiffActionOrder = map[string]int{DiffAdded: 0, DiffModified: 1, DiffRemoved: 2}
+)
+
+// ImportDiff is a single change proposed by an import, as described by one of the strings returned
+// by the server
+type ImportDiff struct {
+	Action  string
+	Kind    string
+	Name    string
+	Details []string
+}
+
+// ImportDiffGroup holds the import diffs of a single kind of entity
+type ImportDiffGroup struct {
+	Kind  string
+	Diffs []ImportDiff
+}
+
+// ParseImportDiff parses a diff string returned by the server. A first line such as "New Function: name"
+// names the action, kind and entity of the change and any following lines are kept as details. Diffs that
+// do not start with such a line are of DiffKindOther, with the action given by a leading "+" or "-"
+func ParseImportDiff(diff string) ImportDiff {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	header := strings.TrimSpace(lines[0])
+	details := lines[1:]
+
+	if match := importDiffHeaderPattern.FindStringSubmatch(header); match != nil {
+		return ImportDiff{
+			Action:  importDiffActions[strings.ToLower(match[1])],
+			Kind:    strings.ToLower(strings.Replace(match[2], "_", " ", -1)),
+			Name:    strings.Trim(match[3], `'" `),
+			Details: details,
+		}
+	}
+
+	parsed := ImportDiff{Action: DiffModified, Kind: DiffKindOther, Name: header, Details: details}
+	switch {
+	case strings.HasPrefix(header, "+"):
+		parsed.Action = DiffAdded
+		parsed.Name = strings.TrimSpace(header[1:])
+	case strings.HasPrefix(header, "-"):
+		parsed.Action = DiffRemoved
+		parsed.Name = strings.TrimSpace(header[1:])
+	}
+
+	return parsed
+}
+
+// GroupImportDiffs parses the diff strings returned by the server and groups them by kind. Groups are
+// sorted by kind, with DiffKindOther last, and diffs by action then name
+func GroupImportDiffs(diffs []string) []ImportDiffGroup {
+	byKind := map[string][]ImportDiff{}
+	for _, diff := range diffs {
+		parsed := ParseImportDiff(diff)
+		byKind[parsed.Kind] = append(byKind[parsed.Kind], parsed)
+	}
+
+	groups := make([]ImportDiffGroup, 0, len(byKind))
+	for kind, kindDiffs := range byKind {
+		sort.SliceStable(kindDiffs, func(i, j int) bool {
+			a, b := kindDiffs[i], kindDiffs[j]
+			if a.Action != b.Action {
+				return importDiffActionOrder[a.Action] < importDiffActionOrder[b.Action]
+			}
+			return a.Name < b.Name
+		})
+		groups = append(groups, ImportDiffGroup{Kind: kind, Diffs: kindDiffs})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Kind == DiffKindOther) != (groups[j].Kind == DiffKindOther) {
+			return groups[j].Kind == DiffKindOther
+		}
+		return groups[i].Kind < groups[j].Kind
+	})
+
+	return groups
+}
+
+// SummarizeImportDiffs returns a one-line count of the changes within groups, such as
+// "2 function(s) added, 1 value(s) removed"
+func SummarizeImportDiffs(groups []ImportDiffGroup) string {
+	parts := []string{}
+	for _, group := range groups {
+		counts := map[string]int{}
+		for _, diff := range group.Diffs {
+			counts[diff.Action]++
+		}
+
+		for _, action := range []string{DiffAdded, DiffModified, DiffRemoved} {
+			if counts[action] == 0 {
+				continue
+			}
+
+			kind := group.Kind + "(s)"
+			if group.Kind == DiffKindOther {
+				kind = "other change(s)"
+			}
+			parts = append(parts, fmt.Sprintf("%d %s %s", counts[action], kind, action))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// FunctionSources returns the source of every function within the provided app data, keyed by name
+func FunctionSources(app map[string]interface{}) map[string]string {
+	sources := map[string]string{}
+	for _, function := range entityList(app[functionsName]) {
+		if source, ok := function[sourceName].(string); ok {
+			sources[entityName(entityMap(function[configName]))] = source
+		}
+	}
+	return sources
+}
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// UnifiedDiff returns the lines of a unified diff turning oldText into newText, or nothing if they are equal.
+// Texts whose changed regions are too large to diff line by line only report how many lines they have
+func UnifiedDiff(oldName, newName, oldText, newText string) []string {
+	if oldText == newText {
+		return nil
+	}
+
+	oldLines, newLines := splitLines(oldText), splitLines(newText)
+
+	lines := []string{"--- " + oldName, "+++ " + newName}
+
+	ops, ok := diffLines(oldLines, newLines)
+	if !ok {
+		return append(lines, fmt.Sprintf("source changed (%d → %d lines)", len(oldLines), len(newLines)))
+	}
+
+	for start := 0; start < len(ops); {
+		// find the next change and the extent of its hunk, merging changes separated by little context
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		last := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			} else if i-last > 2*unifiedDiffContext {
+				break
+			}
+		}
+
+		hunkStart := first - unifiedDiffContext
+		if hunkStart < start {
+			hunkStart = start
+		}
+		hunkEnd := last + unifiedDiffContext + 1
+		if hunkEnd > len(ops) {
+			hunkEnd = len(ops)
+		}
+
+		oldLine, newLine := 1, 1
+		for _, op := range ops[:hunkStart] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+
+		var oldCount, newCount int
+		body := []string{}
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+			body = append(body, string(op.kind)+op.line)
+		}
+
+		lines = append(lines, fmt.Sprintf("@@ -%s +%s @@", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount)))
+		lines = append(lines, body...)
+
+		start = hunkEnd
+	}
+
+	return lines
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the edit script turning a into b, based on their longest common subsequence. The common
+// prefix and suffix of a and b are matched as is, and false is returned if what lies between them is too large
+// to diff within maxDiffLinePairs
+func diffLines(a, b []string) ([]diffOp, bool) {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	middle, ok := diffLinesLCS(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	if !ok {
+		return nil, false
+	}
+	ops = append(ops, middle...)
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	return ops, true
+}
+
+// diffLinesLCS returns the edit script turning a into b from a table of the lengths of their longest common
+// subsequences, or false if the table would exceed maxDiffLinePairs
+func diffLinesLCS(a, b []string) ([]diffOp, bool) {
+	if (len(a)+1)*(len(b)+1) > maxDiffLinePairs {
+		return nil, false
+	}
+
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops, true
+}
@@ -0,0 +1,166 @@
+package utils_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseImportDiff(t *testing.T) {
+	for _, tc := range []struct {
+		diff     string
+		expected utils.ImportDiff
+	}{
+		{
+			diff:     "New Function: 'my_func'",
+			expected: utils.ImportDiff{Action: utils.DiffAdded, Kind: "function", Name: "my_func", Details: []string{}},
+		},
+		{
+			diff:     "- Deleted incoming_webhook: hook\n  url: /hook\n",
+			expected: utils.ImportDiff{Action: utils.DiffRemoved, Kind: "incoming webhook", Name: "hook", Details: []string{"  url: /hook"}},
+		},
+		{
+			diff:     "Updated Auth Provider: api-key",
+			expected: utils.ImportDiff{Action: utils.DiffModified, Kind: "auth provider", Name: "api-key", Details: []string{}},
+		},
+		{
+			diff:     "+ something unexpected",
+			expected: utils.ImportDiff{Action: utils.DiffAdded, Kind: utils.DiffKindOther, Name: "something unexpected", Details: []string{}},
+		},
+		{
+			diff:     "sample-diff-contents",
+			expected: utils.ImportDiff{Action: utils.DiffModified, Kind: utils.DiffKindOther, Name: "sample-diff-contents", Details: []string{}},
+		},
+	} {
+		t.Run(tc.diff, func(t *testing.T) {
+			u.So(t, utils.ParseImportDiff(tc.diff), gc.ShouldResemble, tc.expected)
+		})
+	}
+}
+
+func TestGroupImportDiffs(t *testing.T) {
+	groups := utils.GroupImportDiffs([]string{
+		"something else",
+		"Removed Value: b",
+		"Modified Function: z",
+		"Removed Function: y",
+		"New Function: x",
+		"New Value: a",
+	})
+
+	kinds := []string{}
+	names := [][]string{}
+	for _, group := range groups {
+		kinds = append(kinds, group.Kind)
+		groupNames := []string{}
+		for _, diff := range group.Diffs {
+			groupNames = append(groupNames, diff.Name)
+		}
+		names = append(names, groupNames)
+	}
+
+	u.So(t, kinds, gc.ShouldResemble, []string{"function", "value", utils.DiffKindOther})
+	u.So(t, names, gc.ShouldResemble, [][]string{{"x", "z", "y"}, {"a", "b"}, {"something else"}})
+	u.So(t, utils.SummarizeImportDiffs(groups), gc.ShouldEqual,
+		"1 function(s) added, 1 function(s) modified, 1 function(s) removed, 1 value(s) added, 1 value(s) removed, 1 other change(s) modified")
+}
+
+func TestFunctionSources(t *testing.T) {
+	app, err := utils.UnmarshalFromDir("../testdata/full_app")
+	u.So(t, err, gc.ShouldBeNil)
+
+	sources := utils.FunctionSources(app)
+	u.So(t, sources, gc.ShouldHaveLength, 2)
+	u.So(t, sources["function_a"], gc.ShouldContainSubstring, "return x + 1;")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("should return nothing for equal texts", func(t *testing.T) {
+		u.So(t, utils.UnifiedDiff("a", "b", "x\n", "x\n"), gc.ShouldBeEmpty)
+	})
+
+	t.Run("should diff added and removed texts", func(t *testing.T) {
+		u.So(t, utils.UnifiedDiff("a", "b", "", "x\ny\n"), gc.ShouldResemble, []string{"--- a", "+++ b", "@@ -0,0 +1,2 @@", "+x", "+y"})
+		u.So(t, utils.UnifiedDiff("a", "b", "x\n", ""), gc.ShouldResemble, []string{"--- a", "+++ b", "@@ -1 +0,0 @@", "-x"})
+	})
+
+	t.Run("should split distant changes into hunks with context", func(t *testing.T) {
+		oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+		newText := "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+		u.So(t, utils.UnifiedDiff("a", "b", oldText, newText), gc.ShouldResemble, []string{
+			"--- a",
+			"+++ b",
+			"@@ -1,5 +1,5 @@",
+			" 1",
+			"-2",
+			"+TWO",
+			" 3",
+			" 4",
+			" 5",
+			"@@ -10,3 +10,4 @@",
+			" 10",
+			" 11",
+			" 12",
+			"+13",
+		})
+	})
+
+	t.Run("should merge changes separated by little context", func(t *testing.T) {
+		u.So(t, utils.UnifiedDiff("a", "b", "1\n2\n3\n4\n5\n", "ONE\n2\n3\n4\nFIVE\n"), gc.ShouldResemble, []string{
+			"--- a",
+			"+++ b",
+			"@@ -1,5 +1,5 @@",
+			"-1",
+			"+ONE",
+			" 2",
+			" 3",
+			" 4",
+			"-5",
+			"+FIVE",
+		})
+	})
+	t.Run("should diff a small change within large texts", func(t *testing.T) {
+		oldLines, newLines := make([]string, 20000), make([]string, 20000)
+		for i := range oldLines {
+			oldLines[i] = fmt.Sprintf("line %d", i)
+			newLines[i] = oldLines[i]
+		}
+		newLines[10000] = "changed"
+
+		oldText, newText := strings.Join(oldLines, "\n")+"\n", strings.Join(newLines, "\n")+"\n"
+		u.So(t, utils.UnifiedDiff("a", "b", oldText, newText), gc.ShouldResemble, []string{
+			"--- a",
+			"+++ b",
+			"@@ -9998,7 +9998,7 @@",
+			" line 9997",
+			" line 9998",
+			" line 9999",
+			"-line 10000",
+			"+changed",
+			" line 10001",
+			" line 10002",
+			" line 10003",
+		})
+	})
+
+	t.Run("should only count the lines of texts too large to diff", func(t *testing.T) {
+		oldLines, newLines := make([]string, 20000), make([]string, 30000)
+		for i := range oldLines {
+			oldLines[i] = fmt.Sprintf("old %d", i)
+		}
+		for i := range newLines {
+			newLines[i] = fmt.Sprintf("new %d", i)
+		}
+
+		oldText, newText := strings.Join(oldLines, "\n")+"\n", strings.Join(newLines, "\n")+"\n"
+		u.So(t, utils.UnifiedDiff("a", "b", oldText, newText), gc.ShouldResemble, []string{
+			"--- a",
+			"+++ b",
+			"source changed (20000 → 30000 lines)",
+		})
+	})
+}
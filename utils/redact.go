@@ -0,0 +1,333 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// RedactedPlaceholderPrefix prefixes every value that has been stripped from a redacted export
+const RedactedPlaceholderPrefix = "__STITCH_REDACTED__:"
+
+// SecretsTemplateFileName is the name of the file listing the values that were stripped from a redacted export
+const SecretsTemplateFileName = "secrets.template.json"
+
+// unfilledTemplateValue is written for every entry of the template, to be replaced with the JSON value of the
+// stripped field
+var unfilledTemplateValue = json.RawMessage("null")
+
+var secretLikeFieldNames = []string{
+	"secret",
+	"password",
+	"token",
+	"apikey",
+	"api_key",
+	"privatekey",
+	"private_key",
+}
+
+// RedactAppZip takes an io.Reader containing exported app zip data and returns new zip data in which private
+// values, secret-like service configuration fields and secrets have been replaced with placeholders. A
+// SecretsTemplateFileName entry listing every placeholder is added to the archive.
+func RedactAppZip(zipData io.Reader) (io.Reader, error) {
+	b, err := ioutil.ReadAll(zipData)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	template := map[string]json.RawMessage{}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for _, zipFile := range r.File {
+		if zipFile.Name == SecretsTemplateFileName {
+			continue
+		}
+
+		contents, err := readZipFile(zipFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if !zipFile.FileInfo().IsDir() {
+			contents, err = redactFile(zipFile.Name, contents, template)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		header := zipFile.FileHeader
+		fw, err := w.CreateHeader(&header)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := fw.Write(contents); err != nil {
+			return nil, err
+		}
+	}
+
+	templateData, err := json.MarshalIndent(template, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	fw, err := w.Create(SecretsTemplateFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fw.Write(templateData); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+// RedactedKeys returns the sorted keys of all redaction placeholders found within the provided app data
+func RedactedKeys(app map[string]interface{}) []string {
+	keys := map[string]struct{}{}
+	walkStrings(app, func(s string) interface{} {
+		if strings.HasPrefix(s, RedactedPlaceholderPrefix) {
+			keys[strings.TrimPrefix(s, RedactedPlaceholderPrefix)] = struct{}{}
+		}
+		return s
+	})
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// ResolveRedactedValues replaces every redaction placeholder within the provided app data with the matching JSON
+// entry in values, so that fields keep their original type. Entries that are null or an empty string are
+// considered missing. It returns an error listing the keys of any placeholders that could not be resolved.
+func ResolveRedactedValues(app map[string]interface{}, values map[string]json.RawMessage) error {
+	missing := map[string]struct{}{}
+	invalid := map[string]struct{}{}
+	walkStrings(app, func(s string) interface{} {
+		if !strings.HasPrefix(s, RedactedPlaceholderPrefix) {
+			return s
+		}
+
+		key := strings.TrimPrefix(s, RedactedPlaceholderPrefix)
+		if IsRedactedValueMissing(values[key]) {
+			missing[key] = struct{}{}
+			return s
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(values[key], &value); err != nil {
+			invalid[key] = struct{}{}
+			return s
+		}
+
+		return value
+	})
+
+	if len(invalid) != 0 {
+		return fmt.Errorf("invalid JSON values for redacted fields: %s", strings.Join(sortedSet(invalid), ", "))
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("missing values for redacted fields: %s", strings.Join(sortedSet(missing), ", "))
+}
+
+// IsRedactedValueMissing returns whether raw, the JSON value provided for a redacted field, leaves it unresolved
+// because it is absent, null or an empty string
+func IsRedactedValueMissing(raw json.RawMessage) bool {
+	switch strings.TrimSpace(string(raw)) {
+	case "", "null", `""`:
+		return true
+	}
+	return false
+}
+
+func redactFile(name string, contents []byte, template map[string]json.RawMessage) ([]byte, error) {
+	if path.Ext(name) != jsonExt || len(contents) == 0 {
+		return contents, nil
+	}
+
+	parts := strings.Split(name, "/")
+
+	var redactFn func(doc map[string]interface{}) bool
+	switch {
+	case len(parts) == 1 && parts[0] == secretsName+jsonExt:
+		redactFn = func(doc map[string]interface{}) bool {
+			return redactAll(name, "", doc, template)
+		}
+	case len(parts) == 2 && parts[0] == valuesName:
+		redactFn = func(doc map[string]interface{}) bool {
+			if private, _ := doc["private"].(bool); !private {
+				return false
+			}
+			if _, ok := doc["value"]; !ok {
+				return false
+			}
+			doc["value"] = placeholder(name, "value", template)
+			return true
+		}
+	case len(parts) == 3 && parts[0] == servicesName && parts[2] == configName+jsonExt:
+		redactFn = func(doc map[string]interface{}) bool {
+			return redactSecretLike(name, "config", doc["config"], template)
+		}
+	case len(parts) == 5 && parts[0] == servicesName && parts[2] == incomingWebhooksName && parts[4] == configName+jsonExt:
+		redactFn = func(doc map[string]interface{}) bool {
+			return redactSecretLike(name, "options", doc["options"], template)
+		}
+	default:
+		return contents, nil
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", name, err)
+	}
+
+	if !redactFn(doc) {
+		return contents, nil
+	}
+
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// redactSecretLike replaces every string field beneath node whose name looks like it holds a secret, including
+// within arrays
+func redactSecretLike(file, fieldPath string, node interface{}, template map[string]json.RawMessage) bool {
+	var redacted bool
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			childPath := fieldPath + "." + key
+			if s, ok := value.(string); ok {
+				if s != "" && isSecretLikeFieldName(key) {
+					v[key] = placeholder(file, childPath, template)
+					redacted = true
+				}
+				continue
+			}
+
+			if list, ok := value.([]interface{}); ok && isSecretLikeFieldName(key) {
+				if redactStrings(file, childPath, list, template) {
+					redacted = true
+				}
+			}
+
+			if redactSecretLike(file, childPath, value, template) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			if redactSecretLike(file, fmt.Sprintf("%s[%d]", fieldPath, i), value, template) {
+				redacted = true
+			}
+		}
+	}
+
+	return redacted
+}
+
+// redactStrings replaces the non-empty strings of list, the value of a secret-like field
+func redactStrings(file, fieldPath string, list []interface{}, template map[string]json.RawMessage) bool {
+	var redacted bool
+	for i, value := range list {
+		if s, ok := value.(string); ok && s != "" {
+			list[i] = placeholder(file, fmt.Sprintf("%s[%d]", fieldPath, i), template)
+			redacted = true
+		}
+	}
+
+	return redacted
+}
+
+// redactAll replaces every leaf value beneath doc
+func redactAll(file, fieldPath string, doc map[string]interface{}, template map[string]json.RawMessage) bool {
+	var redacted bool
+	for key, value := range doc {
+		childPath := key
+		if fieldPath != "" {
+			childPath = fieldPath + "." + key
+		}
+
+		if v, ok := value.(map[string]interface{}); ok {
+			if redactAll(file, childPath, v, template) {
+				redacted = true
+			}
+			continue
+		}
+
+		doc[key] = placeholder(file, childPath, template)
+		redacted = true
+	}
+
+	return redacted
+}
+
+func placeholder(file, fieldPath string, template map[string]json.RawMessage) string {
+	key := file + ":" + fieldPath
+	template[key] = unfilledTemplateValue
+	return RedactedPlaceholderPrefix + key
+}
+
+func isSecretLikeFieldName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, secretLike := range secretLikeFieldNames {
+		if strings.Contains(lower, secretLike) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readZipFile(zipFile *zip.File) ([]byte, error) {
+	fileData, err := zipFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract file %q: %s", zipFile.Name, err)
+	}
+	defer fileData.Close()
+
+	return ioutil.ReadAll(fileData)
+}
+
+// walkStrings calls fn with every string found within node, replacing it with the returned value
+func walkStrings(node interface{}, fn func(s string) interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = walkStrings(value, fn)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = walkStrings(value, fn)
+		}
+	case string:
+		return fn(v)
+	}
+
+	return node
+}
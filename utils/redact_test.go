@@ -0,0 +1,154 @@
+package utils_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func readZip(t *testing.T, data []byte) map[string]map[string]interface{} {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	u.So(t, err, gc.ShouldBeNil)
+
+	files := map[string]map[string]interface{}{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		u.So(t, err, gc.ShouldBeNil)
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		u.So(t, err, gc.ShouldBeNil)
+
+		doc := map[string]interface{}{}
+		u.So(t, json.Unmarshal(b, &doc), gc.ShouldBeNil)
+		files[f.Name] = doc
+	}
+	return files
+}
+
+func TestRedactAppZip(t *testing.T) {
+	zipData := u.NewZip(map[string]string{
+		"stitch.json":                                     `{"name": "my-app"}`,
+		"values/public.json":                              `{"name": "public", "value": "visible", "private": false}`,
+		"values/private.json":                             `{"name": "private", "value": "hidden", "private": true}`,
+		"services/svc/config.json":                        `{"name": "svc", "type": "aws", "config": {"accessKeyId": "id", "secretAccessKey": "shh", "region": "us-east-1"}}`,
+		"services/svc/rules/rule.json":                    `{"name": "rule", "actions": []}`,
+		"services/svc/incoming_webhooks/hook/config.json": `{"name": "hook", "options": {"secret": "abc"}}`,
+		"services/db/config.json":                         `{"name": "db", "type": "http", "config": {"users": [{"name": "a", "password": "pw"}], "tokens": ["t1", ""]}}`,
+		"secrets.json":                                    `{"services": {"svc": {"secretAccessKey": "shh"}}}`,
+	})
+
+	redacted, err := utils.RedactAppZip(bytes.NewReader(zipData))
+	u.So(t, err, gc.ShouldBeNil)
+
+	b, err := ioutil.ReadAll(redacted)
+	u.So(t, err, gc.ShouldBeNil)
+	files := readZip(t, b)
+
+	t.Run("should leave public values and unrelated files untouched", func(t *testing.T) {
+		u.So(t, files["values/public.json"]["value"], gc.ShouldEqual, "visible")
+		u.So(t, files["stitch.json"]["name"], gc.ShouldEqual, "my-app")
+		u.So(t, files["services/svc/rules/rule.json"]["name"], gc.ShouldEqual, "rule")
+	})
+
+	t.Run("should replace private values with placeholders", func(t *testing.T) {
+		u.So(t, files["values/private.json"]["value"], gc.ShouldEqual, utils.RedactedPlaceholderPrefix+"values/private.json:value")
+	})
+
+	t.Run("should replace secret-like service and webhook fields with placeholders", func(t *testing.T) {
+		config := files["services/svc/config.json"]["config"].(map[string]interface{})
+		u.So(t, config["secretAccessKey"], gc.ShouldEqual, utils.RedactedPlaceholderPrefix+"services/svc/config.json:config.secretAccessKey")
+		u.So(t, config["region"], gc.ShouldEqual, "us-east-1")
+		u.So(t, config["accessKeyId"], gc.ShouldEqual, "id")
+
+		options := files["services/svc/incoming_webhooks/hook/config.json"]["options"].(map[string]interface{})
+		u.So(t, options["secret"], gc.ShouldEqual, utils.RedactedPlaceholderPrefix+"services/svc/incoming_webhooks/hook/config.json:options.secret")
+	})
+
+	t.Run("should replace secret-like fields within arrays with placeholders", func(t *testing.T) {
+		config := files["services/db/config.json"]["config"].(map[string]interface{})
+		user := config["users"].([]interface{})[0].(map[string]interface{})
+		u.So(t, user["password"], gc.ShouldEqual, utils.RedactedPlaceholderPrefix+"services/db/config.json:config.users[0].password")
+		u.So(t, user["name"], gc.ShouldEqual, "a")
+		u.So(t, config["tokens"], gc.ShouldResemble, []interface{}{utils.RedactedPlaceholderPrefix + "services/db/config.json:config.tokens[0]", ""})
+	})
+
+	t.Run("should replace every secret with a placeholder", func(t *testing.T) {
+		svc := files["secrets.json"]["services"].(map[string]interface{})["svc"].(map[string]interface{})
+		u.So(t, svc["secretAccessKey"], gc.ShouldEqual, utils.RedactedPlaceholderPrefix+"secrets.json:services.svc.secretAccessKey")
+	})
+
+	t.Run("should write a template listing every placeholder", func(t *testing.T) {
+		template := files[utils.SecretsTemplateFileName]
+		u.So(t, template, gc.ShouldHaveLength, 6)
+		u.So(t, template, gc.ShouldContainKey, "values/private.json:value")
+		u.So(t, template["values/private.json:value"], gc.ShouldBeNil)
+		u.So(t, template, gc.ShouldContainKey, "secrets.json:services.svc.secretAccessKey")
+	})
+}
+
+func TestResolveRedactedValues(t *testing.T) {
+	t.Run("should substitute provided values", func(t *testing.T) {
+		app, err := utils.UnmarshalFromDir("../testdata/redacted_app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, utils.RedactedKeys(app), gc.ShouldResemble, []string{"values/value_b.json:value"})
+
+		err = utils.ResolveRedactedValues(app, map[string]json.RawMessage{"values/value_b.json:value": json.RawMessage(`"BBBBBB"`)})
+		u.So(t, err, gc.ShouldBeNil)
+
+		u.So(t, utils.RedactedKeys(app), gc.ShouldBeEmpty)
+		value := app["values"].([]interface{})[0].(map[string]interface{})
+		u.So(t, value["value"], gc.ShouldEqual, "BBBBBB")
+	})
+
+	t.Run("should report missing values", func(t *testing.T) {
+		app, err := utils.UnmarshalFromDir("../testdata/redacted_app")
+		u.So(t, err, gc.ShouldBeNil)
+
+		for _, raw := range []string{`""`, `null`} {
+			err = utils.ResolveRedactedValues(app, map[string]json.RawMessage{"values/value_b.json:value": json.RawMessage(raw)})
+			u.So(t, err, gc.ShouldBeError, "missing values for redacted fields: values/value_b.json:value")
+		}
+	})
+
+	t.Run("should report values that are not JSON", func(t *testing.T) {
+		app, err := utils.UnmarshalFromDir("../testdata/redacted_app")
+		u.So(t, err, gc.ShouldBeNil)
+
+		err = utils.ResolveRedactedValues(app, map[string]json.RawMessage{"values/value_b.json:value": json.RawMessage(`BBBBBB`)})
+		u.So(t, err, gc.ShouldBeError, "invalid JSON values for redacted fields: values/value_b.json:value")
+	})
+
+	t.Run("should restore non-string values with their original type", func(t *testing.T) {
+		zipData := u.NewZip(map[string]string{
+			"stitch.json":          `{"name": "my-app"}`,
+			"values/limit.json":    `{"name": "limit", "value": 42, "private": true}`,
+			"values/settings.json": `{"name": "settings", "value": {"enabled": true, "hosts": ["a", "b"]}, "private": true}`,
+		})
+
+		redacted, err := utils.RedactAppZip(bytes.NewReader(zipData))
+		u.So(t, err, gc.ShouldBeNil)
+		app, err := utils.UnmarshalFromZip(redacted)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, utils.RedactedKeys(app), gc.ShouldResemble, []string{"values/limit.json:value", "values/settings.json:value"})
+
+		err = utils.ResolveRedactedValues(app, map[string]json.RawMessage{
+			"values/limit.json:value":    json.RawMessage(`42`),
+			"values/settings.json:value": json.RawMessage(`{"enabled": true, "hosts": ["a", "b"]}`),
+		})
+		u.So(t, err, gc.ShouldBeNil)
+
+		values := map[string]interface{}{}
+		for _, value := range app["values"].([]interface{}) {
+			doc := value.(map[string]interface{})
+			values[doc["name"].(string)] = doc["value"]
+		}
+		u.So(t, values["limit"], gc.ShouldEqual, float64(42))
+		u.So(t, values["settings"], gc.ShouldResemble, map[string]interface{}{"enabled": true, "hosts": []interface{}{"a", "b"}})
+	})
+}
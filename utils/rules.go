@@ -0,0 +1,471 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule issue severities
+const (
+	RuleIssueError   = "error"
+	RuleIssueWarning = "warning"
+)
+
+// mongoDBServiceTypes are the service types whose rules apply to a database.collection namespace
+var mongoDBServiceTypes = map[string]bool{
+	"mongodb":       true,
+	"mongodb-atlas": true,
+}
+
+// serviceRuleActions lists the actions a rule may allow for the service types that are checked
+var serviceRuleActions = map[string]map[string]bool{
+	"http":   {"get": true, "post": true, "put": true, "patch": true, "delete": true, "head": true},
+	"twilio": {"send": true},
+}
+
+// ruleExpansionRoots are the expansions that may start a %% string, e.g. %%user.id
+var ruleExpansionRoots = map[string]bool{
+	"true":     true,
+	"false":    true,
+	"user":     true,
+	"root":     true,
+	"prevRoot": true,
+	"this":     true,
+	"prev":     true,
+	"values":   true,
+	"request":  true,
+	"args":     true,
+}
+
+// ruleUserFields are the fields of %%user
+var ruleUserFields = map[string]bool{
+	"id":          true,
+	"type":        true,
+	"data":        true,
+	"identities":  true,
+	"custom_data": true,
+}
+
+// ruleQueryOperators are the MongoDB query operators that may be used within rule expressions, all of
+// which are supported by EvaluateRule. Expressions are combined with %and, %or and %nor instead of their
+// query operator counterparts
+var ruleQueryOperators = map[string]bool{
+	"$eq": true, "$ne": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$in": true, "$nin": true, "$exists": true, "$all": true, "$size": true,
+	"$elemMatch": true, "$regex": true, "$options": true, "$not": true,
+}
+
+var (
+	invalidDatabaseNameChars   = regexp.MustCompile(`[/\\. "$*<>:|?]`)
+	invalidCollectionNameChars = regexp.MustCompile(`[$\x00]`)
+)
+
+// RuleIssue is a problem found within a rule file
+type RuleIssue struct {
+	Path     string
+	Location string
+	Severity string
+	Message  string
+}
+
+func (ri RuleIssue) String() string {
+	if ri.Location == "" {
+		return fmt.Sprintf("%s: %s: %s", ri.Path, ri.Severity, ri.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", ri.Path, ri.Location, ri.Severity, ri.Message)
+}
+
+// LintRules checks the rules of every service within the app directory at appPath without contacting
+// Stitch. Namespaces are checked against the type of their service, expressions are checked for unknown
+// expansions and malformed operators, and roles granting write access to everyone are flagged
+func LintRules(appPath string) ([]RuleIssue, error) {
+	return LintRulesFromFileSystem(OSFileSystem{}, appPath)
+}
+
+// LintRulesFromFileSystem checks the rules of every service within the app directory at appPath of fs, as
+// LintRules does
+func LintRulesFromFileSystem(fs FileSystem, appPath string) ([]RuleIssue, error) {
+	services, err := ReadLocalServicesFromFileSystem(fs, appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []RuleIssue
+	for _, service := range services {
+		ruleFiles, err := service.RuleFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces := map[string]string{}
+		for _, path := range ruleFiles {
+			l := &ruleLinter{path: path}
+
+			data, err := fs.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			var rule map[string]interface{}
+			if err := json.Unmarshal(data, &rule); err != nil {
+				l.errorf("", "invalid JSON: %s", err)
+				issues = append(issues, l.issues...)
+				continue
+			}
+
+			if mongoDBServiceTypes[service.Type()] {
+				namespace := l.mongoDBRule(rule)
+				if other, ok := namespaces[namespace]; ok && namespace != "" {
+					l.errorf("", "namespace %s is also defined in %s", namespace, filepath.Base(other))
+				}
+				namespaces[namespace] = path
+			} else {
+				l.serviceRule(service.Type(), rule)
+			}
+
+			issues = append(issues, l.issues...)
+		}
+	}
+
+	return issues, nil
+}
+
+type ruleLinter struct {
+	path   string
+	issues []RuleIssue
+}
+
+func (l *ruleLinter) errorf(location, format string, args ...interface{}) {
+	l.issues = append(l.issues, RuleIssue{l.path, location, RuleIssueError, fmt.Sprintf(format, args...)})
+}
+
+func (l *ruleLinter) warnf(location, format string, args ...interface{}) {
+	l.issues = append(l.issues, RuleIssue{l.path, location, RuleIssueWarning, fmt.Sprintf(format, args...)})
+}
+
+// mongoDBRule lints the rule of a database.collection namespace and returns the namespace
+func (l *ruleLinter) mongoDBRule(rule map[string]interface{}) string {
+	database, _ := rule["database"].(string)
+	collection, _ := rule["collection"].(string)
+
+	switch {
+	case database == "":
+		l.errorf("database", "a database name is required")
+	case invalidDatabaseNameChars.MatchString(database):
+		l.errorf("database", "invalid database name %q", database)
+	}
+
+	switch {
+	case collection == "":
+		l.errorf("collection", "a collection name is required")
+	case invalidCollectionNameChars.MatchString(collection) || strings.HasPrefix(collection, "system."):
+		l.errorf("collection", "invalid collection name %q", collection)
+	}
+
+	for _, field := range []string{"actions", "when"} {
+		if _, ok := rule[field]; ok {
+			l.errorf(field, "MongoDB rules do not have %s, use roles instead", field)
+		}
+	}
+
+	namespace := ""
+	if database != "" && collection != "" {
+		namespace = database + "." + collection
+	}
+
+	roles, ok := rule["roles"].([]interface{})
+	if !ok {
+		if _, exists := rule["roles"]; exists {
+			l.errorf("roles", "roles must be an array")
+		}
+		roles = nil
+	}
+
+	roleNames := map[string]bool{}
+	for i, r := range roles {
+		location := fmt.Sprintf("roles[%d]", i)
+
+		role, ok := r.(map[string]interface{})
+		if !ok {
+			l.errorf(location, "a role must be an object")
+			continue
+		}
+
+		name, _ := role["name"].(string)
+		if name == "" {
+			l.errorf(location, "a role name is required")
+		} else if roleNames[name] {
+			l.errorf(location, "role %q is defined more than once", name)
+		}
+		roleNames[name] = true
+
+		for _, field := range []string{"apply_when", "read", "write", "insert", "delete"} {
+			if value, ok := role[field]; ok {
+				l.expression(location+"."+field, value)
+			}
+		}
+
+		if fields, ok := role["fields"].(map[string]interface{}); ok {
+			for _, fieldName := range sortedKeys(fields) {
+				l.fieldPermissions(fmt.Sprintf("%s.fields.%s", location, fieldName), fields[fieldName])
+			}
+		}
+
+		if additionalFields, ok := role["additional_fields"]; ok {
+			l.fieldPermissions(location+".additional_fields", additionalFields)
+		}
+
+		if appliesToEveryone(role["apply_when"]) && isTrueExpression(role["write"]) {
+			l.warnf(location, "role %q grants write access to every document of %s to every user", name, namespace)
+		}
+	}
+
+	if filters, ok := rule["filters"].([]interface{}); ok {
+		for i, f := range filters {
+			filter, _ := f.(map[string]interface{})
+			for _, field := range []string{"when", "match_expression"} {
+				if value, ok := filter[field]; ok {
+					l.expression(fmt.Sprintf("filters[%d].%s", i, field), value)
+				}
+			}
+		}
+	}
+
+	return namespace
+}
+
+func (l *ruleLinter) fieldPermissions(location string, value interface{}) {
+	permissions, ok := value.(map[string]interface{})
+	if !ok {
+		l.errorf(location, "field permissions must be an object")
+		return
+	}
+
+	for _, field := range []string{"read", "write"} {
+		if value, ok := permissions[field]; ok {
+			l.expression(location+"."+field, value)
+		}
+	}
+}
+
+// serviceRule lints the rule of a service other than MongoDB, which lists the actions it allows
+func (l *ruleLinter) serviceRule(serviceType string, rule map[string]interface{}) {
+	for _, field := range []string{"database", "collection", "roles"} {
+		if _, ok := rule[field]; ok {
+			l.errorf(field, "rules of %s services do not apply to a namespace and cannot have %s", serviceType, field)
+		}
+	}
+
+	if name, _ := rule["name"].(string); name == "" {
+		l.errorf("name", "a rule name is required")
+	}
+
+	actions, ok := rule["actions"].([]interface{})
+	if !ok {
+		l.errorf("actions", "actions must be an array")
+	}
+
+	for i, a := range actions {
+		action, ok := a.(string)
+		if !ok {
+			l.errorf(fmt.Sprintf("actions[%d]", i), "an action must be a string")
+			continue
+		}
+
+		if known, ok := serviceRuleActions[serviceType]; ok && !known[action] {
+			l.errorf(fmt.Sprintf("actions[%d]", i), "unknown action %q for %s services", action, serviceType)
+		}
+	}
+
+	when, ok := rule["when"]
+	if !ok {
+		return
+	}
+
+	// the when expression is often stored as a JSON string
+	if encoded, ok := when.(string); ok && strings.HasPrefix(strings.TrimSpace(encoded), "{") {
+		if err := json.Unmarshal([]byte(encoded), &when); err != nil {
+			l.errorf("when", "invalid JSON expression: %s", err)
+			return
+		}
+	}
+
+	l.expression("when", when)
+}
+
+// expression lints a rule expression, recursing into arrays and objects
+func (l *ruleLinter) expression(location string, expr interface{}) {
+	switch value := expr.(type) {
+	case string:
+		l.expansion(location, value)
+	case []interface{}:
+		for i, item := range value {
+			l.expression(fmt.Sprintf("%s[%d]", location, i), item)
+		}
+	case map[string]interface{}:
+		for _, key := range sortedKeys(value) {
+			operand := value[key]
+			keyLocation := location + "." + key
+
+			switch {
+			case strings.HasPrefix(key, "%%"):
+				l.expansion(location, key)
+				l.expression(keyLocation, operand)
+			case strings.HasPrefix(key, "%"):
+				l.operator(keyLocation, key, operand)
+			case strings.HasPrefix(key, "$"):
+				switch {
+				case key == "$and" || key == "$or" || key == "$nor":
+					l.errorf(keyLocation, "unknown query operator %q, did you mean %q?", key, "%"+key[1:])
+				case !ruleQueryOperators[key]:
+					l.errorf(keyLocation, "unknown query operator %q", key)
+				}
+				l.expression(keyLocation, operand)
+			default:
+				l.expression(keyLocation, operand)
+			}
+		}
+	}
+}
+
+// expansion lints a string that may be a %% expansion
+func (l *ruleLinter) expansion(location, value string) {
+	if !strings.HasPrefix(value, "%") {
+		return
+	}
+
+	if !strings.HasPrefix(value, "%%") {
+		root := strings.SplitN(value[1:], ".", 2)[0]
+		if ruleExpansionRoots[root] {
+			l.errorf(location, "%q is not an expansion, did you mean %q?", value, "%"+value)
+		}
+		return
+	}
+
+	parts := strings.Split(value[2:], ".")
+	root, path := parts[0], parts[1:]
+
+	if !ruleExpansionRoots[root] {
+		l.errorf(location, "unknown expansion %q", value)
+		return
+	}
+
+	for _, part := range path {
+		if part == "" {
+			l.errorf(location, "invalid expansion %q", value)
+			return
+		}
+	}
+
+	switch root {
+	case "true", "false":
+		if len(path) != 0 {
+			l.errorf(location, "%%%%%s cannot be followed by a path in %q", root, value)
+		}
+	case "user":
+		if len(path) != 0 && !ruleUserFields[path[0]] {
+			l.errorf(location, "unknown field %q of %%%%user in %q, must be one of id, type, data, identities or custom_data", path[0], value)
+		}
+	case "values":
+		if len(path) == 0 {
+			l.errorf(location, "%%%%values must be followed by the name of a value")
+		}
+	}
+}
+
+// operator lints the operand of a % operator
+func (l *ruleLinter) operator(location, operator string, operand interface{}) {
+	switch operator {
+	case "%and", "%or", "%nor":
+		expressions, ok := operand.([]interface{})
+		if !ok || len(expressions) == 0 {
+			l.errorf(location, "%s expects a non-empty array of expressions", operator)
+			return
+		}
+
+		for i, expr := range expressions {
+			if _, ok := expr.(map[string]interface{}); !ok {
+				l.errorf(fmt.Sprintf("%s[%d]", location, i), "%s expects a non-empty array of expressions", operator)
+				continue
+			}
+			l.expression(fmt.Sprintf("%s[%d]", location, i), expr)
+		}
+	case "%not":
+		if _, ok := operand.(map[string]interface{}); !ok {
+			l.errorf(location, "%%not expects an expression")
+			return
+		}
+		l.expression(location, operand)
+	case "%exists":
+		if _, ok := operand.(bool); !ok && !isExpansion(operand) {
+			l.errorf(location, "%%exists expects a boolean")
+		}
+	case "%in", "%nin":
+		if _, ok := operand.([]interface{}); !ok && !isExpansion(operand) {
+			l.errorf(location, "%s expects an array", operator)
+			return
+		}
+		l.expression(location, operand)
+	case "%stringContains":
+		if _, ok := operand.(string); !ok {
+			l.errorf(location, "%%stringContains expects a string")
+			return
+		}
+		l.expression(location, operand)
+	case "%function":
+		function, ok := operand.(map[string]interface{})
+		if !ok {
+			l.errorf(location, "%%function expects an object with a name and arguments")
+			return
+		}
+
+		if name, _ := function["name"].(string); name == "" {
+			l.errorf(location, "%%function requires the name of a function")
+		}
+
+		if arguments, ok := function["arguments"]; ok {
+			if _, ok := arguments.([]interface{}); !ok {
+				l.errorf(location+".arguments", "the arguments of %%function must be an array")
+				return
+			}
+			l.expression(location+".arguments", arguments)
+		}
+	default:
+		l.errorf(location, "unknown operator %q", operator)
+	}
+}
+
+func isExpansion(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, "%%")
+}
+
+// isTrueExpression returns whether an expression is unconditionally true
+func isTrueExpression(expr interface{}) bool {
+	return expr == true || expr == "%%true"
+}
+
+// appliesToEveryone returns whether an apply_when expression matches every user
+func appliesToEveryone(expr interface{}) bool {
+	if expr == nil || isTrueExpression(expr) {
+		return true
+	}
+
+	doc, ok := expr.(map[string]interface{})
+	return ok && len(doc) == 0
+}
+
+func sortedKeys(doc map[string]interface{}) []string {
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,700 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Actions that can be evaluated against MongoDB rules
+const (
+	RuleActionRead   = "read"
+	RuleActionWrite  = "write"
+	RuleActionInsert = "insert"
+	RuleActionDelete = "delete"
+)
+
+// missingValue is what an expansion resolves to when its path does not exist
+type missingValue struct{}
+
+var missing = missingValue{}
+
+// RuleRequest is a request to evaluate against the rule of a namespace
+type RuleRequest struct {
+	Action string
+	User   map[string]interface{}
+	// Doc is the document being accessed. For writes it is the document as it would be after the write
+	Doc map[string]interface{}
+	// PrevDoc is the document before a write, if any
+	PrevDoc map[string]interface{}
+	Values  map[string]interface{}
+	// CallFunction runs the named function for %function operators
+	CallFunction func(name string, args []interface{}) (interface{}, error)
+}
+
+// FieldPermission is the outcome of evaluating the permission of a single field
+type FieldPermission struct {
+	Field   string
+	Allowed bool
+	// Source is the part of the role that granted or denied the permission
+	Source string
+}
+
+// RuleEvaluation is the outcome of evaluating a RuleRequest
+type RuleEvaluation struct {
+	// Role is the name of the first role whose apply_when matched, or empty if none did
+	Role    string
+	Allowed bool
+	// Source is the part of the role that decided the document-level outcome
+	Source string
+	Fields []FieldPermission
+}
+
+// FindNamespaceRule returns the rule of the given database.collection namespace among the rules of service
+func FindNamespaceRule(service *LocalService, namespace string) (map[string]interface{}, error) {
+	if !mongoDBServiceTypes[service.Type()] {
+		return nil, fmt.Errorf("service %q is of type %s: only the rules of MongoDB services apply to namespaces", service.Name(), service.Type())
+	}
+
+	ruleFiles, err := service.RuleFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range ruleFiles {
+		var rule map[string]interface{}
+		if err := readAndUnmarshalJSONInto(path, &rule); err != nil {
+			return nil, err
+		}
+
+		database, _ := rule["database"].(string)
+		collection, _ := rule["collection"].(string)
+		if database+"."+collection == namespace {
+			return rule, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no rule found for namespace %s in %s", namespace, service.RulesDirectory())
+}
+
+// EvaluateRule evaluates the request against the roles of a MongoDB namespace rule. The first role whose
+// apply_when matches decides: delete and insert use the role's document-level permission, while read
+// and write are evaluated for each top-level field of the document, falling back from the role's
+// document-level permission to its fields and then its additional_fields
+func EvaluateRule(rule map[string]interface{}, request RuleRequest) (*RuleEvaluation, error) {
+	switch request.Action {
+	case RuleActionRead, RuleActionWrite, RuleActionInsert, RuleActionDelete:
+	default:
+		return nil, fmt.Errorf("unknown action %q, must be one of read, write, insert or delete", request.Action)
+	}
+
+	e := &ruleEvaluator{request: request}
+
+	roles, _ := rule["roles"].([]interface{})
+	for i, r := range roles {
+		role, _ := r.(map[string]interface{})
+		name, _ := role["name"].(string)
+
+		applies, err := e.evaluate(role["apply_when"], nil)
+		if err != nil {
+			return nil, fmt.Errorf("roles[%d].apply_when: %s", i, err)
+		}
+
+		if role["apply_when"] == nil || applies {
+			evaluation, err := e.evaluateRole(role)
+			if err != nil {
+				return nil, fmt.Errorf("role %q: %s", name, err)
+			}
+			evaluation.Role = name
+			return evaluation, nil
+		}
+	}
+
+	return &RuleEvaluation{Source: "no role applies"}, nil
+}
+
+type ruleEvaluator struct {
+	request RuleRequest
+}
+
+// fieldContext is the field being evaluated, which %%this and %%prev refer to
+type fieldContext struct {
+	name string
+}
+
+func (e *ruleEvaluator) evaluateRole(role map[string]interface{}) (*RuleEvaluation, error) {
+	switch e.request.Action {
+	case RuleActionDelete:
+		allowed, err := e.evaluate(role[RuleActionDelete], nil)
+		if err != nil {
+			return nil, err
+		}
+		return &RuleEvaluation{Allowed: allowed, Source: RuleActionDelete}, nil
+	case RuleActionInsert:
+		allowed, err := e.evaluate(role[RuleActionInsert], nil)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return &RuleEvaluation{Source: RuleActionInsert}, nil
+		}
+
+		// inserting a document also requires write access to each of its fields
+		evaluation, err := e.evaluateFields(role, RuleActionWrite)
+		if err != nil {
+			return nil, err
+		}
+		evaluation.Source = RuleActionInsert + " and " + evaluation.Source
+		return evaluation, nil
+	}
+
+	return e.evaluateFields(role, e.request.Action)
+}
+
+// evaluateFields evaluates the read or write permission of every top-level field of the document
+func (e *ruleEvaluator) evaluateFields(role map[string]interface{}, action string) (*RuleEvaluation, error) {
+	documentLevel, err := e.permission(role, action, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, _ := role["fields"].(map[string]interface{})
+	additionalFields, _ := role["additional_fields"].(map[string]interface{})
+
+	evaluation := &RuleEvaluation{Allowed: true, Source: "field permissions"}
+	if documentLevel {
+		evaluation.Source = "document-level " + action
+	}
+
+	names := make([]string, 0, len(e.request.Doc))
+	for name := range e.request.Doc {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := FieldPermission{Field: name}
+		ctx := &fieldContext{name: name}
+
+		switch permissions, ok := fields[name].(map[string]interface{}); {
+		case documentLevel:
+			field.Allowed, field.Source = true, "document-level "+action
+		case ok:
+			field.Source = "fields." + name
+			if field.Allowed, err = e.permission(permissions, action, ctx); err != nil {
+				return nil, fmt.Errorf("fields.%s: %s", name, err)
+			}
+		case additionalFields != nil:
+			field.Source = "additional_fields"
+			if field.Allowed, err = e.permission(additionalFields, action, ctx); err != nil {
+				return nil, fmt.Errorf("additional_fields: %s", err)
+			}
+		default:
+			field.Source = "no permission"
+		}
+
+		evaluation.Allowed = evaluation.Allowed && field.Allowed
+		evaluation.Fields = append(evaluation.Fields, field)
+	}
+
+	return evaluation, nil
+}
+
+// permission evaluates the read or write permission of a role or field. Write permission implies read
+func (e *ruleEvaluator) permission(permissions map[string]interface{}, action string, ctx *fieldContext) (bool, error) {
+	allowed, err := e.evaluate(permissions[action], ctx)
+	if err != nil || allowed || action != RuleActionRead {
+		return allowed, err
+	}
+
+	return e.evaluate(permissions[RuleActionWrite], ctx)
+}
+
+// evaluate evaluates an expression to a boolean. A missing expression is false
+func (e *ruleEvaluator) evaluate(expr interface{}, ctx *fieldContext) (bool, error) {
+	switch value := expr.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return value, nil
+	case string:
+		resolved, err := e.expand(value, ctx)
+		if err != nil {
+			return false, err
+		}
+		allowed, ok := resolved.(bool)
+		if !ok {
+			return false, fmt.Errorf("%q does not evaluate to a boolean", value)
+		}
+		return allowed, nil
+	case map[string]interface{}:
+		for _, key := range sortedKeys(value) {
+			matched, err := e.evaluateKey(key, value[key], ctx)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	return false, fmt.Errorf("invalid expression %v", expr)
+}
+
+// evaluateKey evaluates a single key of an expression object against its operand
+func (e *ruleEvaluator) evaluateKey(key string, operand interface{}, ctx *fieldContext) (bool, error) {
+	switch key {
+	case "%and", "%or", "%nor":
+		expressions, ok := operand.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s expects an array of expressions", key)
+		}
+
+		for _, expr := range expressions {
+			matched, err := e.evaluate(expr, ctx)
+			if err != nil {
+				return false, err
+			}
+			if key == "%and" && !matched {
+				return false, nil
+			}
+			if key != "%and" && matched {
+				return key == "%or", nil
+			}
+		}
+		return key != "%or", nil
+	case "%not":
+		matched, err := e.evaluate(operand, ctx)
+		return !matched, err
+	case "%function":
+		result, err := e.callFunction(operand, ctx)
+		if err != nil {
+			return false, err
+		}
+		return truthy(result), nil
+	}
+
+	var value interface{}
+	if strings.HasPrefix(key, "%%") {
+		expanded, err := e.expand(key, ctx)
+		if err != nil {
+			return false, err
+		}
+		value = expanded
+	} else {
+		// a plain key refers to a field of the document
+		value = lookupPath(e.request.Doc, strings.Split(key, "."))
+	}
+
+	return e.match(value, operand, ctx)
+}
+
+// match returns whether value satisfies operand, which is either an object of operators or a value
+// to compare against
+func (e *ruleEvaluator) match(value, operand interface{}, ctx *fieldContext) (bool, error) {
+	operators, ok := operand.(map[string]interface{})
+	if !ok || len(operators) == 0 || !isOperatorDocument(operators) {
+		expected, err := e.resolve(operand, ctx)
+		if err != nil {
+			return false, err
+		}
+		return valuesEqual(value, expected), nil
+	}
+
+	for _, operator := range sortedKeys(operators) {
+		var matched bool
+		var err error
+
+		switch operator {
+		case "$options":
+			// applied along with $regex
+			continue
+		case "$not":
+			matched, err = e.match(value, operators[operator], ctx)
+			matched = !matched
+		case "$elemMatch":
+			matched, err = e.elemMatch(value, operators[operator], ctx)
+		case "$regex":
+			matched, err = e.matchRegex(value, operators[operator], operators["$options"], ctx)
+		default:
+			var argument interface{}
+			if argument, err = e.resolve(operators[operator], ctx); err == nil {
+				matched, err = applyOperator(operator, value, argument)
+			}
+		}
+
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// elemMatch returns whether an element of the array value satisfies query, which is either an object of
+// operators or of conditions on the fields of the element
+func (e *ruleEvaluator) elemMatch(value, query interface{}, ctx *fieldContext) (bool, error) {
+	conditions, ok := query.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("$elemMatch expects an object")
+	}
+
+	elements, ok := value.([]interface{})
+	if !ok {
+		return false, nil
+	}
+
+	for _, element := range elements {
+		matched, err := e.matchElement(element, conditions, ctx)
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+
+	return false, nil
+}
+
+func (e *ruleEvaluator) matchElement(element interface{}, conditions map[string]interface{}, ctx *fieldContext) (bool, error) {
+	if isOperatorDocument(conditions) {
+		return e.match(element, conditions, ctx)
+	}
+
+	for _, key := range sortedKeys(conditions) {
+		matched, err := e.match(lookupPath(element, strings.Split(key, ".")), conditions[key], ctx)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// matchRegex returns whether value, or any of its elements if it is an array, is a string matching the
+// pattern with the given $options
+func (e *ruleEvaluator) matchRegex(value, pattern, options interface{}, ctx *fieldContext) (bool, error) {
+	resolved, err := e.resolve(pattern, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	expr, ok := resolved.(string)
+	if !ok {
+		return false, fmt.Errorf("$regex expects a string")
+	}
+
+	if options != nil {
+		flags, ok := options.(string)
+		if !ok || strings.Trim(flags, "ims") != "" {
+			return false, fmt.Errorf("$options only supports the i, m and s flags")
+		}
+		if flags != "" {
+			expr = "(?" + flags + ")" + expr
+		}
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid $regex: %s", err)
+	}
+
+	for _, v := range arrayOrValue(value) {
+		if s, ok := v.(string); ok && re.MatchString(s) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// resolve replaces every expansion within a value
+func (e *ruleEvaluator) resolve(value interface{}, ctx *fieldContext) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return e.expand(v, ctx)
+	case []interface{}:
+		resolved := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			r, err := e.resolve(item, ctx)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, r)
+		}
+		return resolved, nil
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			r, err := e.resolve(item, ctx)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = r
+		}
+		return resolved, nil
+	}
+
+	return value, nil
+}
+
+// expand resolves a %% expansion, or returns any other string as is
+func (e *ruleEvaluator) expand(value string, ctx *fieldContext) (interface{}, error) {
+	if !strings.HasPrefix(value, "%%") {
+		return value, nil
+	}
+
+	parts := strings.Split(value[2:], ".")
+	root, path := parts[0], parts[1:]
+
+	var base interface{}
+	switch root {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "user":
+		base = e.request.User
+	case "root":
+		base = e.request.Doc
+	case "prevRoot":
+		base = e.request.PrevDoc
+	case "values":
+		base = e.request.Values
+	case "this", "prev":
+		if ctx == nil {
+			return nil, fmt.Errorf("%q can only be used in field permissions", value)
+		}
+		doc := e.request.Doc
+		if root == "prev" {
+			doc = e.request.PrevDoc
+		}
+		base = lookupPath(doc, []string{ctx.name})
+	case "request", "args":
+		return missing, nil
+	default:
+		return nil, fmt.Errorf("unknown expansion %q", value)
+	}
+
+	if base == nil {
+		return missing, nil
+	}
+
+	return lookupPath(base, path), nil
+}
+
+func (e *ruleEvaluator) callFunction(operand interface{}, ctx *fieldContext) (interface{}, error) {
+	function, _ := operand.(map[string]interface{})
+	name, _ := function["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("%%function requires the name of a function")
+	}
+
+	if e.request.CallFunction == nil {
+		return nil, fmt.Errorf("cannot call function %q", name)
+	}
+
+	resolved, err := e.resolve(function["arguments"], ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args, _ := resolved.([]interface{})
+	for i, arg := range args {
+		if arg == missing {
+			args[i] = nil
+		}
+	}
+
+	return e.request.CallFunction(name, args)
+}
+
+// lookupPath walks a path of object keys and array indexes, returning missing if it does not exist
+func lookupPath(value interface{}, path []string) interface{} {
+	for _, part := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return missing
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return missing
+			}
+			value = v[index]
+		default:
+			return missing
+		}
+	}
+
+	return value
+}
+
+func isOperatorDocument(doc map[string]interface{}) bool {
+	for key := range doc {
+		if !strings.HasPrefix(key, "%") && !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+func applyOperator(operator string, value, argument interface{}) (bool, error) {
+	switch operator {
+	case "%exists", "$exists":
+		exists, ok := argument.(bool)
+		if !ok {
+			return false, fmt.Errorf("%s expects a boolean", operator)
+		}
+		return (value != missing) == exists, nil
+	case "%in", "$in", "%nin", "$nin":
+		candidates, ok := argument.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s expects an array", operator)
+		}
+		found := containsValue(candidates, value)
+		if operator == "%in" || operator == "$in" {
+			return found, nil
+		}
+		return !found, nil
+	case "%stringContains":
+		s, ok := value.(string)
+		substr, isString := argument.(string)
+		return ok && isString && strings.Contains(s, substr), nil
+	case "$eq":
+		return valuesEqual(value, argument), nil
+	case "$ne":
+		return !valuesEqual(value, argument), nil
+	case "$all":
+		required, ok := argument.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s expects an array", operator)
+		}
+		for _, r := range required {
+			if !containsValue([]interface{}{r}, value) {
+				return false, nil
+			}
+		}
+		return len(required) != 0, nil
+	case "$size":
+		size, ok := argument.(float64)
+		if !ok {
+			return false, fmt.Errorf("%s expects a number", operator)
+		}
+		array, isArray := value.([]interface{})
+		return isArray && float64(len(array)) == size, nil
+	case "$gt", "$gte", "$lt", "$lte":
+		cmp, ok := compareValues(value, argument)
+		if !ok {
+			return false, nil
+		}
+		switch operator {
+		case "$gt":
+			return cmp > 0, nil
+		case "$gte":
+			return cmp >= 0, nil
+		case "$lt":
+			return cmp < 0, nil
+		}
+		return cmp <= 0, nil
+	}
+
+	return false, fmt.Errorf("unsupported operator %q", operator)
+}
+
+// containsValue returns whether value, or any of its elements if it is an array, is among candidates
+func containsValue(candidates []interface{}, value interface{}) bool {
+	for _, candidate := range candidates {
+		for _, v := range arrayOrValue(value) {
+			if valuesEqual(v, candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// arrayOrValue returns the elements of value if it is an array, and value alone otherwise
+func arrayOrValue(value interface{}) []interface{} {
+	if array, ok := value.([]interface{}); ok {
+		return array
+	}
+	return []interface{}{value}
+}
+
+// valuesEqual returns whether a and b are equal. Missing values are never equal to anything, not even to
+// each other, so that a rule comparing two absent fields does not match
+func valuesEqual(a, b interface{}) bool {
+	if a == missing || b == missing {
+		return false
+	}
+	if cmp, ok := compareValues(a, b); ok {
+		return cmp == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues orders two numbers or two strings
+func compareValues(a, b interface{}) (int, bool) {
+	if x, ok := a.(float64); ok {
+		y, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if x, ok := a.(string); ok {
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	}
+
+	return 0, false
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil, missingValue:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+// ReadJSONDocument parses a JSON object given inline, or read from a file when prefixed with @
+func ReadJSONDocument(value string) (map[string]interface{}, error) {
+	data := []byte(value)
+	if strings.HasPrefix(value, "@") {
+		var err error
+		if data, err = ioutil.ReadFile(value[1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
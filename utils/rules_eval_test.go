@@ -0,0 +1,200 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func parseRule(t *testing.T, rule string) map[string]interface{} {
+	var parsed map[string]interface{}
+	u.So(t, json.Unmarshal([]byte(rule), &parsed), gc.ShouldBeNil)
+	return parsed
+}
+
+func TestFindNamespaceRule(t *testing.T) {
+	service, err := utils.ReadLocalService("../testdata/rules_app", "mongodb-atlas")
+	u.So(t, err, gc.ShouldBeNil)
+
+	t.Run("should find the rule of a namespace", func(t *testing.T) {
+		rule, err := utils.FindNamespaceRule(service, "blog.posts")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, rule["collection"], gc.ShouldEqual, "posts")
+	})
+
+	t.Run("should fail for an unknown namespace", func(t *testing.T) {
+		_, err := utils.FindNamespaceRule(service, "blog.drafts")
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, "no rule found for namespace blog.drafts")
+	})
+
+	t.Run("should fail for a service that is not MongoDB", func(t *testing.T) {
+		httpService, err := utils.ReadLocalService("../testdata/rules_app", "http")
+		u.So(t, err, gc.ShouldBeNil)
+
+		_, err = utils.FindNamespaceRule(httpService, "blog.posts")
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, `service "http" is of type http`)
+	})
+}
+
+func TestEvaluateRule(t *testing.T) {
+	rule := parseRule(t, `{
+		"roles": [
+			{
+				"name": "owner",
+				"apply_when": {"owner_id": "%%user.id"},
+				"fields": {
+					"title": {"write": true},
+					"notes": {"read": {"%%user.data.role": {"%in": ["editor", "admin"]}}}
+				},
+				"additional_fields": {"read": true},
+				"insert": true,
+				"delete": {"%%root.locked": {"%exists": false}}
+			},
+			{
+				"name": "reader",
+				"apply_when": {"%or": [{"%%user.data.age": {"$gte": 18}}, {"%%values.public": true}]},
+				"read": {"%%root.published": true},
+				"write": false
+			}
+		]
+	}`)
+
+	evaluate := func(action, user, doc string, values map[string]interface{}) *utils.RuleEvaluation {
+		request := utils.RuleRequest{Action: action, Values: values}
+		u.So(t, json.Unmarshal([]byte(user), &request.User), gc.ShouldBeNil)
+		u.So(t, json.Unmarshal([]byte(doc), &request.Doc), gc.ShouldBeNil)
+
+		evaluation, err := utils.EvaluateRule(rule, request)
+		u.So(t, err, gc.ShouldBeNil)
+		return evaluation
+	}
+
+	t.Run("should evaluate field permissions of the first role that applies", func(t *testing.T) {
+		evaluation := evaluate(utils.RuleActionRead, `{"id": "u1", "data": {"role": "viewer"}}`, `{"owner_id": "u1", "title": "a", "notes": "b"}`, nil)
+		u.So(t, evaluation.Role, gc.ShouldEqual, "owner")
+		u.So(t, evaluation.Allowed, gc.ShouldBeFalse)
+		u.So(t, evaluation.Fields, gc.ShouldResemble, []utils.FieldPermission{
+			{Field: "notes", Allowed: false, Source: "fields.notes"},
+			{Field: "owner_id", Allowed: true, Source: "additional_fields"},
+			{Field: "title", Allowed: true, Source: "fields.title"},
+		})
+
+		evaluation = evaluate(utils.RuleActionRead, `{"id": "u1", "data": {"role": "editor"}}`, `{"owner_id": "u1", "notes": "b"}`, nil)
+		u.So(t, evaluation.Allowed, gc.ShouldBeTrue)
+	})
+
+	t.Run("should require write access to every field to insert", func(t *testing.T) {
+		evaluation := evaluate(utils.RuleActionInsert, `{"id": "u1"}`, `{"owner_id": "u1", "title": "a"}`, nil)
+		u.So(t, evaluation.Allowed, gc.ShouldBeFalse)
+		u.So(t, evaluation.Source, gc.ShouldEqual, "insert and field permissions")
+		u.So(t, evaluation.Fields[0], gc.ShouldResemble, utils.FieldPermission{Field: "owner_id", Allowed: false, Source: "additional_fields"})
+	})
+
+	t.Run("should evaluate document-level permissions", func(t *testing.T) {
+		evaluation := evaluate(utils.RuleActionDelete, `{"id": "u1"}`, `{"owner_id": "u1"}`, nil)
+		u.So(t, evaluation.Allowed, gc.ShouldBeTrue)
+
+		evaluation = evaluate(utils.RuleActionDelete, `{"id": "u1"}`, `{"owner_id": "u1", "locked": true}`, nil)
+		u.So(t, evaluation.Allowed, gc.ShouldBeFalse)
+
+		evaluation = evaluate(utils.RuleActionRead, `{"id": "u2", "data": {"age": 30}}`, `{"owner_id": "u1", "published": true}`, nil)
+		u.So(t, evaluation.Role, gc.ShouldEqual, "reader")
+		u.So(t, evaluation.Allowed, gc.ShouldBeTrue)
+		u.So(t, evaluation.Source, gc.ShouldEqual, "document-level read")
+	})
+
+	t.Run("should resolve values and report when no role applies", func(t *testing.T) {
+		evaluation := evaluate(utils.RuleActionRead, `{"id": "u2"}`, `{"owner_id": "u1"}`, nil)
+		u.So(t, evaluation.Role, gc.ShouldBeEmpty)
+		u.So(t, evaluation.Allowed, gc.ShouldBeFalse)
+
+		evaluation = evaluate(utils.RuleActionRead, `{"id": "u2"}`, `{"owner_id": "u1"}`, map[string]interface{}{"public": true})
+		u.So(t, evaluation.Role, gc.ShouldEqual, "reader")
+	})
+
+	t.Run("should call functions", func(t *testing.T) {
+		rule := parseRule(t, `{"roles": [{"name": "fn", "apply_when": {"%function": {"name": "isAdmin", "arguments": ["%%user.id"]}}, "delete": true}]}`)
+
+		var calledWith []interface{}
+		request := utils.RuleRequest{
+			Action: utils.RuleActionDelete,
+			User:   map[string]interface{}{"id": "u1"},
+			CallFunction: func(name string, args []interface{}) (interface{}, error) {
+				calledWith = args
+				return name == "isAdmin", nil
+			},
+		}
+
+		evaluation, err := utils.EvaluateRule(rule, request)
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, evaluation.Role, gc.ShouldEqual, "fn")
+		u.So(t, evaluation.Allowed, gc.ShouldBeTrue)
+		u.So(t, calledWith, gc.ShouldResemble, []interface{}{"u1"})
+	})
+
+	t.Run("should never match missing values", func(t *testing.T) {
+		rule := parseRule(t, `{"roles": [{"name": "team", "apply_when": {"%%root.team_id": "%%user.data.team_id"}, "delete": true}]}`)
+
+		evaluation, err := utils.EvaluateRule(rule, utils.RuleRequest{
+			Action: utils.RuleActionDelete,
+			User:   map[string]interface{}{"id": "u1"},
+			Doc:    map[string]interface{}{},
+		})
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, evaluation.Role, gc.ShouldBeEmpty)
+		u.So(t, evaluation.Allowed, gc.ShouldBeFalse)
+	})
+
+	t.Run("should evaluate every query operator accepted by the linter", func(t *testing.T) {
+		doc := map[string]interface{}{
+			"name": "Alice",
+			"tags": []interface{}{"admin", "staff"},
+			"members": []interface{}{
+				map[string]interface{}{"id": "u1", "role": "owner"},
+				map[string]interface{}{"id": "u2", "role": "viewer"},
+			},
+		}
+
+		for _, tc := range []struct {
+			expression string
+			expected   bool
+		}{
+			{`{"name": {"$regex": "^ali", "$options": "i"}}`, true},
+			{`{"name": {"$regex": "^ali"}}`, false},
+			{`{"tags": {"$regex": "^adm"}}`, true},
+			{`{"members": {"$elemMatch": {"id": "%%user.id", "role": "owner"}}}`, true},
+			{`{"members": {"$elemMatch": {"id": "u2", "role": "owner"}}}`, false},
+			{`{"tags": {"$elemMatch": {"$eq": "staff"}}}`, true},
+			{`{"tags": {"$all": ["staff", "admin"], "$size": 2}}`, true},
+			{`{"tags": {"$all": ["staff", "guest"]}}`, false},
+			{`{"name": {"$not": {"$regex": "^B"}}}`, true},
+			{`{"missing": {"$ne": "x"}}`, true},
+		} {
+			t.Run(tc.expression, func(t *testing.T) {
+				rule := parseRule(t, `{"roles": [{"name": "role", "apply_when": `+tc.expression+`, "delete": true}]}`)
+				evaluation, err := utils.EvaluateRule(rule, utils.RuleRequest{
+					Action: utils.RuleActionDelete,
+					User:   map[string]interface{}{"id": "u1"},
+					Doc:    doc,
+				})
+				u.So(t, err, gc.ShouldBeNil)
+				u.So(t, evaluation.Allowed, gc.ShouldEqual, tc.expected)
+			})
+		}
+	})
+
+	t.Run("should reject unknown actions and expansions", func(t *testing.T) {
+		_, err := utils.EvaluateRule(rule, utils.RuleRequest{Action: "update"})
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, `unknown action "update"`)
+
+		_, err = utils.EvaluateRule(parseRule(t, `{"roles": [{"apply_when": {"%%usr.id": "x"}}]}`), utils.RuleRequest{Action: utils.RuleActionRead})
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldEqual, `roles[0].apply_when: unknown expansion "%%usr.id"`)
+	})
+}
@@ -0,0 +1,96 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func newRulesApp(t *testing.T, serviceType, rule string) string {
+	dir, err := ioutil.TempDir("", "stitch-rules")
+	u.So(t, err, gc.ShouldBeNil)
+
+	serviceDir := filepath.Join(dir, "services", "svc")
+	u.So(t, os.MkdirAll(filepath.Join(serviceDir, "rules"), 0700), gc.ShouldBeNil)
+	u.So(t, ioutil.WriteFile(filepath.Join(serviceDir, "config.json"), []byte(`{"name": "svc", "type": "`+serviceType+`"}`), 0600), gc.ShouldBeNil)
+	u.So(t, ioutil.WriteFile(filepath.Join(serviceDir, "rules", "rule.json"), []byte(rule), 0600), gc.ShouldBeNil)
+
+	return dir
+}
+
+func lintMessages(t *testing.T, serviceType, rule string) []string {
+	dir := newRulesApp(t, serviceType, rule)
+	defer os.RemoveAll(dir)
+
+	issues, err := utils.LintRules(dir)
+	u.So(t, err, gc.ShouldBeNil)
+
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, issue.Location+": "+issue.Message)
+	}
+	return messages
+}
+
+func TestLintRules(t *testing.T) {
+	t.Run("should accept valid operators and expansions", func(t *testing.T) {
+		u.So(t, lintMessages(t, "mongodb-atlas", `{
+			"database": "db",
+			"collection": "coll",
+			"roles": [{
+				"name": "role",
+				"apply_when": {"%%user.id": {"%in": "%%root.members"}},
+				"read": {"%%values.readers": {"%exists": true}},
+				"write": {"%%true": {"%function": {"name": "canWrite", "arguments": ["%%root", "%%prevRoot"]}}},
+				"insert": {"%%root.count": {"$lte": 10}},
+				"delete": {"%not": {"%%root.locked": true}}
+			}],
+			"filters": [{"match_expression": {"tags": {"$all": ["a"], "$size": 2, "$elemMatch": {"$regex": "^a", "$options": "i"}, "$not": {"$size": 0}}}}]
+		}`), gc.ShouldBeEmpty)
+	})
+
+	t.Run("should report malformed operators", func(t *testing.T) {
+		u.So(t, lintMessages(t, "mongodb-atlas", `{
+			"database": "db",
+			"collection": "coll",
+			"roles": [{
+				"name": "role",
+				"apply_when": {"%%user.id": {"%within": []}},
+				"read": {"%%root.a": {"$lessThan": 1}},
+				"write": {"%%values": {"%exists": "yes"}},
+				"delete": {"%function": {"arguments": "%%root"}}
+			}],
+			"filters": [{"match_expression": {"$or": [{"a": 1}]}}]
+		}`), gc.ShouldResemble, []string{
+			`roles[0].apply_when.%%user.id.%within: unknown operator "%within"`,
+			`roles[0].read.%%root.a.$lessThan: unknown query operator "$lessThan"`,
+			`roles[0].write: %%values must be followed by the name of a value`,
+			`roles[0].write.%%values.%exists: %exists expects a boolean`,
+			`roles[0].delete.%function: %function requires the name of a function`,
+			`roles[0].delete.%function.arguments: the arguments of %function must be an array`,
+			`filters[0].match_expression.$or: unknown query operator "$or", did you mean "%or"?`,
+		})
+	})
+
+	t.Run("should check namespaces against the service type", func(t *testing.T) {
+		u.So(t, lintMessages(t, "mongodb-atlas", `{"database": "my db", "collection": "system.users"}`), gc.ShouldResemble, []string{
+			`database: invalid database name "my db"`,
+			`collection: invalid collection name "system.users"`,
+		})
+
+		u.So(t, lintMessages(t, "twilio", `{"name": "sms", "database": "db", "actions": ["send"]}`), gc.ShouldResemble, []string{
+			`database: rules of twilio services do not apply to a namespace and cannot have database`,
+		})
+	})
+
+	t.Run("should report invalid JSON", func(t *testing.T) {
+		messages := lintMessages(t, "http", `{"name": "rule",`)
+		u.So(t, messages, gc.ShouldHaveLength, 1)
+		u.So(t, messages[0], gc.ShouldStartWith, ": invalid JSON")
+	})
+}
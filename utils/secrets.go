@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+const secretConfigName = "secret_config"
+
+// SecretReferences takes an io.Reader containing exported app zip data and returns, for every secret
+// referenced by the secret_config of a service or auth provider, a sorted description of each reference
+func SecretReferences(zipData io.Reader) (map[string][]string, error) {
+	b, err := ioutil.ReadAll(zipData)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	references := map[string][]string{}
+
+	for _, zipFile := range r.File {
+		parts := strings.Split(zipFile.Name, "/")
+		if zipFile.FileInfo().IsDir() || path.Ext(zipFile.Name) != jsonExt {
+			continue
+		}
+
+		var kind string
+		switch {
+		case len(parts) == 3 && parts[0] == servicesName && parts[2] == configName+jsonExt:
+			kind = "service"
+		case len(parts) == 2 && parts[0] == authProvidersName:
+			kind = "auth provider"
+		default:
+			continue
+		}
+
+		contents, err := readZipFile(zipFile)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc struct {
+			Name         string            `json:"name"`
+			SecretConfig map[string]string `json:"secret_config"`
+		}
+		if err := json.Unmarshal(contents, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", zipFile.Name, err)
+		}
+
+		for field, secretName := range doc.SecretConfig {
+			references[secretName] = append(references[secretName], fmt.Sprintf("%s '%s' (%s)", kind, doc.Name, field))
+		}
+	}
+
+	for _, refs := range references {
+		sort.Strings(refs)
+	}
+
+	return references, nil
+}
@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+var serviceNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// LocalService is a service stored within an app directory as services/<dir>/config.json, alongside its
+// rules and incoming webhooks
+type LocalService struct {
+	Dir    string
+	Config map[string]interface{}
+
+	// fs is the FileSystem the service was read from, and its rules and incoming webhooks are read from.
+	// It defaults to the local disk
+	fs FileSystem
+}
+
+func (ls *LocalService) fileSystem() FileSystem {
+	if ls.fs == nil {
+		return OSFileSystem{}
+	}
+	return ls.fs
+}
+
+// Name returns the name of the service
+func (ls *LocalService) Name() string {
+	name, _ := ls.Config["name"].(string)
+	return name
+}
+
+// Type returns the type of the service, such as mongodb-atlas or http
+func (ls *LocalService) Type() string {
+	serviceType, _ := ls.Config["type"].(string)
+	return serviceType
+}
+
+// ConfigPath returns the path of the config file of the service
+func (ls *LocalService) ConfigPath() string {
+	return filepath.Join(ls.Dir, configName+jsonExt)
+}
+
+// RulesDirectory returns the path of the rules directory of the service
+func (ls *LocalService) RulesDirectory() string {
+	return filepath.Join(ls.Dir, rulesName)
+}
+
+// RuleFiles returns the paths of the rule files of the service, sorted
+func (ls *LocalService) RuleFiles() ([]string, error) {
+	fileInfos, err := ls.fileSystem().ReadDir(ls.RulesDirectory())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || filepath.Ext(fileInfo.Name()) != jsonExt {
+			continue
+		}
+		paths = append(paths, filepath.Join(ls.RulesDirectory(), fileInfo.Name()))
+	}
+
+	return paths, nil
+}
+
+// ServiceTemplate describes how to scaffold the config of a service type. Fields are stored within the
+// config of the service while SecretFields are stored in secrets.json
+type ServiceTemplate struct {
+	Type         string
+	Config       map[string]interface{}
+	Fields       []TemplateField
+	SecretFields []TemplateField
+}
+
+// ServiceTemplates lists the service types that can be scaffolded, keyed by type
+var ServiceTemplates = map[string]ServiceTemplate{
+	"mongodb-atlas": {
+		Type: "mongodb-atlas",
+		Fields: []TemplateField{
+			{Name: "clusterName", Description: "Name of the Atlas cluster to link", Default: "Cluster0"},
+		},
+	},
+	"mongodb": {
+		Type: "mongodb",
+		SecretFields: []TemplateField{
+			{Name: "uri", Description: "Connection string of the MongoDB deployment"},
+		},
+	},
+	"http": {
+		Type: "http",
+	},
+	"github": {
+		Type: "github",
+	},
+	"twilio": {
+		Type: "twilio",
+		Fields: []TemplateField{
+			{Name: "sid", Description: "Twilio account SID"},
+		},
+		SecretFields: []TemplateField{
+			{Name: "auth_token", Description: "Twilio auth token"},
+		},
+	},
+	"aws": {
+		Type: "aws",
+		Fields: []TemplateField{
+			{Name: "accessKeyId", Description: "AWS access key ID"},
+			{Name: "region", Description: "AWS region", Default: "us-east-1"},
+		},
+		SecretFields: []TemplateField{
+			{Name: "secretAccessKey", Description: "AWS secret access key"},
+		},
+	},
+	"gcm": {
+		Type: "gcm",
+		Fields: []TemplateField{
+			{Name: "senderId", Description: "Firebase Cloud Messaging sender ID"},
+		},
+		SecretFields: []TemplateField{
+			{Name: "apiKey", Description: "Firebase Cloud Messaging legacy server key"},
+		},
+	},
+	"mailgun": {
+		Type: "mailgun",
+		Fields: []TemplateField{
+			{Name: "domain", Description: "Mailgun domain"},
+		},
+		SecretFields: []TemplateField{
+			{Name: "apiKey", Description: "Mailgun API key"},
+		},
+	},
+}
+
+// ServiceTypes returns the service types that can be scaffolded, sorted
+func ServiceTypes() []string {
+	types := make([]string, 0, len(ServiceTemplates))
+	for serviceType := range ServiceTemplates {
+		types = append(types, serviceType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// NewLocalService builds a service of the template's type named name within the app directory at appPath,
+// with the given values of the template's fields
+func (st ServiceTemplate) NewLocalService(appPath, name string, fields map[string]string) (*LocalService, error) {
+	if !serviceNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid service name %q: only letters, digits, underscores and hyphens are allowed", name)
+	}
+
+	config := map[string]interface{}{}
+	for key, value := range st.Config {
+		config[key] = value
+	}
+	for key, value := range fields {
+		config[key] = value
+	}
+
+	return &LocalService{
+		Dir: filepath.Join(ServicesDirectory(appPath), name),
+		Config: map[string]interface{}{
+			"name":   name,
+			"type":   st.Type,
+			"config": config,
+		},
+	}, nil
+}
+
+// WriteLocalService writes the config of the service, creating its directory along with empty rules
+// and incoming webhooks directories
+func WriteLocalService(service *LocalService) error {
+	for _, dir := range []string{service.RulesDirectory(), service.IncomingWebhooksDirectory()} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+
+	return WriteJSONFile(service.ConfigPath(), service.Config)
+}
+
+// RemoveLocalService deletes the directory of the service along with its rules and incoming webhooks
+func RemoveLocalService(service *LocalService) error {
+	return os.RemoveAll(service.Dir)
+}
+
+// ServicesDirectory returns the path of the services directory within the app directory at appPath
+func ServicesDirectory(appPath string) string {
+	return filepath.Join(appPath, servicesName)
+}
+
+// ReadLocalServices loads the config of every service within the app directory at appPath, sorted by name
+func ReadLocalServices(appPath string) ([]*LocalService, error) {
+	return ReadLocalServicesFromFileSystem(OSFileSystem{}, appPath)
+}
+
+// ReadLocalServicesFromFileSystem loads the config of every service within the app directory at appPath
+// of fs, sorted by name
+func ReadLocalServicesFromFileSystem(fs FileSystem, appPath string) ([]*LocalService, error) {
+	fileInfos, err := fs.ReadDir(ServicesDirectory(appPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*LocalService{}, nil
+		}
+		return nil, err
+	}
+
+	services := []*LocalService{}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() {
+			continue
+		}
+
+		service := &LocalService{Dir: filepath.Join(ServicesDirectory(appPath), fileInfo.Name()), fs: fs}
+		if err := readAndUnmarshalJSONFrom(fs, service.ConfigPath(), &service.Config); err != nil {
+			return nil, err
+		}
+
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name() < services[j].Name() })
+
+	return services, nil
+}
+
+// ReadLocalService loads the service with the given name from the app directory at appPath
+func ReadLocalService(appPath, name string) (*LocalService, error) {
+	services, err := ReadLocalServices(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range services {
+		if service.Name() == name {
+			return service, nil
+		}
+	}
+
+	return nil, fmt.Errorf("service %q not found in %s", name, ServicesDirectory(appPath))
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TemplateManifestFileName is the name of the file declaring the parameters of an app template
+const TemplateManifestFileName = "template.json"
+
+// TemplateParameterList is the type of parameters whose comma-separated value is substituted as an array
+const TemplateParameterList = "list"
+
+var (
+	templateParameterNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	templatePlaceholderPattern   = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+)
+
+// TemplateParameter is a value that has to be supplied when importing an app template. It is referenced
+// within the JSON files of the template as {{name}}
+type TemplateParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     string `json:"default,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// TemplateManifest declares the parameters of an app template
+type TemplateManifest struct {
+	Parameters []TemplateParameter `json:"parameters"`
+}
+
+// ReadTemplateManifest loads the template manifest of the app directory at appPath
+func ReadTemplateManifest(appPath string) (*TemplateManifest, error) {
+	path := filepath.Join(appPath, TemplateManifestFileName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s is not an app template: %s not found", appPath, TemplateManifestFileName)
+	}
+
+	var manifest TemplateManifest
+	if err := readAndUnmarshalJSONInto(path, &manifest); err != nil {
+		return nil, err
+	}
+
+	for _, param := range manifest.Parameters {
+		if !templateParameterNamePattern.MatchString(param.Name) {
+			return nil, fmt.Errorf("invalid template parameter name %q: only letters, digits and underscores are allowed", param.Name)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// Apply replaces every parameter placeholder within the JSON of the provided app data with the matching entry in values.
+// A string consisting of a single placeholder of a list parameter is replaced with an array. It returns an
+// error listing any placeholders that are not declared by the manifest or have no value
+func (tm *TemplateManifest) Apply(app map[string]interface{}, values map[string]string) error {
+	params := map[string]TemplateParameter{}
+	for _, param := range tm.Parameters {
+		params[param.Name] = param
+	}
+
+	undeclared, missing := map[string]struct{}{}, map[string]struct{}{}
+	walkTemplateStrings(app, nil, func(s string) interface{} {
+		if match := templatePlaceholderPattern.FindStringSubmatch(s); match != nil && match[0] == s {
+			if param, ok := params[match[1]]; ok && param.Type == TemplateParameterList {
+				value, ok := values[param.Name]
+				if !ok {
+					missing[param.Name] = struct{}{}
+					return s
+				}
+				return splitTemplateList(value)
+			}
+		}
+
+		return templatePlaceholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+			name := templatePlaceholderPattern.FindStringSubmatch(placeholder)[1]
+			if _, ok := params[name]; !ok {
+				undeclared[name] = struct{}{}
+				return placeholder
+			}
+
+			value, ok := values[name]
+			if !ok {
+				missing[name] = struct{}{}
+				return placeholder
+			}
+			return value
+		})
+	})
+
+	if len(undeclared) != 0 {
+		return fmt.Errorf("template references undeclared parameters: %s", strings.Join(sortedSet(undeclared), ", "))
+	}
+
+	if len(missing) != 0 {
+		return fmt.Errorf("missing values for template parameters: %s", strings.Join(sortedSet(missing), ", "))
+	}
+
+	return nil
+}
+
+func splitTemplateList(value string) []interface{} {
+	items := []interface{}{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// walkTemplateStrings calls fn with every string found within node, found at path within the app, replacing
+// it with the returned value. The source of functions and incoming webhooks is left untouched since
+// JavaScript may contain braces; other fields named "source" are walked like any other
+func walkTemplateStrings(node interface{}, path []string, fn func(s string) interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if key == sourceName && isSourceEntryPath(path) {
+				continue
+			}
+			v[key] = walkTemplateStrings(value, appendPath(path, key), fn)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = walkTemplateStrings(value, appendPath(path, templateListItem), fn)
+		}
+	case string:
+		return fn(v)
+	}
+
+	return node
+}
+
+// templateListItem stands for any index within a path walked by walkTemplateStrings
+const templateListItem = "[]"
+
+// isSourceEntryPath returns whether path leads to a function or an incoming webhook, whose source is
+// JavaScript
+func isSourceEntryPath(path []string) bool {
+	switch len(path) {
+	case 2:
+		return path[0] == functionsName && path[1] == templateListItem
+	case 4:
+		return path[0] == servicesName && path[1] == templateListItem &&
+			path[2] == incomingWebhooksName && path[3] == templateListItem
+	}
+	return false
+}
+
+func appendPath(path []string, part string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), part)
+}
+
+func sortedSet(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,105 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReadTemplateManifest(t *testing.T) {
+	t.Run("should read the parameters of a template", func(t *testing.T) {
+		manifest, err := utils.ReadTemplateManifest("../testdata/template_app")
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, manifest.Parameters, gc.ShouldHaveLength, 4)
+		u.So(t, manifest.Parameters[1], gc.ShouldResemble, utils.TemplateParameter{
+			Name:        "cluster_name",
+			Description: "Atlas cluster to link",
+			Default:     "Cluster0",
+		})
+	})
+
+	t.Run("should fail for directories without a template manifest", func(t *testing.T) {
+		_, err := utils.ReadTemplateManifest("../testdata/simple_app")
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldContainSubstring, "is not an app template: template.json not found")
+	})
+}
+
+func TestTemplateManifestApply(t *testing.T) {
+	manifest := &utils.TemplateManifest{
+		Parameters: []utils.TemplateParameter{
+			{Name: "app_name"},
+			{Name: "origins", Type: utils.TemplateParameterList},
+		},
+	}
+
+	t.Run("should substitute parameters within strings and lists but not function and webhook sources", func(t *testing.T) {
+		app := map[string]interface{}{
+			"name": "{{app_name}}",
+			"security": map[string]interface{}{
+				"allowed_request_origins": "{{ origins }}",
+			},
+			"values": []interface{}{
+				map[string]interface{}{"value": "https://{{app_name}}.example.com"},
+			},
+			"functions": []interface{}{
+				map[string]interface{}{"source": "exports = () => '{{app_name}}';"},
+			},
+			"services": []interface{}{
+				map[string]interface{}{
+					"config": map[string]interface{}{"config": map[string]interface{}{"source": "{{app_name}}-events"}},
+					"incoming_webhooks": []interface{}{
+						map[string]interface{}{"source": "exports = () => '{{app_name}}';"},
+					},
+				},
+			},
+			"triggers": []interface{}{
+				map[string]interface{}{"config": map[string]interface{}{"source": "{{app_name}}"}},
+			},
+		}
+
+		err := manifest.Apply(app, map[string]string{"app_name": "shop", "origins": "http://a.com, http://b.com"})
+		u.So(t, err, gc.ShouldBeNil)
+		u.So(t, app, gc.ShouldResemble, map[string]interface{}{
+			"name": "shop",
+			"security": map[string]interface{}{
+				"allowed_request_origins": []interface{}{"http://a.com", "http://b.com"},
+			},
+			"values": []interface{}{
+				map[string]interface{}{"value": "https://shop.example.com"},
+			},
+			"functions": []interface{}{
+				map[string]interface{}{"source": "exports = () => '{{app_name}}';"},
+			},
+			"services": []interface{}{
+				map[string]interface{}{
+					"config": map[string]interface{}{"config": map[string]interface{}{"source": "shop-events"}},
+					"incoming_webhooks": []interface{}{
+						map[string]interface{}{"source": "exports = () => '{{app_name}}';"},
+					},
+				},
+			},
+			"triggers": []interface{}{
+				map[string]interface{}{"config": map[string]interface{}{"source": "shop"}},
+			},
+		})
+	})
+
+	t.Run("should fail for undeclared parameters", func(t *testing.T) {
+		app := map[string]interface{}{"name": "{{app_name}}-{{env}}"}
+
+		err := manifest.Apply(app, map[string]string{"app_name": "shop"})
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldEqual, "template references undeclared parameters: env")
+	})
+
+	t.Run("should fail for parameters without a value", func(t *testing.T) {
+		app := map[string]interface{}{"name": "{{app_name}}", "origins": "{{origins}}"}
+
+		err := manifest.Apply(app, map[string]string{})
+		u.So(t, err, gc.ShouldNotBeNil)
+		u.So(t, err.Error(), gc.ShouldEqual, "missing values for template parameters: app_name, origins")
+	})
+}
@@ -1,6 +1,7 @@
 package testutils
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
@@ -10,7 +11,10 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -126,6 +130,60 @@ func NewResponseBody(data io.Reader) *ResponseBody {
 	return &rb
 }
 
+// NewZip returns a zip archive holding the given files, added in order of their names. Names ending in a
+// slash are added as directories
+func NewZip(files map[string]string) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range names {
+		fw, err := w.Create(name)
+		if err != nil {
+			panic(err)
+		}
+
+		if _, err := fw.Write([]byte(files[name])); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+
+	return buf.Bytes()
+}
+
+// ReadDirectoryFiles returns the contents of the files within dir keyed by their slash-separated paths
+// relative to dir
+func ReadDirectoryFiles(dir string) map[string]string {
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return files
+}
+
 // NewEmptyStorage creates a new empty MemoryStrategy
 func NewEmptyStorage() *storage.Storage {
 	return storage.New(NewMemoryStrategy([]byte{}))
@@ -185,12 +243,16 @@ func GenerateValidAccessToken() string {
 	return fmt.Sprintf("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.%s.RuF0KMEBAalfnsdMeozpQLQ_2hK27l9omxtTp8eF1yI", tokenString)
 }
 
+// mockStitchClientCallsMu guards the recorded calls of MockStitchClients, which may be made concurrently
+var mockStitchClientCallsMu sync.Mutex
+
 // MockStitchClient satisfies an api.StitchClient
 type MockStitchClient struct {
 	CreateEmptyAppFn                  func(groupID, appName string) (*models.App, error)
 	FetchAppByGroupIDAndClientAppIDFn func(groupID, clientAppID string) (*models.App, error)
 	FetchAppByClientAppIDFn           func(clientAppID string) (*models.App, error)
 	FetchAppsByGroupIDFn              func(groupID string) ([]*models.App, error)
+	FetchUserProfileFn                func() (*models.UserProfile, error)
 
 	ExportFn      func(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error)
 	ExportFnCalls [][]string
@@ -199,6 +261,56 @@ type MockStitchClient struct {
 	ImportFnCalls [][]string
 
 	DiffFn func(groupID, appID string, appData []byte, strategy string) ([]string, error)
+
+	FunctionsFn      func(groupID, appID string) ([]*models.Function, error)
+	FunctionFn       func(groupID, appID, functionID string) (*models.Function, error)
+	CreateFunctionFn func(groupID, appID string, function *models.Function) (*models.Function, error)
+	UpdateFunctionFn func(groupID, appID string, function *models.Function) error
+	DeleteFunctionFn func(groupID, appID, functionID string) error
+
+	ExecuteFunctionFn func(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error)
+
+	ValuesFn      func(groupID, appID string) ([]*models.Value, error)
+	ValueFn       func(groupID, appID, valueID string) (*models.Value, error)
+	CreateValueFn func(groupID, appID string, value *models.Value) (*models.Value, error)
+	UpdateValueFn func(groupID, appID string, value *models.Value) error
+	DeleteValueFn func(groupID, appID, valueID string) error
+
+	SecretsFn      func(groupID, appID string) ([]*models.Secret, error)
+	CreateSecretFn func(groupID, appID string, secret *models.Secret) (*models.Secret, error)
+	UpdateSecretFn func(groupID, appID string, secret *models.Secret) error
+	DeleteSecretFn func(groupID, appID, secretID string) error
+
+	TriggersFn      func(groupID, appID string) ([]*models.Trigger, error)
+	TriggerFn       func(groupID, appID, triggerID string) (map[string]interface{}, error)
+	UpdateTriggerFn func(groupID, appID, triggerID string, trigger map[string]interface{}) error
+	ResumeTriggerFn func(groupID, appID, triggerID string) error
+
+	LogsFn func(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error)
+
+	AppUsersFn              func(groupID, appID, providerType string) ([]*models.AppUser, error)
+	AppUserFn               func(groupID, appID, userID string) (*models.AppUser, error)
+	DeleteAppUserFn         func(groupID, appID, userID string) error
+	EnableAppUserFn         func(groupID, appID, userID string) error
+	DisableAppUserFn        func(groupID, appID, userID string) error
+	RevokeAppUserSessionsFn func(groupID, appID, userID string) error
+
+	AuthProvidersFn func(groupID, appID string) ([]*models.AuthProvider, error)
+	APIKeysFn       func(groupID, appID string) ([]*models.APIKey, error)
+	CreateAPIKeyFn  func(groupID, appID, name string) (*models.APIKey, error)
+	EnableAPIKeyFn  func(groupID, appID, keyID string) error
+	DisableAPIKeyFn func(groupID, appID, keyID string) error
+	DeleteAPIKeyFn  func(groupID, appID, keyID string) error
+}
+
+// NewMockAppStitchClient returns a MockStitchClient that finds any app by its client App ID, as the app
+// "app-id" of the project "group-id"
+func NewMockAppStitchClient() *MockStitchClient {
+	return &MockStitchClient{
+		FetchAppByClientAppIDFn: func(clientAppID string) (*models.App, error) {
+			return &models.App{GroupID: "group-id", ID: "app-id", ClientAppID: clientAppID}, nil
+		},
+	}
 }
 
 // Authenticate will authenticate a user given an auth.AuthenticationProvider
@@ -209,7 +321,9 @@ func (msc *MockStitchClient) Authenticate(authProvider auth.AuthenticationProvid
 // Export will download a Stitch app as a .zip
 func (msc *MockStitchClient) Export(groupID, appID string, isTemplated bool) (string, io.ReadCloser, error) {
 	if msc.ExportFn != nil {
+		mockStitchClientCallsMu.Lock()
 		msc.ExportFnCalls = append(msc.ExportFnCalls, []string{groupID, appID, strconv.FormatBool(isTemplated)})
+		mockStitchClientCallsMu.Unlock()
 		return msc.ExportFn(groupID, appID, isTemplated)
 	}
 
@@ -234,6 +348,15 @@ func (msc *MockStitchClient) FetchAppsByGroupID(groupID string) ([]*models.App,
 	return nil, errors.New("someone should test me")
 }
 
+// FetchUserProfile will fetch the profile of the current user
+func (msc *MockStitchClient) FetchUserProfile() (*models.UserProfile, error) {
+	if msc.FetchUserProfileFn != nil {
+		return msc.FetchUserProfileFn()
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
 // CreateEmptyApp does nothing
 func (msc *MockStitchClient) CreateEmptyApp(groupID, appName string) (*models.App, error) {
 	if msc.CreateEmptyAppFn != nil {
@@ -270,12 +393,301 @@ func (msc *MockStitchClient) FetchAppByClientAppID(clientAppID string) (*models.
 	return nil, api.ErrAppNotFound{clientAppID}
 }
 
+// Functions returns the functions of an app
+func (msc *MockStitchClient) Functions(groupID, appID string) ([]*models.Function, error) {
+	if msc.FunctionsFn != nil {
+		return msc.FunctionsFn(groupID, appID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// Function returns a single function of an app
+func (msc *MockStitchClient) Function(groupID, appID, functionID string) (*models.Function, error) {
+	if msc.FunctionFn != nil {
+		return msc.FunctionFn(groupID, appID, functionID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// CreateFunction creates a function within an app
+func (msc *MockStitchClient) CreateFunction(groupID, appID string, function *models.Function) (*models.Function, error) {
+	if msc.CreateFunctionFn != nil {
+		return msc.CreateFunctionFn(groupID, appID, function)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// UpdateFunction updates a function within an app
+func (msc *MockStitchClient) UpdateFunction(groupID, appID string, function *models.Function) error {
+	if msc.UpdateFunctionFn != nil {
+		return msc.UpdateFunctionFn(groupID, appID, function)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// DeleteFunction deletes a function from an app
+func (msc *MockStitchClient) DeleteFunction(groupID, appID, functionID string) error {
+	if msc.DeleteFunctionFn != nil {
+		return msc.DeleteFunctionFn(groupID, appID, functionID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// ExecuteFunction executes a function within an app
+func (msc *MockStitchClient) ExecuteFunction(groupID, appID, userID, name string, args []json.RawMessage) (*models.FunctionExecution, error) {
+	if msc.ExecuteFunctionFn != nil {
+		return msc.ExecuteFunctionFn(groupID, appID, userID, name, args)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// Values returns the values of an app
+func (msc *MockStitchClient) Values(groupID, appID string) ([]*models.Value, error) {
+	if msc.ValuesFn != nil {
+		return msc.ValuesFn(groupID, appID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// Value returns a value of an app
+func (msc *MockStitchClient) Value(groupID, appID, valueID string) (*models.Value, error) {
+	if msc.ValueFn != nil {
+		return msc.ValueFn(groupID, appID, valueID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// CreateValue creates a value within an app
+func (msc *MockStitchClient) CreateValue(groupID, appID string, value *models.Value) (*models.Value, error) {
+	if msc.CreateValueFn != nil {
+		return msc.CreateValueFn(groupID, appID, value)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// UpdateValue updates a value within an app
+func (msc *MockStitchClient) UpdateValue(groupID, appID string, value *models.Value) error {
+	if msc.UpdateValueFn != nil {
+		return msc.UpdateValueFn(groupID, appID, value)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// DeleteValue deletes a value from an app
+func (msc *MockStitchClient) DeleteValue(groupID, appID, valueID string) error {
+	if msc.DeleteValueFn != nil {
+		return msc.DeleteValueFn(groupID, appID, valueID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// Secrets returns the secrets of an app
+func (msc *MockStitchClient) Secrets(groupID, appID string) ([]*models.Secret, error) {
+	if msc.SecretsFn != nil {
+		return msc.SecretsFn(groupID, appID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// CreateSecret creates a secret within an app
+func (msc *MockStitchClient) CreateSecret(groupID, appID string, secret *models.Secret) (*models.Secret, error) {
+	if msc.CreateSecretFn != nil {
+		return msc.CreateSecretFn(groupID, appID, secret)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// UpdateSecret updates a secret within an app
+func (msc *MockStitchClient) UpdateSecret(groupID, appID string, secret *models.Secret) error {
+	if msc.UpdateSecretFn != nil {
+		return msc.UpdateSecretFn(groupID, appID, secret)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// DeleteSecret deletes a secret from an app
+func (msc *MockStitchClient) DeleteSecret(groupID, appID, secretID string) error {
+	if msc.DeleteSecretFn != nil {
+		return msc.DeleteSecretFn(groupID, appID, secretID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// Triggers returns the triggers of an app
+func (msc *MockStitchClient) Triggers(groupID, appID string) ([]*models.Trigger, error) {
+	if msc.TriggersFn != nil {
+		return msc.TriggersFn(groupID, appID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// Trigger returns a single trigger of an app
+func (msc *MockStitchClient) Trigger(groupID, appID, triggerID string) (map[string]interface{}, error) {
+	if msc.TriggerFn != nil {
+		return msc.TriggerFn(groupID, appID, triggerID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// UpdateTrigger updates a trigger within an app
+func (msc *MockStitchClient) UpdateTrigger(groupID, appID, triggerID string, trigger map[string]interface{}) error {
+	if msc.UpdateTriggerFn != nil {
+		return msc.UpdateTriggerFn(groupID, appID, triggerID, trigger)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// ResumeTrigger resumes a suspended trigger of an app
+func (msc *MockStitchClient) ResumeTrigger(groupID, appID, triggerID string) error {
+	if msc.ResumeTriggerFn != nil {
+		return msc.ResumeTriggerFn(groupID, appID, triggerID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// Logs returns a page of the logs of an app
+func (msc *MockStitchClient) Logs(groupID, appID string, query models.LogsQuery) (*models.LogsPage, error) {
+	if msc.LogsFn != nil {
+		return msc.LogsFn(groupID, appID, query)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// AppUsers returns the users of an app
+func (msc *MockStitchClient) AppUsers(groupID, appID, providerType string) ([]*models.AppUser, error) {
+	if msc.AppUsersFn != nil {
+		return msc.AppUsersFn(groupID, appID, providerType)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// AppUser returns a user of an app
+func (msc *MockStitchClient) AppUser(groupID, appID, userID string) (*models.AppUser, error) {
+	if msc.AppUserFn != nil {
+		return msc.AppUserFn(groupID, appID, userID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// DeleteAppUser deletes a user of an app
+func (msc *MockStitchClient) DeleteAppUser(groupID, appID, userID string) error {
+	if msc.DeleteAppUserFn != nil {
+		return msc.DeleteAppUserFn(groupID, appID, userID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// EnableAppUser enables a user of an app
+func (msc *MockStitchClient) EnableAppUser(groupID, appID, userID string) error {
+	if msc.EnableAppUserFn != nil {
+		return msc.EnableAppUserFn(groupID, appID, userID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// DisableAppUser disables a user of an app
+func (msc *MockStitchClient) DisableAppUser(groupID, appID, userID string) error {
+	if msc.DisableAppUserFn != nil {
+		return msc.DisableAppUserFn(groupID, appID, userID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// RevokeAppUserSessions revokes the sessions of a user of an app
+func (msc *MockStitchClient) RevokeAppUserSessions(groupID, appID, userID string) error {
+	if msc.RevokeAppUserSessionsFn != nil {
+		return msc.RevokeAppUserSessionsFn(groupID, appID, userID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// AuthProviders returns the auth providers of an app
+func (msc *MockStitchClient) AuthProviders(groupID, appID string) ([]*models.AuthProvider, error) {
+	if msc.AuthProvidersFn != nil {
+		return msc.AuthProvidersFn(groupID, appID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// APIKeys returns the API keys of an app
+func (msc *MockStitchClient) APIKeys(groupID, appID string) ([]*models.APIKey, error) {
+	if msc.APIKeysFn != nil {
+		return msc.APIKeysFn(groupID, appID)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// CreateAPIKey creates an API key of an app
+func (msc *MockStitchClient) CreateAPIKey(groupID, appID, name string) (*models.APIKey, error) {
+	if msc.CreateAPIKeyFn != nil {
+		return msc.CreateAPIKeyFn(groupID, appID, name)
+	}
+
+	return nil, errors.New("someone should test me")
+}
+
+// EnableAPIKey enables an API key of an app
+func (msc *MockStitchClient) EnableAPIKey(groupID, appID, keyID string) error {
+	if msc.EnableAPIKeyFn != nil {
+		return msc.EnableAPIKeyFn(groupID, appID, keyID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// DisableAPIKey disables an API key of an app
+func (msc *MockStitchClient) DisableAPIKey(groupID, appID, keyID string) error {
+	if msc.DisableAPIKeyFn != nil {
+		return msc.DisableAPIKeyFn(groupID, appID, keyID)
+	}
+
+	return errors.New("someone should test me")
+}
+
+// DeleteAPIKey deletes an API key of an app
+func (msc *MockStitchClient) DeleteAPIKey(groupID, appID, keyID string) error {
+	if msc.DeleteAPIKeyFn != nil {
+		return msc.DeleteAPIKeyFn(groupID, appID, keyID)
+	}
+
+	return errors.New("someone should test me")
+}
+
 // MockMDBClient satisfies a mdbcloud.Client
 type MockMDBClient struct {
 	WithAuthFn           func(username, apiKey string) mdbcloud.Client
 	GroupsFn             func() ([]mdbcloud.Group, error)
 	GroupByNameFn        func(string) (*mdbcloud.Group, error)
 	DeleteDatabaseUserFn func(groupId, username string) error
+	ClustersFn           func(groupID string) ([]mdbcloud.Cluster, error)
 }
 
 // WithAuth will authenticate a user given username and apiKey
@@ -307,6 +719,14 @@ func (mmc *MockMDBClient) DeleteDatabaseUser(groupID, username string) error {
 	return nil
 }
 
+// Clusters will return the clusters of a group
+func (mmc *MockMDBClient) Clusters(groupID string) ([]mdbcloud.Cluster, error) {
+	if mmc.ClustersFn != nil {
+		return mmc.ClustersFn(groupID)
+	}
+	return nil, errors.New("someone should test me")
+}
+
 // MongoDBCloudEnv represents ENV variables required for running tests against cloud
 type MongoDBCloudEnv struct {
 	CloudAPIBaseURL     string
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// LocalTrigger is a trigger stored within an app directory as triggers/<name>.json
+type LocalTrigger struct {
+	Path   string
+	Config map[string]interface{}
+}
+
+// Name returns the name of the trigger
+func (lt *LocalTrigger) Name() string {
+	name, _ := lt.Config["name"].(string)
+	return name
+}
+
+// Type returns the type of the trigger, such as DATABASE or AUTHENTICATION
+func (lt *LocalTrigger) Type() string {
+	triggerType, _ := lt.Config["type"].(string)
+	return triggerType
+}
+
+// FunctionName returns the name of the function the trigger calls
+func (lt *LocalTrigger) FunctionName() string {
+	functionName, _ := lt.Config["function_name"].(string)
+	return functionName
+}
+
+// Disabled returns whether the trigger is disabled
+func (lt *LocalTrigger) Disabled() bool {
+	disabled, _ := lt.Config["disabled"].(bool)
+	return disabled
+}
+
+// TriggersDirectory returns the path of the triggers directory within the app directory at appPath
+func TriggersDirectory(appPath string) string {
+	return filepath.Join(appPath, triggersName)
+}
+
+// ReadLocalTriggers loads every trigger within the app directory at appPath, sorted by name
+func ReadLocalTriggers(appPath string) ([]*LocalTrigger, error) {
+	return ReadLocalTriggersFromFileSystem(OSFileSystem{}, appPath)
+}
+
+// ReadLocalTriggersFromFileSystem loads every trigger within the app directory at appPath of fs, sorted by
+// name
+func ReadLocalTriggersFromFileSystem(fs FileSystem, appPath string) ([]*LocalTrigger, error) {
+	configs, err := readJSONConfigs(fs, TriggersDirectory(appPath))
+	if err != nil {
+		return nil, err
+	}
+
+	triggers := make([]*LocalTrigger, 0, len(configs))
+	for path, config := range configs {
+		triggers = append(triggers, &LocalTrigger{Path: path, Config: config})
+	}
+
+	sort.Slice(triggers, func(i, j int) bool { return triggers[i].Name() < triggers[j].Name() })
+
+	return triggers, nil
+}
+
+// ReadLocalTrigger loads the trigger with the given name from the app directory at appPath
+func ReadLocalTrigger(appPath, name string) (*LocalTrigger, error) {
+	triggers, err := ReadLocalTriggers(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, trigger := range triggers {
+		if trigger.Name() == name {
+			return trigger, nil
+		}
+	}
+
+	return nil, fmt.Errorf("trigger %q not found in %s", name, TriggersDirectory(appPath))
+}
+
+// WriteLocalTrigger writes the config of the trigger back to its file
+func WriteLocalTrigger(trigger *LocalTrigger) error {
+	return WriteJSONFile(trigger.Path, trigger.Config)
+}
@@ -34,7 +34,11 @@ var (
 
 // ReadAndUnmarshalInto unmarshals data from the given path into an interface{} using the provided marshalFn
 func ReadAndUnmarshalInto(marshalFn func(in []byte, out interface{}) error, path string, out interface{}) error {
-	data, err := ioutil.ReadFile(path)
+	return readAndUnmarshalInto(OSFileSystem{}, marshalFn, path, out)
+}
+
+func readAndUnmarshalInto(fs FileSystem, marshalFn func(in []byte, out interface{}) error, path string, out interface{}) error {
+	data, err := fs.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -50,6 +54,20 @@ func ReadAndUnmarshalInto(marshalFn func(in []byte, out interface{}) error, path
 	return nil
 }
 
+// WriteJSONFile writes data to the file at the given path as indented JSON, creating any missing parent directories
+func WriteJSONFile(path string, data interface{}) error {
+	contents, err := json.MarshalIndent(data, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory %q: %s", filepath.Dir(path), err)
+	}
+
+	return ioutil.WriteFile(path, append(contents, '\n'), 0600)
+}
+
 const maxDirectoryContainSearchDepth = 8
 
 // GetDirectoryContainingFile searches upwards for a valid Stitch app directory
@@ -77,7 +95,12 @@ func GetDirectoryContainingFile(wd, filename string) (string, error) {
 
 // WriteZipToDir takes a destination and an io.Reader containing zip data and unpacks it
 func WriteZipToDir(dest string, zipData io.Reader, overwrite bool) error {
-	if _, err := os.Open(dest); !overwrite && err == nil {
+	return WriteZipToFileSystem(OSFileSystem{}, dest, zipData, overwrite)
+}
+
+// WriteZipToFileSystem unpacks the zip data into the dest directory of fs
+func WriteZipToFileSystem(fs WritableFileSystem, dest string, zipData io.Reader, overwrite bool) error {
+	if _, err := fs.Stat(dest); !overwrite && err == nil {
 		return fmt.Errorf("failed to create directory %q: directory already exists", dest)
 	}
 
@@ -91,66 +114,82 @@ func WriteZipToDir(dest string, zipData io.Reader, overwrite bool) error {
 		return err
 	}
 
-	err = os.MkdirAll(dest, os.ModePerm)
-	if err != nil {
+	if err := fs.MkdirAll(dest, os.ModePerm); err != nil {
 		return fmt.Errorf("failed to create directory %q: %s", dest, err)
 	}
 
 	for _, zipFile := range r.File {
-		if err := processFile(filepath.Join(dest, zipFile.Name), zipFile); err != nil {
+		if err := processFile(fs, filepath.Join(dest, zipFile.Name), zipFile); err != nil {
 			return err
 		}
 	}
 
-	return err
+	return nil
 }
 
-func processFile(path string, zipFile *zip.File) error {
-	fileData, err := zipFile.Open()
+func processFile(fs WritableFileSystem, path string, zipFile *zip.File) error {
+	if zipFile.FileInfo().IsDir() {
+		if err := fs.MkdirAll(path, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create sub-directory %q: %s", path, err)
+		}
+		return nil
+	}
+
+	contents, err := readZipFile(zipFile)
 	if err != nil {
 		return fmt.Errorf("failed to extract file %q: %s", path, err)
 	}
-	defer fileData.Close()
 
-	if zipFile.FileInfo().IsDir() {
-		err = os.MkdirAll(path, os.ModePerm)
-		if err != nil {
-			return fmt.Errorf("failed to create sub-directory %q: %s", path, err)
-		}
-	} else {
-		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, zipFile.Mode())
-		if err != nil {
-			return fmt.Errorf("failed to create file %q: %s", path, err)
-		}
-		defer f.Close()
+	// not every archiver writes entries for the parent directories of files
+	if err := fs.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create sub-directory %q: %s", filepath.Dir(path), err)
+	}
 
-		_, err = io.Copy(f, fileData)
-		if err != nil {
-			return fmt.Errorf("failed to extract file %q: %s", path, err)
-		}
+	if err := fs.WriteFile(path, contents, zipFile.Mode()); err != nil {
+		return fmt.Errorf("failed to create file %q: %s", path, err)
 	}
 
 	return nil
 }
 
+// UnmarshalFromZip unmarshals a Stitch app from the given exported zip data into a map[string]interface{}
+func UnmarshalFromZip(zipData io.Reader) (map[string]interface{}, error) {
+	b, err := ioutil.ReadAll(zipData)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := readZipArchive(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalFromFileSystem(fs, ".")
+}
+
 // UnmarshalFromDir unmarshals a Stitch app from the given directory into a map[string]interface{}
 func UnmarshalFromDir(path string) (map[string]interface{}, error) {
+	return UnmarshalFromFileSystem(OSFileSystem{}, path)
+}
+
+// UnmarshalFromFileSystem unmarshals a Stitch app from the given directory of fs into a map[string]interface{}
+func UnmarshalFromFileSystem(fs FileSystem, path string) (map[string]interface{}, error) {
 	app := map[string]interface{}{}
 
-	if err := readAndUnmarshalJSONInto(filepath.Join(path, appConfigName+jsonExt), &app); err != nil {
+	if err := readAndUnmarshalJSONFrom(fs, filepath.Join(path, appConfigName+jsonExt), &app); err != nil {
 		return app, err
 	}
 
-	if _, err := os.Stat(filepath.Join(path, secretsName+jsonExt)); err == nil {
+	if _, err := fs.Stat(filepath.Join(path, secretsName+jsonExt)); err == nil {
 		var secrets interface{}
-		if err := readAndUnmarshalJSONInto(filepath.Join(path, secretsName+jsonExt), &secrets); err != nil {
+		if err := readAndUnmarshalJSONFrom(fs, filepath.Join(path, secretsName+jsonExt), &secrets); err != nil {
 			return app, err
 		}
 
 		app[secretsName] = secrets
 	}
 
-	values, err := unmarshalJSONFiles(filepath.Join(path, valuesName))
+	values, err := unmarshalJSONFiles(fs, filepath.Join(path, valuesName))
 	if err != nil {
 		return app, err
 	}
@@ -159,7 +198,7 @@ func UnmarshalFromDir(path string) (map[string]interface{}, error) {
 		app[valuesName] = values
 	}
 
-	authProviders, err := unmarshalJSONFiles(filepath.Join(path, authProvidersName))
+	authProviders, err := unmarshalJSONFiles(fs, filepath.Join(path, authProvidersName))
 	if err != nil {
 		return app, err
 	}
@@ -168,7 +207,7 @@ func UnmarshalFromDir(path string) (map[string]interface{}, error) {
 		app[authProvidersName] = authProviders
 	}
 
-	functions, err := unmarshalFunctionDirectories(filepath.Join(path, functionsName))
+	functions, err := unmarshalFunctionDirectories(fs, filepath.Join(path, functionsName))
 	if err != nil {
 		return app, err
 	}
@@ -177,7 +216,7 @@ func UnmarshalFromDir(path string) (map[string]interface{}, error) {
 		app[functionsName] = functions
 	}
 
-	triggers, err := unmarshalJSONFiles(filepath.Join(path, triggersName))
+	triggers, err := unmarshalJSONFiles(fs, filepath.Join(path, triggersName))
 	if err != nil {
 		return app, err
 	}
@@ -186,7 +225,7 @@ func UnmarshalFromDir(path string) (map[string]interface{}, error) {
 		app[triggersName] = triggers
 	}
 
-	services, err := unmarshalServiceDirectories(filepath.Join(path, servicesName))
+	services, err := unmarshalServiceDirectories(fs, filepath.Join(path, servicesName))
 	if err != nil {
 		return app, err
 	}
@@ -196,8 +235,12 @@ func UnmarshalFromDir(path string) (map[string]interface{}, error) {
 	return app, nil
 }
 
-func unmarshalJSONFiles(path string) ([]interface{}, error) {
-	fileInfos, _ := ioutil.ReadDir(path)
+func unmarshalJSONFiles(fs FileSystem, path string) ([]interface{}, error) {
+	fileInfos, err := readEntityDir(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
 	files := make([]interface{}, 0, len(fileInfos))
 
 	for _, fileInfo := range fileInfos {
@@ -207,7 +250,7 @@ func unmarshalJSONFiles(path string) ([]interface{}, error) {
 		}
 
 		var f interface{}
-		if err := readAndUnmarshalJSONInto(jsonFilePath, &f); err != nil {
+		if err := readAndUnmarshalJSONFrom(fs, jsonFilePath, &f); err != nil {
 			return []interface{}{}, err
 		}
 
@@ -217,17 +260,21 @@ func unmarshalJSONFiles(path string) ([]interface{}, error) {
 	return files, nil
 }
 
-func unmarshalFunctionDirectories(path string) ([]interface{}, error) {
-	fileInfos, _ := ioutil.ReadDir(path)
+func unmarshalFunctionDirectories(fs FileSystem, path string) ([]interface{}, error) {
+	fileInfos, err := readEntityDir(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
 	directories := []interface{}{}
 
-	err := iterDirectories(func(info os.FileInfo, path string) error {
+	err = iterDirectories(func(info os.FileInfo, path string) error {
 		var config interface{}
-		if err := readAndUnmarshalJSONInto(filepath.Join(path, configName+jsonExt), &config); err != nil {
+		if err := readAndUnmarshalJSONFrom(fs, filepath.Join(path, configName+jsonExt), &config); err != nil {
 			return err
 		}
 
-		sourceBytes, err := ioutil.ReadFile(filepath.Join(path, sourceName+jsExt))
+		sourceBytes, err := fs.ReadFile(filepath.Join(path, sourceName+jsExt))
 		if err != nil {
 			return err
 		}
@@ -239,7 +286,7 @@ func unmarshalFunctionDirectories(path string) ([]interface{}, error) {
 		directories = append(directories, directory)
 
 		return nil
-	}, path, fileInfos)
+	}, fs, path, fileInfos)
 
 	if err != nil {
 		return nil, err
@@ -248,28 +295,32 @@ func unmarshalFunctionDirectories(path string) ([]interface{}, error) {
 	return directories, nil
 }
 
-func unmarshalServiceDirectories(path string) ([]interface{}, error) {
-	fileInfos, _ := ioutil.ReadDir(path)
+func unmarshalServiceDirectories(fs FileSystem, path string) ([]interface{}, error) {
+	fileInfos, err := readEntityDir(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
 	services := []interface{}{}
 
-	err := iterDirectories(func(info os.FileInfo, path string) error {
+	err = iterDirectories(func(info os.FileInfo, path string) error {
 		svc := map[string]interface{}{}
 
 		var config map[string]interface{}
-		if err := readAndUnmarshalJSONInto(filepath.Join(path, configName+jsonExt), &config); err != nil {
+		if err := readAndUnmarshalJSONFrom(fs, filepath.Join(path, configName+jsonExt), &config); err != nil {
 			return err
 		}
 
 		svc[configName] = config
 
-		incomingWebhooks, err := unmarshalFunctionDirectories(filepath.Join(path, incomingWebhooksName))
+		incomingWebhooks, err := unmarshalFunctionDirectories(fs, filepath.Join(path, incomingWebhooksName))
 		if err != nil {
 			return err
 		}
 
 		svc[incomingWebhooksName] = incomingWebhooks
 
-		rules, err := unmarshalJSONFiles(filepath.Join(path, rulesName))
+		rules, err := unmarshalJSONFiles(fs, filepath.Join(path, rulesName))
 		if err != nil {
 			return err
 		}
@@ -279,7 +330,7 @@ func unmarshalServiceDirectories(path string) ([]interface{}, error) {
 		services = append(services, svc)
 
 		return nil
-	}, path, fileInfos)
+	}, fs, path, fileInfos)
 
 	if err != nil {
 		return nil, err
@@ -288,10 +339,25 @@ func unmarshalServiceDirectories(path string) ([]interface{}, error) {
 	return services, nil
 }
 
-func iterDirectories(iterFn func(info os.FileInfo, path string) error, path string, fileInfos []os.FileInfo) error {
+// readEntityDir lists the directory of entities at path, which are optional and so may be missing
+func readEntityDir(fs FileSystem, path string) ([]os.FileInfo, error) {
+	fileInfos, err := fs.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return fileInfos, err
+}
+
+func iterDirectories(iterFn func(info os.FileInfo, path string) error, fs FileSystem, path string, fileInfos []os.FileInfo) error {
 	for _, fileInfo := range fileInfos {
 		fileNamePath := filepath.Join(path, fileInfo.Name())
-		if info, err := os.Stat(fileNamePath); !info.IsDir() || err != nil {
+		info, err := fs.Stat(fileNamePath)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
 			continue
 		}
 
@@ -304,7 +370,11 @@ func iterDirectories(iterFn func(info os.FileInfo, path string) error, path stri
 }
 
 func readAndUnmarshalJSONInto(path string, out interface{}) error {
-	if err := ReadAndUnmarshalInto(json.Unmarshal, path, out); err != nil {
+	return readAndUnmarshalJSONFrom(OSFileSystem{}, path, out)
+}
+
+func readAndUnmarshalJSONFrom(fs FileSystem, path string, out interface{}) error {
+	if err := readAndUnmarshalInto(fs, json.Unmarshal, path, out); err != nil {
 		return fmt.Errorf("failed to parse %s: %s", path, err)
 	}
 
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+var valueNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateValueName returns an error if name cannot be used as the name of a value, and so as the name of
+// its file
+func ValidateValueName(name string) error {
+	if !valueNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid value name %q: only letters, digits, underscores and hyphens are allowed", name)
+	}
+	return nil
+}
+
+// LocalValue is a value stored within an app directory as values/<name>.json
+type LocalValue struct {
+	Path   string
+	Config map[string]interface{}
+}
+
+// Name returns the name of the value
+func (lv *LocalValue) Name() string {
+	name, _ := lv.Config["name"].(string)
+	return name
+}
+
+// Value returns the contents of the value
+func (lv *LocalValue) Value() interface{} {
+	return lv.Config["value"]
+}
+
+// Private returns whether the value is marked as private in its config
+func (lv *LocalValue) Private() bool {
+	private, _ := lv.Config["private"].(bool)
+	return private
+}
+
+// ValuesDirectory returns the path of the values directory within the app directory at appPath
+func ValuesDirectory(appPath string) string {
+	return filepath.Join(appPath, valuesName)
+}
+
+// ReadLocalValues loads every value within the app directory at appPath, sorted by name
+func ReadLocalValues(appPath string) ([]*LocalValue, error) {
+	return ReadLocalValuesFromFileSystem(OSFileSystem{}, appPath)
+}
+
+// ReadLocalValuesFromFileSystem loads every value within the app directory at appPath of fs, sorted by name
+func ReadLocalValuesFromFileSystem(fs FileSystem, appPath string) ([]*LocalValue, error) {
+	configs, err := readJSONConfigs(fs, ValuesDirectory(appPath))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*LocalValue, 0, len(configs))
+	for path, config := range configs {
+		values = append(values, &LocalValue{Path: path, Config: config})
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Name() < values[j].Name() })
+
+	return values, nil
+}
+
+// readJSONConfigs loads every JSON file directly within dir of fs, keyed by path. A missing directory
+// contains no files
+func readJSONConfigs(fs FileSystem, dir string) (map[string]map[string]interface{}, error) {
+	fileInfos, err := fs.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]map[string]interface{}{}, nil
+		}
+		return nil, err
+	}
+
+	configs := map[string]map[string]interface{}{}
+	for _, fileInfo := range fileInfos {
+		path := filepath.Join(dir, fileInfo.Name())
+		if fileInfo.IsDir() || filepath.Ext(path) != jsonExt {
+			continue
+		}
+
+		config := map[string]interface{}{}
+		if err := readAndUnmarshalJSONFrom(fs, path, &config); err != nil {
+			return nil, err
+		}
+
+		configs[path] = config
+	}
+
+	return configs, nil
+}
+
+// ReadLocalValue loads the value with the given name from the app directory at appPath
+func ReadLocalValue(appPath, name string) (*LocalValue, error) {
+	values, err := ReadLocalValues(appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		if value.Name() == name {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("value %q not found in %s", name, ValuesDirectory(appPath))
+}
+
+// WriteLocalValue writes the config of the value to its file, defaulting to values/<name>.json
+// within the app directory at appPath
+func WriteLocalValue(appPath string, value *LocalValue) error {
+	if value.Path == "" {
+		if err := ValidateValueName(value.Name()); err != nil {
+			return err
+		}
+		value.Path = filepath.Join(ValuesDirectory(appPath), value.Name()+jsonExt)
+	}
+
+	return WriteJSONFile(value.Path, value.Config)
+}
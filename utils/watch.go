@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DirWatcher reports changes made to the files within a directory tree
+type DirWatcher interface {
+	// Changes returns a channel that receives the path of every changed file
+	Changes() <-chan string
+	// Errors returns a channel that receives errors encountered while watching
+	Errors() <-chan error
+	Close() error
+}
+
+// NewDirWatcher returns a DirWatcher for the directory tree rooted at root. It uses OS file
+// notifications when they are available and falls back to polling every pollInterval otherwise
+func NewDirWatcher(root string, pollInterval time.Duration) (DirWatcher, error) {
+	watcher, err := newNotifyDirWatcher(root)
+	if err == nil {
+		return watcher, nil
+	}
+
+	return NewPollingDirWatcher(root, pollInterval)
+}
+
+// IsIgnoredPath reports whether changes to the file at path should be ignored, such as
+// hidden files and editor swap or backup files
+func IsIgnoredPath(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, ".") ||
+		strings.HasSuffix(base, "~") ||
+		strings.HasSuffix(base, ".swp") ||
+		strings.HasSuffix(base, ".swx") ||
+		strings.HasPrefix(base, "#")
+}
+
+type notifyDirWatcher struct {
+	watcher *fsnotify.Watcher
+	changes chan string
+	errors  chan error
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newNotifyDirWatcher(root string) (*notifyDirWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := addDirectories(watcher, root); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	w := &notifyDirWatcher{
+		watcher: watcher,
+		changes: make(chan string),
+		errors:  make(chan error),
+		done:    make(chan struct{}),
+	}
+
+	go w.loop()
+
+	return w, nil
+}
+
+func (w *notifyDirWatcher) loop() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if IsIgnoredPath(event.Name) {
+				continue
+			}
+
+			// fsnotify does not watch recursively, so newly created directories must be added
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addDirectories(w.watcher, event.Name); err != nil {
+						w.sendError(err)
+					}
+				}
+			}
+
+			select {
+			case w.changes <- event.Name:
+			case <-w.done:
+				return
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.sendError(err)
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *notifyDirWatcher) sendError(err error) {
+	select {
+	case w.errors <- err:
+	case <-w.done:
+	}
+}
+
+func (w *notifyDirWatcher) Changes() <-chan string {
+	return w.changes
+}
+
+func (w *notifyDirWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+func (w *notifyDirWatcher) Close() error {
+	w.once.Do(func() { close(w.done) })
+	return w.watcher.Close()
+}
+
+func addDirectories(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		if path != root && IsIgnoredPath(path) {
+			return filepath.SkipDir
+		}
+
+		return watcher.Add(path)
+	})
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+type pollingDirWatcher struct {
+	root     string
+	interval time.Duration
+	changes  chan string
+	errors   chan error
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewPollingDirWatcher returns a DirWatcher that scans the directory tree rooted at root every interval
+func NewPollingDirWatcher(root string, interval time.Duration) (DirWatcher, error) {
+	state, err := scanDirectory(root)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &pollingDirWatcher{
+		root:     root,
+		interval: interval,
+		changes:  make(chan string),
+		errors:   make(chan error),
+		done:     make(chan struct{}),
+	}
+
+	go w.loop(state)
+
+	return w, nil
+}
+
+func (w *pollingDirWatcher) loop(previous map[string]fileState) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.done:
+			return
+		}
+
+		current, err := scanDirectory(w.root)
+		if err != nil {
+			select {
+			case w.errors <- err:
+			case <-w.done:
+				return
+			}
+			continue
+		}
+
+		for _, path := range changedPaths(previous, current) {
+			select {
+			case w.changes <- path:
+			case <-w.done:
+				return
+			}
+		}
+
+		previous = current
+	}
+}
+
+func (w *pollingDirWatcher) Changes() <-chan string {
+	return w.changes
+}
+
+func (w *pollingDirWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+func (w *pollingDirWatcher) Close() error {
+	w.once.Do(func() { close(w.done) })
+	return nil
+}
+
+func scanDirectory(root string) (map[string]fileState, error) {
+	state := map[string]fileState{}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != root && IsIgnoredPath(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.IsDir() {
+			state[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+
+		return nil
+	})
+
+	return state, err
+}
+
+func changedPaths(previous, current map[string]fileState) []string {
+	paths := []string{}
+
+	for path, state := range current {
+		if prev, ok := previous[path]; !ok || prev != state {
+			paths = append(paths, path)
+		}
+	}
+
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,63 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/10gen/stitch-cli/utils"
+	u "github.com/10gen/stitch-cli/utils/test"
+	gc "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDirWatchers(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		newWatcher  func(root string) (utils.DirWatcher, error)
+	}{
+		{
+			description: "with OS file notifications",
+			newWatcher: func(root string) (utils.DirWatcher, error) {
+				return utils.NewDirWatcher(root, 10*time.Millisecond)
+			},
+		},
+		{
+			description: "with polling",
+			newWatcher: func(root string) (utils.DirWatcher, error) {
+				return utils.NewPollingDirWatcher(root, 10*time.Millisecond)
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "stitch-watch")
+			u.So(t, err, gc.ShouldBeNil)
+			defer os.RemoveAll(dir)
+
+			u.So(t, os.MkdirAll(filepath.Join(dir, "functions", "function_a"), os.ModePerm), gc.ShouldBeNil)
+
+			watcher, err := tc.newWatcher(dir)
+			u.So(t, err, gc.ShouldBeNil)
+			defer watcher.Close()
+
+			t.Run("should report changed files", func(t *testing.T) {
+				path := filepath.Join(dir, "functions", "function_a", "source.js")
+				u.So(t, ioutil.WriteFile(path, []byte("exports = function() {};"), 0600), gc.ShouldBeNil)
+
+				select {
+				case changed := <-watcher.Changes():
+					u.So(t, changed, gc.ShouldEqual, path)
+				case <-time.After(5 * time.Second):
+					t.Fatal("timed out waiting for a change")
+				}
+			})
+		})
+	}
+
+	t.Run("should ignore hidden and editor backup files", func(t *testing.T) {
+		u.So(t, utils.IsIgnoredPath("functions/fn/.source.js.swp"), gc.ShouldBeTrue)
+		u.So(t, utils.IsIgnoredPath("functions/fn/source.js~"), gc.ShouldBeTrue)
+		u.So(t, utils.IsIgnoredPath("functions/fn/source.js"), gc.ShouldBeFalse)
+	})
+}
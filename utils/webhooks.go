@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Validation methods of incoming webhooks
+const (
+	WebhookValidationNone          = "NO_VALIDATION"
+	WebhookValidationQueryParam    = "SECRET_AS_QUERY_PARAM"
+	WebhookValidationVerifyPayload = "VERIFY_PAYLOAD"
+)
+
+// WebhookSecretQueryParam is the query parameter carrying the secret of webhooks validated with
+// SECRET_AS_QUERY_PARAM
+const WebhookSecretQueryParam = "secret"
+
+const githubServiceType = "github"
+
+// LocalWebhook is an incoming webhook stored within a service directory as incoming_webhooks/<dir>/config.json,
+// alongside its source.js
+type LocalWebhook struct {
+	Service *LocalService
+	Dir     string
+	Config  map[string]interface{}
+}
+
+// Name returns the name of the webhook
+func (lw *LocalWebhook) Name() string {
+	name, _ := lw.Config["name"].(string)
+	return name
+}
+
+func (lw *LocalWebhook) option(name string) string {
+	options, _ := lw.Config["options"].(map[string]interface{})
+	value, _ := options[name].(string)
+	return value
+}
+
+// HTTPMethod returns the HTTP method the webhook accepts, or ANY if it accepts every method
+func (lw *LocalWebhook) HTTPMethod() string {
+	if method := lw.option("httpMethod"); method != "" {
+		return method
+	}
+	if lw.Service.Type() == githubServiceType {
+		return "POST"
+	}
+	return "ANY"
+}
+
+// Secret returns the secret used to validate requests to the webhook
+func (lw *LocalWebhook) Secret() string {
+	return lw.option("secret")
+}
+
+// ValidationMethod returns how requests to the webhook are validated. GitHub webhooks always verify
+// the payload, while the webhooks of other services without an explicit method fall back to passing
+// their secret as a query parameter
+func (lw *LocalWebhook) ValidationMethod() string {
+	if method := lw.option("validationMethod"); method != "" {
+		return method
+	}
+
+	switch {
+	case lw.Secret() == "":
+		return WebhookValidationNone
+	case lw.Service.Type() == githubServiceType:
+		return WebhookValidationVerifyPayload
+	}
+	return WebhookValidationQueryParam
+}
+
+// SignatureHeader returns the header and value that sign body with secret for webhooks validated with
+// VERIFY_PAYLOAD. GitHub webhooks are signed with HMAC-SHA1 in X-Hub-Signature, others with HMAC-SHA256
+// in X-Hook-Signature
+func (lw *LocalWebhook) SignatureHeader(body []byte, secret string) (string, string) {
+	header, prefix, hashFn := "X-Hook-Signature", "sha256=", sha256.New
+	if lw.Service.Type() == githubServiceType {
+		header, prefix, hashFn = "X-Hub-Signature", "sha1=", func() hash.Hash { return sha1.New() }
+	}
+
+	mac := hmac.New(hashFn, []byte(secret))
+	mac.Write(body)
+
+	return header, prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// IncomingWebhooksDirectory returns the path of the incoming webhooks directory of the service
+func (ls *LocalService) IncomingWebhooksDirectory() string {
+	return filepath.Join(ls.Dir, incomingWebhooksName)
+}
+
+// Webhooks loads the incoming webhooks of the service, sorted by name
+func (ls *LocalService) Webhooks() ([]*LocalWebhook, error) {
+	fileInfos, err := ls.fileSystem().ReadDir(ls.IncomingWebhooksDirectory())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*LocalWebhook{}, nil
+		}
+		return nil, err
+	}
+
+	webhooks := []*LocalWebhook{}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() {
+			continue
+		}
+
+		webhook := &LocalWebhook{Service: ls, Dir: filepath.Join(ls.IncomingWebhooksDirectory(), fileInfo.Name())}
+		if err := readAndUnmarshalJSONFrom(ls.fileSystem(), filepath.Join(webhook.Dir, configName+jsonExt), &webhook.Config); err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].Name() < webhooks[j].Name() })
+
+	return webhooks, nil
+}
+
+// ReadLocalWebhooks loads the incoming webhooks of every service within the app directory at appPath,
+// sorted by service name and then by name
+func ReadLocalWebhooks(appPath string) ([]*LocalWebhook, error) {
+	return ReadLocalWebhooksFromFileSystem(OSFileSystem{}, appPath)
+}
+
+// ReadLocalWebhooksFromFileSystem loads the incoming webhooks of every service within the app directory
+// at appPath of fs, sorted by service name and then by name
+func ReadLocalWebhooksFromFileSystem(fs FileSystem, appPath string) ([]*LocalWebhook, error) {
+	services, err := ReadLocalServicesFromFileSystem(fs, appPath)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []*LocalWebhook{}
+	for _, service := range services {
+		serviceWebhooks, err := service.Webhooks()
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, serviceWebhooks...)
+	}
+
+	return webhooks, nil
+}
+
+// ReadLocalWebhook loads the incoming webhook with the given name of the named service from the app
+// directory at appPath
+func ReadLocalWebhook(appPath, serviceName, name string) (*LocalWebhook, error) {
+	service, err := ReadLocalService(appPath, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := service.Webhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		if webhook.Name() == name {
+			return webhook, nil
+		}
+	}
+
+	return nil, fmt.Errorf("incoming webhook %q not found in %s", name, service.IncomingWebhooksDirectory())
+}